@@ -1,17 +1,47 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"github.com/gin-gonic/gin"
 	"log"
+	"net"
+	"os"
+	"sales/internal/auth"
+	"sales/internal/config"
 	"sales/internal/constants"
 	"sales/internal/database"
+	"sales/internal/grpcserver"
 	"sales/internal/handlers"
+	"sales/internal/repository"
+	"sales/internal/repository/memory"
+	"sales/internal/services"
+	"sales/internal/tenant"
+	"sales/pkg/cache"
 	"sales/pkg/cronjob"
+	"time"
+	_ "time/tzdata" // Embedded timezone database for the 'tz' parameter
 )
 
 func main() {
+	cfg, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	db, err := database.NewDatabase(constants.DatabaseName)
+	// `migrate up|down|status` manages the schema instead of serving
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := runMigrate(databaseConfig(cfg), args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	store, err := openStore(databaseConfig(cfg))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -22,12 +52,77 @@ func main() {
 	// Middleware for logging requests
 	router.Use(gin.Logger())
 
-	handlers.SetupRoutes(router, db)
+	authenticator, err := auth.Load(cfg.Auth)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !authenticator.Configured() {
+		log.Printf("No API keys or JWKS configured in auth.api_keys_file or auth.jwks_file, the API will reject every request")
+	}
+
+	tenants, err := tenant.Load(cfg.Ingestion.TenantsFile, tenant.Tenant{CSVFile: cfg.Ingestion.CSVFile, Schedule: cfg.Cron.Schedule})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rateLimits, err := handlers.LoadRateLimits(cfg.Server.RateLimitsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Share cached analytics results between instances when a Redis-compatible server is configured
+	ttl := time.Duration(cfg.Cache.TTL)
+	var cacheStore cache.Store = cache.NewLRU(cfg.Cache.Entries, ttl)
+	if cfg.Cache.RedisURL != "" {
+		redis, err := cache.NewRedis(cfg.Cache.RedisURL, constants.CacheKeyPrefix, ttl)
+		if err != nil {
+			log.Fatalf("Invalid cache.redis_url: %v", err)
+		}
+		if err := redis.Ping(context.Background()); err != nil {
+			log.Fatalf("Failed to reach the cache server: %v", err)
+		}
+		cacheStore = redis
+	}
+	svc := services.New(store, services.NewResultCache(cacheStore))
 
-	// Set up cron job in background
-	go cronjob.SetupCronJob(db)
+	if err := handlers.SetupRoutes(router, cfg, svc, authenticator, tenants, rateLimits); err != nil {
+		log.Fatal(err)
+	}
 
-	if err := router.Run(constants.APIServerPort); err != nil {
+	// Set up the tenants' cron jobs in background
+	go cronjob.SetupCronJob(svc.Refresh, tenants.Tenants())
+
+	// Serve the gRPC API next to the REST one, with the same credentials
+	grpcListener, err := net.Listen("tcp", cfg.Server.GRPCAddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go func() {
+		if err := grpcserver.NewServer(svc, authenticator, tenants).Serve(grpcListener); err != nil {
+			log.Fatalf("Failed to run gRPC server: %v", err)
+		}
+	}()
+
+	if err := router.Run(cfg.Server.HTTPAddr); err != nil {
 		log.Fatalf("Failed to run server: %v", err)
 	}
 }
+
+// databaseConfig returns the database settings of cfg.
+func databaseConfig(cfg config.Config) database.Config {
+	return database.Config{Driver: cfg.Database.Driver, DSN: cfg.Database.DSN}
+}
+
+// openStore returns the repositories of the configured database, migrated to this binary's schema,
+// or an empty in-memory store with the memory driver.
+func openStore(cfg database.Config) (repository.Store, error) {
+	if cfg.Driver == constants.DriverMemory {
+		log.Printf("Using the %s driver, data is lost on exit", constants.DriverMemory)
+		return memory.NewStore(), nil
+	}
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewSQLStore(db), nil
+}
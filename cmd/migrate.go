@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sales/internal/database"
+	"strconv"
+	"text/tabwriter"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status"
+
+// runMigrate runs the migrate command on the configured database: up applies pending migrations,
+// down reverts the latest ones (one by default) and status lists them.
+func runMigrate(cfg database.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	db, err := database.Open(cfg)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp(db)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s)\n", len(applied))
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps %q, expected a positive integer", args[1])
+			}
+		}
+		reverted, err := database.MigrateDown(db, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Reverted %d migration(s)\n", len(reverted))
+	case "status":
+		statuses, err := database.Status(db)
+		if err != nil {
+			return err
+		}
+		writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := status.AppliedAt
+			if appliedAt == "" {
+				appliedAt = "pending"
+			}
+			fmt.Fprintf(writer, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return writer.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Package auth authenticates callers of the REST, GraphQL and gRPC APIs with static API keys,
+// stored as SHA-256 hashes, or with HS256/RS256 JWTs verified against a local JWKS file.
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sales/internal/config"
+	"sales/internal/constants"
+	"sales/pkg/jwt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Principal is the caller a request was authenticated as. Tenant is the tenant its credentials are
+// bound to, empty when they may act for any tenant.
+type Principal struct {
+	Name   string
+	Roles  []string
+	Tenant string
+}
+
+// HasRole reports whether the principal holds one of roles; admins hold every role.
+func (p Principal) HasRole(roles ...string) bool {
+	for _, role := range p.Roles {
+		if role == constants.RoleAdmin || slices.Contains(roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey is a static key as stored in the API keys file: its hex SHA-256 hash, its roles and the
+// tenant it is bound to, if any.
+type APIKey struct {
+	Name   string   `json:"name"`
+	SHA256 string   `json:"sha256"`
+	Roles  []string `json:"roles"`
+	Tenant string   `json:"tenant"`
+
+	hash []byte
+}
+
+// Authenticator resolves the credential sent with a request to a principal.
+type Authenticator struct {
+	apiKeys  []APIKey
+	verifier *jwt.Verifier
+}
+
+// NewAuthenticator checks the hashes and roles of apiKeys. verifier may be nil to accept API keys only.
+func NewAuthenticator(apiKeys []APIKey, verifier *jwt.Verifier) (*Authenticator, error) {
+	for i := range apiKeys {
+		key := &apiKeys[i]
+		hash, err := hex.DecodeString(key.SHA256)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("API key %q: sha256 must be 64 hex characters", key.Name)
+		}
+		if err := checkRoles(key.Roles); err != nil {
+			return nil, fmt.Errorf("API key %q: %w", key.Name, err)
+		}
+		key.hash = hash
+	}
+	return &Authenticator{apiKeys: apiKeys, verifier: verifier}, nil
+}
+
+// Load builds an authenticator from the API keys file and JWKS file of cfg; either may be unset.
+func Load(cfg config.Auth) (*Authenticator, error) {
+	var apiKeys []APIKey
+	if path := cfg.APIKeysFile; path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &apiKeys); err != nil {
+			return nil, fmt.Errorf("invalid API keys file %s: %w", path, err)
+		}
+	}
+
+	var verifier *jwt.Verifier
+	if path := cfg.JWKSFile; path != "" {
+		keys, err := jwt.LoadKeySet(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS file %s: %w", path, err)
+		}
+		verifier = &jwt.Verifier{
+			Keys:     keys,
+			Issuer:   cfg.JWTIssuer,
+			Audience: cfg.JWTAudience,
+			Leeway:   constants.JWTLeeway,
+		}
+	}
+
+	return NewAuthenticator(apiKeys, verifier)
+}
+
+// Configured reports whether any credential can be accepted at all.
+func (a *Authenticator) Configured() bool {
+	return len(a.apiKeys) > 0 || (a.verifier != nil && len(a.verifier.Keys.Keys) > 0)
+}
+
+// Authenticate resolves an API key or a JWT. Failures wrap constants.ErrUnauthorized.
+func (a *Authenticator) Authenticate(credential string) (Principal, error) {
+	if credential == "" {
+		return Principal{}, constants.ErrUnauthorized
+	}
+
+	// JWTs are three dot separated segments, which API keys never contain
+	if strings.Count(credential, ".") == 2 && a.verifier != nil {
+		claims, err := a.verifier.Verify(credential, time.Now())
+		if err != nil {
+			return Principal{}, fmt.Errorf("%w: %v", constants.ErrUnauthorized, err)
+		}
+		var roles []string
+		if raw, ok := claims.Raw[constants.RolesClaim]; ok && json.Unmarshal(raw, &roles) != nil {
+			return Principal{}, fmt.Errorf("%w: '%s' claim must be a list of strings", constants.ErrUnauthorized, constants.RolesClaim)
+		}
+		var tenant string
+		if raw, ok := claims.Raw[constants.TenantClaim]; ok && json.Unmarshal(raw, &tenant) != nil {
+			return Principal{}, fmt.Errorf("%w: '%s' claim must be a string", constants.ErrUnauthorized, constants.TenantClaim)
+		}
+		return Principal{Name: claims.Subject, Roles: roles, Tenant: tenant}, nil
+	}
+
+	hash := sha256.Sum256([]byte(credential))
+	for _, key := range a.apiKeys {
+		if subtle.ConstantTimeCompare(hash[:], key.hash) == 1 {
+			return Principal{Name: key.Name, Roles: key.Roles, Tenant: key.Tenant}, nil
+		}
+	}
+	return Principal{}, constants.ErrUnauthorized
+}
+
+func checkRoles(roles []string) error {
+	for _, role := range roles {
+		if !slices.Contains(constants.Roles, role) {
+			return fmt.Errorf("unknown role %q, expected one of %s", role, strings.Join(constants.Roles, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sales/internal/constants"
+	"sales/pkg/jwt"
+	"slices"
+	"testing"
+	"time"
+)
+
+var secret = []byte("a shared secret of at least 32 bytes")
+
+func hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// signed returns an HS256 token with claims, expiring in an hour unless claims set exp.
+func signed(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	if _, ok := claims["exp"]; !ok {
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+	}
+	header, _ := json.Marshal(map[string]string{"alg": jwt.HS256})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(token))
+	return token + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newTestAuthenticator(t *testing.T) *Authenticator {
+	t.Helper()
+	authenticator, err := NewAuthenticator([]APIKey{
+		{Name: "dashboard", SHA256: hash("viewer-key"), Roles: []string{constants.RoleViewer}},
+		{Name: "acme-loader", SHA256: hash("acme-key"), Roles: []string{constants.RoleIngestor, constants.RoleAnalyst}, Tenant: "acme"},
+	}, &jwt.Verifier{Keys: &jwt.KeySet{Keys: []jwt.Key{{Alg: jwt.HS256, Secret: secret}}}, Leeway: constants.JWTLeeway})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return authenticator
+}
+
+func TestAuthenticate(t *testing.T) {
+	authenticator := newTestAuthenticator(t)
+
+	for _, test := range []struct {
+		name       string
+		credential string
+		want       Principal
+	}{
+		{"an unbound API key", "viewer-key", Principal{Name: "dashboard", Roles: []string{constants.RoleViewer}}},
+		{"an API key bound to a tenant", "acme-key", Principal{Name: "acme-loader", Roles: []string{constants.RoleIngestor, constants.RoleAnalyst}, Tenant: "acme"}},
+		{"a JWT with roles", signed(t, map[string]any{"sub": "alice", "roles": []string{constants.RoleAdmin}}), Principal{Name: "alice", Roles: []string{constants.RoleAdmin}}},
+		{"a JWT bound to a tenant", signed(t, map[string]any{"sub": "bob", "roles": []string{constants.RoleAnalyst}, "tenant": "acme"}), Principal{Name: "bob", Roles: []string{constants.RoleAnalyst}, Tenant: "acme"}},
+		{"a JWT without roles", signed(t, map[string]any{"sub": "carol"}), Principal{Name: "carol"}},
+	} {
+		got, err := authenticator.Authenticate(test.credential)
+		if err != nil || got.Name != test.want.Name || got.Tenant != test.want.Tenant || !slices.Equal(got.Roles, test.want.Roles) {
+			t.Errorf("%s: %+v, %v, want %+v", test.name, got, err, test.want)
+		}
+	}
+
+	for name, credential := range map[string]string{
+		"no credential":            "",
+		"an unknown API key":       "unknown-key",
+		"an expired JWT":           signed(t, map[string]any{"sub": "alice", "exp": time.Now().Add(-time.Hour).Unix()}),
+		"roles given as a string":  signed(t, map[string]any{"sub": "alice", "roles": constants.RoleAdmin}),
+		"a tenant given as a list": signed(t, map[string]any{"sub": "alice", "tenant": []string{"acme", "globex"}}),
+		"a JWT of another secret": func() string {
+			token := signed(t, map[string]any{"sub": "alice"})
+			return token[:len(token)-4] + "AAAA"
+		}(),
+	} {
+		if _, err := authenticator.Authenticate(credential); !errors.Is(err, constants.ErrUnauthorized) {
+			t.Errorf("%s: %v, want %v", name, err, constants.ErrUnauthorized)
+		}
+	}
+
+	// without a key set a JWT is just an unknown API key
+	apiKeysOnly, err := NewAuthenticator([]APIKey{{Name: "dashboard", SHA256: hash("viewer-key"), Roles: []string{constants.RoleViewer}}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := apiKeysOnly.Authenticate(signed(t, map[string]any{"sub": "alice"})); !errors.Is(err, constants.ErrUnauthorized) {
+		t.Errorf("a JWT without a key set: %v, want %v", err, constants.ErrUnauthorized)
+	}
+}
+
+func TestHasRole(t *testing.T) {
+	for _, test := range []struct {
+		roles  []string
+		wanted []string
+		want   bool
+	}{
+		{[]string{constants.RoleViewer}, []string{constants.RoleViewer, constants.RoleAnalyst}, true},
+		{[]string{constants.RoleViewer}, []string{constants.RoleAnalyst}, false},
+		{[]string{constants.RoleAdmin}, []string{constants.RoleIngestor}, true},
+		{nil, []string{constants.RoleViewer}, false},
+	} {
+		if got := (Principal{Roles: test.roles}).HasRole(test.wanted...); got != test.want {
+			t.Errorf("%v holding one of %v: %t, want %t", test.roles, test.wanted, got, test.want)
+		}
+	}
+}
+
+func TestNewAuthenticatorRejectsInvalidKeys(t *testing.T) {
+	for name, key := range map[string]APIKey{
+		"a short hash":    {Name: "short", SHA256: "abc", Roles: []string{constants.RoleViewer}},
+		"a non-hex hash":  {Name: "hex", SHA256: hash("key")[:62] + "zz", Roles: []string{constants.RoleViewer}},
+		"an unknown role": {Name: "role", SHA256: hash("key"), Roles: []string{"superuser"}},
+	} {
+		if _, err := NewAuthenticator([]APIKey{key}, nil); err == nil {
+			t.Errorf("%s was accepted", name)
+		}
+	}
+
+	if authenticator, _ := NewAuthenticator(nil, nil); authenticator.Configured() {
+		t.Error("an authenticator without keys reports being configured")
+	}
+}
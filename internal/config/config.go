@@ -0,0 +1,355 @@
+// Package config loads the settings of the application. Each setting has a default, which a YAML or
+// TOML file, the SALES_* environment variables and the command line flags override in turn.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sales/internal/constants"
+	"slices"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	cron "github.com/robfig/cron/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileEnv names the YAML (.yaml, .yml) or TOML (.toml) configuration file when the -config
+// flag is not given. Environment variables override the file and flags override both.
+const ConfigFileEnv = "SALES_CONFIG_FILE"
+
+// environment variables overriding the settings; an empty one restores the default
+const (
+	HTTPAddrEnv       = "SALES_HTTP_ADDR"        // REST listen address, e.g. :8080
+	GRPCAddrEnv       = "SALES_GRPC_ADDR"        // gRPC listen address, e.g. :9090
+	RateLimitsFileEnv = "SALES_RATE_LIMITS_FILE" // JSON overrides per route class, e.g. {"analytics": {"per_minute": 30, "burst": 10}}
+	DatabaseDriverEnv = "SALES_DB_DRIVER"        // sqlite (default), postgres or memory
+	DatabaseDSNEnv    = "SALES_DB_DSN"           // SQLite file path or PostgreSQL connection string
+	CSVFileEnv        = "SALES_CSV_FILE"         // CSV refreshed from by tenants that name none
+	TenantsFileEnv    = "SALES_TENANTS_FILE"     // JSON list of {id, csv_file, schedule}
+	CronScheduleEnv   = "SALES_CRON_SCHEDULE"    // refresh schedule of tenants that name none
+	APIKeysFileEnv    = "SALES_API_KEYS_FILE"    // JSON list of {name, sha256, roles}
+	JWKSFileEnv       = "SALES_JWKS_FILE"        // JSON Web Key Set verifying HS256 and RS256 JWTs
+	JWTIssuerEnv      = "SALES_JWT_ISSUER"       // required iss claim, unchecked when empty
+	JWTAudienceEnv    = "SALES_JWT_AUDIENCE"     // required aud claim, unchecked when empty
+	CacheRedisURLEnv  = "SALES_CACHE_REDIS_URL"  // Redis-compatible server shared by every instance, e.g. redis://localhost:6379/0
+	CacheEntriesEnv   = "SALES_CACHE_ENTRIES"    // results kept by the in-process cache
+	CacheTTLEnv       = "SALES_CACHE_TTL"        // how long results are kept, e.g. 10m
+)
+
+// Config holds every setting of the application.
+type Config struct {
+	Server    Server    `json:"server" yaml:"server" toml:"server"`
+	Database  Database  `json:"database" yaml:"database" toml:"database"`
+	Ingestion Ingestion `json:"ingestion" yaml:"ingestion" toml:"ingestion"`
+	Cron      Cron      `json:"cron" yaml:"cron" toml:"cron"`
+	Auth      Auth      `json:"auth" yaml:"auth" toml:"auth"`
+	Cache     Cache     `json:"cache" yaml:"cache" toml:"cache"`
+}
+
+// Server configures the REST and gRPC listeners and the rate limits of the REST API.
+type Server struct {
+	HTTPAddr       string `json:"http_addr" yaml:"http_addr" toml:"http_addr"`
+	GRPCAddr       string `json:"grpc_addr" yaml:"grpc_addr" toml:"grpc_addr"`
+	RateLimitsFile string `json:"rate_limits_file" yaml:"rate_limits_file" toml:"rate_limits_file"` // JSON overrides per route class
+}
+
+// Database selects the database the application stores its data in.
+type Database struct {
+	Driver string `json:"driver" yaml:"driver" toml:"driver"` // constants.DriverSQLite, constants.DriverPostgres or constants.DriverMemory
+	DSN    string `json:"dsn" yaml:"dsn" toml:"dsn"`          // file path for SQLite, connection string for PostgreSQL, unused in memory
+}
+
+// Ingestion names the files sales are refreshed from.
+type Ingestion struct {
+	CSVFile     string `json:"csv_file" yaml:"csv_file" toml:"csv_file"`             // CSV of tenants that name none
+	TenantsFile string `json:"tenants_file" yaml:"tenants_file" toml:"tenants_file"` // JSON list of tenants, only the default one when empty
+}
+
+// Cron schedules the automatic refreshes.
+type Cron struct {
+	Schedule string `json:"schedule" yaml:"schedule" toml:"schedule"` // schedule of tenants that name none
+}
+
+// Auth names the credentials the API accepts.
+type Auth struct {
+	APIKeysFile string `json:"api_keys_file" yaml:"api_keys_file" toml:"api_keys_file"`
+	JWKSFile    string `json:"jwks_file" yaml:"jwks_file" toml:"jwks_file"`
+	JWTIssuer   string `json:"jwt_issuer" yaml:"jwt_issuer" toml:"jwt_issuer"`       // required iss claim, unchecked when empty
+	JWTAudience string `json:"jwt_audience" yaml:"jwt_audience" toml:"jwt_audience"` // required aud claim, unchecked when empty
+}
+
+// Cache configures the analytics result cache.
+type Cache struct {
+	RedisURL string   `json:"redis_url" yaml:"redis_url" toml:"redis_url"` // shared Redis-compatible server, in process when empty
+	Entries  int      `json:"entries" yaml:"entries" toml:"entries"`       // results kept by the in-process cache
+	TTL      Duration `json:"ttl" yaml:"ttl" toml:"ttl"`
+}
+
+// Default returns the settings used when nothing overrides them. Relative paths are relative to
+// the working directory. The SQLite DSN is left empty and defaults once the driver is known.
+func Default() Config {
+	return Config{
+		Server:    Server{HTTPAddr: constants.DefaultHTTPAddr, GRPCAddr: constants.DefaultGRPCAddr},
+		Database:  Database{Driver: constants.DriverSQLite},
+		Ingestion: Ingestion{CSVFile: constants.DefaultCSVFile},
+		Cron:      Cron{Schedule: constants.DefaultCronSchedule},
+		Cache:     Cache{Entries: constants.CacheEntries, TTL: Duration(constants.CacheTTL)},
+	}
+}
+
+// setting is a setting overridden by an environment variable and a flag.
+type setting struct {
+	env   string
+	flag  string
+	usage string
+	value flag.Value
+}
+
+// settings binds the settings of cfg to their environment variables and flags.
+func settings(cfg *Config) []setting {
+	return []setting{
+		{HTTPAddrEnv, "http-addr", "REST listen address", (*stringValue)(&cfg.Server.HTTPAddr)},
+		{GRPCAddrEnv, "grpc-addr", "gRPC listen address", (*stringValue)(&cfg.Server.GRPCAddr)},
+		{RateLimitsFileEnv, "rate-limits-file", "JSON file overriding the rate limits of route classes", (*stringValue)(&cfg.Server.RateLimitsFile)},
+		{DatabaseDriverEnv, "db-driver", "database driver: sqlite, postgres or memory", (*stringValue)(&cfg.Database.Driver)},
+		{DatabaseDSNEnv, "db-dsn", "SQLite file path or PostgreSQL connection string", (*stringValue)(&cfg.Database.DSN)},
+		{CSVFileEnv, "csv-file", "CSV refreshed from by tenants that name none", (*stringValue)(&cfg.Ingestion.CSVFile)},
+		{TenantsFileEnv, "tenants-file", "JSON file listing the tenants", (*stringValue)(&cfg.Ingestion.TenantsFile)},
+		{CronScheduleEnv, "cron-schedule", "refresh schedule of tenants that name none", (*stringValue)(&cfg.Cron.Schedule)},
+		{APIKeysFileEnv, "api-keys-file", "JSON file listing the API keys", (*stringValue)(&cfg.Auth.APIKeysFile)},
+		{JWKSFileEnv, "jwks-file", "JSON Web Key Set verifying JWTs", (*stringValue)(&cfg.Auth.JWKSFile)},
+		{JWTIssuerEnv, "jwt-issuer", "required JWT iss claim", (*stringValue)(&cfg.Auth.JWTIssuer)},
+		{JWTAudienceEnv, "jwt-audience", "required JWT aud claim", (*stringValue)(&cfg.Auth.JWTAudience)},
+		{CacheRedisURLEnv, "cache-redis-url", "Redis-compatible server shared by every instance", (*stringValue)(&cfg.Cache.RedisURL)},
+		{CacheEntriesEnv, "cache-entries", "results kept by the in-process cache", (*intValue)(&cfg.Cache.Entries)},
+		{CacheTTLEnv, "cache-ttl", "how long results are cached, e.g. 10m", &cfg.Cache.TTL},
+	}
+}
+
+// Load returns the validated settings: the defaults overridden by the configuration file named by
+// the -config flag or the environment, then by the environment, then by the flags in args. It also
+// returns the arguments left after the flags.
+func Load(args []string) (Config, []string, error) {
+	cfg := Default()
+	bindings := settings(&cfg)
+
+	// flags are parsed first to find the configuration file, and applied last
+	flags := flag.NewFlagSet("sales", flag.ContinueOnError)
+	configFile := flags.String("config", "", "YAML or TOML configuration file ("+ConfigFileEnv+")")
+	for _, s := range bindings {
+		flags.String(s.flag, s.value.String(), s.usage+" ("+s.env+")")
+	}
+	if err := flags.Parse(args); err != nil {
+		return Config{}, nil, err
+	}
+
+	if *configFile == "" {
+		*configFile = os.Getenv(ConfigFileEnv)
+	}
+	// a DSN of the file is only known to be a path relative to it once the driver is final
+	var dsnDir string
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return Config{}, nil, err
+		}
+		if cfg.Database.DSN != "" {
+			dsnDir = filepath.Dir(*configFile)
+		}
+	}
+
+	defaults := Default()
+	defaultBindings := settings(&defaults)
+	for i, s := range bindings {
+		value, ok := os.LookupEnv(s.env)
+		if !ok {
+			continue
+		}
+		if value == "" {
+			value = defaultBindings[i].value.String()
+		}
+		if err := s.value.Set(value); err != nil {
+			return Config{}, nil, fmt.Errorf("invalid %s: %w", s.env, err)
+		}
+		if s.env == DatabaseDSNEnv {
+			dsnDir = ""
+		}
+	}
+
+	var err error
+	flags.Visit(func(f *flag.Flag) {
+		for _, s := range bindings {
+			if s.flag == f.Name && err == nil {
+				if setErr := s.value.Set(f.Value.String()); setErr != nil {
+					err = fmt.Errorf("invalid -%s: %w", f.Name, setErr)
+				}
+				if s.env == DatabaseDSNEnv {
+					dsnDir = ""
+				}
+			}
+		}
+	})
+	if err != nil {
+		return Config{}, nil, err
+	}
+
+	if cfg.Database.Driver == "" {
+		cfg.Database.Driver = constants.DriverSQLite
+	}
+	// SQLite URIs and in-memory databases are not file paths
+	if cfg.Database.Driver == constants.DriverSQLite {
+		switch dsn := cfg.Database.DSN; {
+		case dsn == "":
+			cfg.Database.DSN = constants.DefaultDatabaseFile
+		case dsnDir != "" && !filepath.IsAbs(dsn) && !strings.HasPrefix(dsn, "file:") && !strings.HasPrefix(dsn, ":memory:"):
+			cfg.Database.DSN = filepath.Join(dsnDir, dsn)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, nil, err
+	}
+	return cfg, flags.Args(), nil
+}
+
+// loadFile overrides cfg with the settings of a YAML or TOML file, chosen by its extension. Unknown
+// keys are rejected, and relative file paths are resolved against the directory of the file so it
+// works from any working directory. The DSN is left to Load, which knows the final driver.
+func (cfg *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	before := *cfg
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		// an empty file has no document, which leaves the settings as they are
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("invalid configuration file %s: %w", path, err)
+		}
+	case ".toml":
+		decoder := toml.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(cfg); err != nil {
+			var strict *toml.StrictMissingError
+			if errors.As(err, &strict) && len(strict.Errors) > 0 {
+				return fmt.Errorf("invalid configuration file %s: unknown key %s", path, strings.Join(strict.Errors[0].Key(), "."))
+			}
+			return fmt.Errorf("invalid configuration file %s: %w", path, err)
+		}
+	default:
+		return fmt.Errorf("configuration file %s must be .yaml, .yml or .toml", path)
+	}
+
+	dir := filepath.Dir(path)
+	resolve := func(value *string, previous string) {
+		if *value != previous && *value != "" && !filepath.IsAbs(*value) {
+			*value = filepath.Join(dir, *value)
+		}
+	}
+	resolve(&cfg.Server.RateLimitsFile, before.Server.RateLimitsFile)
+	resolve(&cfg.Ingestion.CSVFile, before.Ingestion.CSVFile)
+	resolve(&cfg.Ingestion.TenantsFile, before.Ingestion.TenantsFile)
+	resolve(&cfg.Auth.APIKeysFile, before.Auth.APIKeysFile)
+	resolve(&cfg.Auth.JWKSFile, before.Auth.JWKSFile)
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (cfg Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	for key, addr := range map[string]string{"server.http_addr": cfg.Server.HTTPAddr, "server.grpc_addr": cfg.Server.GRPCAddr} {
+		_, _, err := net.SplitHostPort(addr)
+		check(err == nil, "%s %q must be a host:port address", key, addr)
+	}
+	check(cfg.Server.HTTPAddr != cfg.Server.GRPCAddr, "server.http_addr and server.grpc_addr must differ")
+
+	switch cfg.Database.Driver {
+	case constants.DriverSQLite:
+		check(cfg.Database.DSN != "", "database.dsn is required with the %s driver", constants.DriverSQLite)
+	case constants.DriverPostgres:
+		check(cfg.Database.DSN != "", "database.dsn is required with the %s driver", constants.DriverPostgres)
+	case constants.DriverMemory:
+	default:
+		check(false, "unsupported database.driver %q, expected %s, %s or %s", cfg.Database.Driver,
+			constants.DriverSQLite, constants.DriverPostgres, constants.DriverMemory)
+	}
+
+	check(cfg.Ingestion.CSVFile != "", "ingestion.csv_file is required")
+	if _, err := cron.ParseStandard(cfg.Cron.Schedule); err != nil {
+		check(false, "invalid cron.schedule %q: %v", cfg.Cron.Schedule, err)
+	}
+
+	// files are read at startup, so a missing one is reported with the rest
+	for key, path := range map[string]string{
+		"server.rate_limits_file": cfg.Server.RateLimitsFile,
+		"ingestion.tenants_file":  cfg.Ingestion.TenantsFile,
+		"auth.api_keys_file":      cfg.Auth.APIKeysFile,
+		"auth.jwks_file":          cfg.Auth.JWKSFile,
+	} {
+		if path != "" {
+			_, err := os.Stat(path)
+			check(err == nil, "%s: %v", key, err)
+		}
+	}
+
+	if cfg.Cache.RedisURL != "" {
+		u, err := url.Parse(cfg.Cache.RedisURL)
+		check(err == nil && (u.Scheme == "redis" || u.Scheme == "rediss" || u.Scheme == "unix"),
+			"cache.redis_url must be a redis://, rediss:// or unix:// URL")
+	}
+	check(cfg.Cache.Entries >= 1, "cache.entries must be at least 1")
+	check(cfg.Cache.TTL > 0, "cache.ttl must be positive")
+
+	// map iteration makes the order random, so sort for stable messages
+	slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+	return errors.Join(errs...)
+}
+
+// redactedSecret replaces the secrets of the redacted settings.
+const redactedSecret = "xxxxx"
+
+// passwordParam matches the password of a key=value PostgreSQL connection string.
+var passwordParam = regexp.MustCompile(`(?i)(\bpassword\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// Redacted returns a copy of cfg safe to show, without the passwords of the database DSN and the
+// Redis URL.
+func (cfg Config) Redacted() Config {
+	cfg.Database.DSN = redactDSN(cfg.Database.DSN)
+	cfg.Cache.RedisURL = redactDSN(cfg.Cache.RedisURL)
+	return cfg
+}
+
+// redactDSN hides the password of a URL or a key=value connection string.
+func redactDSN(dsn string) string {
+	if strings.Contains(dsn, "://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return redactedSecret
+		}
+		// passwords given as query parameters, as PostgreSQL URLs allow
+		query := u.Query()
+		if query.Has("password") {
+			query.Set("password", redactedSecret)
+			u.RawQuery = query.Encode()
+		}
+		return u.Redacted()
+	}
+	return passwordParam.ReplaceAllString(dsn, "${1}"+redactedSecret)
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sales/internal/constants"
+	"testing"
+	"time"
+)
+
+// isolate unsets the SALES_* variables Load reads for the duration of the test.
+func isolate(t *testing.T) {
+	t.Helper()
+	var cfg Config
+	for _, s := range settings(&cfg) {
+		t.Setenv(s.env, "")
+		os.Unsetenv(s.env)
+	}
+	t.Setenv(ConfigFileEnv, "")
+	os.Unsetenv(ConfigFileEnv)
+}
+
+// writeFile writes content to name in dir and returns its path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultsAreRelativeToTheWorkingDirectory(t *testing.T) {
+	isolate(t)
+
+	cfg, _, err := Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Ingestion.CSVFile != constants.DefaultCSVFile || cfg.Database.DSN != constants.DefaultDatabaseFile {
+		t.Errorf("files %q and %q, want %q and %q", cfg.Ingestion.CSVFile, cfg.Database.DSN, constants.DefaultCSVFile, constants.DefaultDatabaseFile)
+	}
+	if cfg.Server.HTTPAddr != constants.DefaultHTTPAddr || cfg.Cron.Schedule != constants.DefaultCronSchedule || cfg.Cache.TTL != Duration(constants.CacheTTL) {
+		t.Errorf("defaults %+v", cfg)
+	}
+	if cfg, _, err := Load([]string{"-db-driver", constants.DriverMemory}); err != nil || cfg.Database.DSN != "" {
+		t.Errorf("memory driver: DSN %q, %v, want none", cfg.Database.DSN, err)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	isolate(t)
+	for name, content := range map[string]string{
+		"sales.yaml": "server:\n  http_addr: \":8001\"\n  grpc_addr: \":9001\"\ningestion:\n  csv_file: sales.csv\ncron:\n  schedule: \"@hourly\"\ncache:\n  entries: 10\n",
+		"sales.toml": "[server]\nhttp_addr = \":8001\"\ngrpc_addr = \":9001\"\n[ingestion]\ncsv_file = \"sales.csv\"\n[cron]\nschedule = \"@hourly\"\n[cache]\nentries = 10\n",
+	} {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(ConfigFileEnv, file)
+			t.Setenv(GRPCAddrEnv, ":9002")
+			t.Setenv(CacheEntriesEnv, "20")
+			t.Setenv(CacheTTLEnv, "1m")
+
+			cfg, args, err := Load([]string{"-cache-entries", "30", "migrate", "up"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, check := range []struct {
+				setting   string
+				got, want any
+			}{
+				{"file only", cfg.Server.HTTPAddr, ":8001"},
+				{"file then env", cfg.Server.GRPCAddr, ":9002"},
+				{"file, env then flag", cfg.Cache.Entries, 30},
+				{"env only", time.Duration(cfg.Cache.TTL), time.Minute},
+				{"schedule of the file", cfg.Cron.Schedule, "@hourly"},
+				{"relative path of the file", cfg.Ingestion.CSVFile, filepath.Join(dir, "sales.csv")},
+				{"arguments after the flags", len(args), 2},
+			} {
+				if check.got != check.want {
+					t.Errorf("%s: %v, want %v", check.setting, check.got, check.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadRejectsInvalidSettings(t *testing.T) {
+	isolate(t)
+
+	t.Setenv(CacheEntriesEnv, "many")
+	if _, _, err := Load(nil); err == nil {
+		t.Error("Load accepted a malformed environment variable")
+	}
+	os.Unsetenv(CacheEntriesEnv)
+
+	if _, _, err := Load([]string{"-db-driver", "oracle"}); err == nil {
+		t.Error("Load accepted an unsupported driver")
+	}
+	if _, _, err := Load([]string{"-config", filepath.Join(t.TempDir(), "sales.json")}); err == nil {
+		t.Error("Load accepted a configuration file of an unknown format")
+	}
+}
+
+func TestEmptyEnvironmentRestoresDefaults(t *testing.T) {
+	dir := t.TempDir()
+	isolate(t)
+	t.Setenv(ConfigFileEnv, writeFile(t, dir, "sales.yaml", "server:\n  http_addr: \":8001\"\nauth:\n  jwt_issuer: https://issuer.example\ncache:\n  entries: 10\n"))
+	t.Setenv(HTTPAddrEnv, "")
+	t.Setenv(JWTIssuerEnv, "")
+	t.Setenv(CacheEntriesEnv, "")
+
+	cfg, _, err := Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.HTTPAddr != constants.DefaultHTTPAddr || cfg.Auth.JWTIssuer != "" || cfg.Cache.Entries != constants.CacheEntries {
+		t.Errorf("cleared settings %q, %q and %d, want the defaults", cfg.Server.HTTPAddr, cfg.Auth.JWTIssuer, cfg.Cache.Entries)
+	}
+}
+
+func TestDSNFollowsTheFinalDriver(t *testing.T) {
+	dir := t.TempDir()
+	isolate(t)
+	postgresDSN := "host=localhost dbname=sales"
+
+	for _, test := range []struct {
+		name, file string
+		env        map[string]string
+		args       []string
+		want       string
+	}{
+		{"SQLite file of the file", "database:\n  dsn: sales.db\n", nil, nil, filepath.Join(dir, "sales.db")},
+		{"PostgreSQL DSN of the file, driver of the environment", "database:\n  dsn: " + postgresDSN + "\n", map[string]string{DatabaseDriverEnv: constants.DriverPostgres}, nil, postgresDSN},
+		{"SQLite file of the file, driver of a flag", "database:\n  driver: postgres\n  dsn: sales.db\n", nil, []string{"-db-driver", constants.DriverSQLite}, filepath.Join(dir, "sales.db")},
+		{"SQLite file of the environment", "database:\n  dsn: sales.db\n", map[string]string{DatabaseDSNEnv: "other.db"}, nil, "other.db"},
+		{"SQLite file of a flag", "database:\n  dsn: sales.db\n", nil, []string{"-db-dsn", "other.db"}, "other.db"},
+		{"SQLite in memory", "database:\n  dsn: \":memory:\"\n", nil, nil, ":memory:"},
+		{"DSN cleared by the environment", "database:\n  dsn: sales.db\n", map[string]string{DatabaseDSNEnv: ""}, nil, constants.DefaultDatabaseFile},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(ConfigFileEnv, writeFile(t, dir, "sales.yaml", test.file))
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+			cfg, _, err := Load(test.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Database.DSN != test.want {
+				t.Errorf("DSN %q, want %q", cfg.Database.DSN, test.want)
+			}
+		})
+	}
+}
@@ -1,16 +1,96 @@
 package constants
 
-import "path/filepath"
+import (
+	"path/filepath"
+	"time"
+)
 
+// defaults of the settings, relative paths being resolved against the working directory
 var (
-	CSVFilePath  = filepath.Join("..", "data", "sales_data.csv")
-	DatabaseName = filepath.Join("..", "sales_database.db")
+	DefaultCSVFile      = filepath.Join("..", "data", "sales_data.csv")
+	DefaultDatabaseFile = filepath.Join("..", "sales_database.db")
+)
+
+const (
+	DefaultHTTPAddr     = ":8080"
+	DefaultGRPCAddr     = ":9090"
+	DefaultCronSchedule = "@daily"
+)
+
+// API versions mounted under /<version>; unversioned paths are deprecated aliases of APIVersionV1.
+const APIVersionV1 = "v1"
+
+// JWTLeeway tolerates clock skew when checking exp and nbf.
+const JWTLeeway = time.Minute
+
+// RolesClaim is the JWT claim listing the caller's roles.
+const RolesClaim = "roles"
+
+// TenantClaim is the JWT claim binding the caller to a tenant.
+const TenantClaim = "tenant"
+
+// DefaultTenant is the only tenant when no tenants file is set, and the owner of pre-tenant data.
+const DefaultTenant = "default"
+
+// route classes rate limited separately
+const (
+	RateClassAnalytics = "analytics" // top products, analytics and GraphQL
+	RateClassRefresh   = "refresh"
+	RateClassDefault   = "default" // entities, administration and documentation
+)
+
+// default rate limits per client and route class
+const (
+	AnalyticsPerMinute = 60
+	AnalyticsBurst     = 20
+	RefreshPerMinute   = 2
+	RefreshBurst       = 1
+	DefaultPerMinute   = 300
+	DefaultBurst       = 60
+)
+
+// supported database drivers
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory" // in process, empty at startup and lost on exit
 )
 
+// analytics result cache settings
 const (
-	APIServerPort = ":8080"
-	DateFormat    = "2006-01-02" // YYYY-MM-DD
-	CronTime      = "@daily"
+	CacheEntries   = 1000 // default results kept by the in-process cache
+	CacheTTL       = 10 * time.Minute
+	CacheKeyPrefix = "sales:"
+	// CacheControl lets clients keep results but makes them revalidate with the ETag before reuse
+	CacheControl = "private, no-cache"
+)
+
+// roles checked per route; admin passes every check
+const (
+	RoleViewer   = "viewer"
+	RoleAnalyst  = "analyst"
+	RoleIngestor = "ingestor"
+	RoleAdmin    = "admin"
+)
+
+var Roles = []string{RoleViewer, RoleAnalyst, RoleIngestor, RoleAdmin}
+
+const DateFormat = "2006-01-02" // YYYY-MM-DD
+
+// TimestampFormats are the accepted Date of Sale layouts in the CSV, tried in order.
+var TimestampFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	DateFormat,
+}
+
+// query limits
+const (
+	MaxLimit         = 100
+	MaxDateRangeDays = 731 // two years, leap day included
 )
 
 // query params
@@ -18,4 +98,135 @@ const (
 	StartDate = "start_date"
 	EndDate   = "end_date"
 	Limit     = "n"
+	ProductID = "product_id"
+	Category  = "category"
+	Horizon   = "horizon"
+	Method    = "method"
+	Interval  = "interval"
+	Region    = "region"
+	MinVolume = "min_volume"
+	Rows      = "rows"
+	Columns   = "columns"
+	Metric    = "metric"
+	Percent   = "percent"
+	Format    = "format"
+	GroupBy   = "group_by"
+	Buckets   = "buckets"
+	Timezone  = "tz"
+)
+
+// filter params
+const (
+	PaymentMethod = "payment_method"
+	MinPrice      = "min_price"
+	MaxPrice      = "max_price"
+	CustomerID    = "customer_id"
+)
+
+// pagination params
+const (
+	PageLimit  = "limit"
+	Cursor     = "cursor"
+	PageNumber = "page"
+	PageSize   = "page_size"
+	Sort       = "sort"
+)
+
+// GraphQL and gRPC arguments
+const (
+	GraphQLOffset = "offset"
+	GraphQLSortBy = "sortBy"
+	GRPCDimension = "dimension"
+)
+
+// GraphQLMaxDepth caps how deeply GraphQL queries may nest selections.
+const GraphQLMaxDepth = 10
+
+// StreamPageSize is how many rows gRPC streams read per query.
+const StreamPageSize = 500
+
+// pagination limits
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// pivot dimensions
+const (
+	DimensionRegion        = "region"
+	DimensionCategory      = "category"
+	DimensionPaymentMethod = "payment_method"
+	DimensionProduct       = "product"
+	DimensionMonth         = "month"
+)
+
+var PivotDimensions = []string{DimensionRegion, DimensionCategory, DimensionPaymentMethod, DimensionProduct, DimensionMonth}
+
+// metrics
+const (
+	MetricQuantity = "quantity"
+	MetricRevenue  = "revenue"
+	MetricOrders   = "orders"
+)
+
+var Metrics = []string{MetricQuantity, MetricRevenue, MetricOrders}
+
+// pivot percentage modes
+const (
+	PercentOfRow    = "row"
+	PercentOfColumn = "column"
+)
+
+var PercentModes = []string{PercentOfRow, PercentOfColumn}
+
+// response formats
+const (
+	FormatJSON   = "json"
+	FormatCSV    = "csv"
+	FormatXLSX   = "xlsx"
+	FormatNDJSON = "ndjson"
+)
+
+// Formats lists every format analytics results can be exported as.
+var Formats = []string{FormatJSON, FormatCSV, FormatXLSX, FormatNDJSON}
+
+// FormatMediaTypes maps each format to the media type negotiated through the Accept header.
+var FormatMediaTypes = map[string]string{
+	FormatJSON:   "application/json",
+	FormatCSV:    "text/csv",
+	FormatXLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	FormatNDJSON: "application/x-ndjson",
+}
+
+// ExportFlushRows is how many exported rows are written between flushes to the client.
+const ExportFlushRows = 500
+
+// time bucketing intervals
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+var Intervals = []string{IntervalDay, IntervalWeek, IntervalMonth}
+
+// dimensions top products are grouped by
+var GroupDimensions = []string{DimensionCategory, DimensionRegion}
+
+// trending products
+const DefaultTrendingMinVolume = 5
+
+// distribution statistics
+const (
+	DefaultHistogramBuckets = 10
+	MaxHistogramBuckets     = 100
+)
+
+var DistributionDimensions = []string{DimensionRegion, DimensionCategory, DimensionPaymentMethod}
+
+// forecasting
+const (
+	DefaultForecastHorizon = 4
+	MaxForecastHorizon     = 52
+	ForecastInterval       = IntervalWeek
 )
@@ -1,9 +1,65 @@
 package constants
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrInvalidLimit     = errors.New("invalid 'n' parameter for total records")
+	ErrInvalidLimit     = fmt.Errorf("invalid 'n' parameter for total records, expected 1 to %d", MaxLimit)
 	ErrInvalidStartDate = errors.New("invalid start_date")
 	ErrInvalidEndDate   = errors.New("invalid end_date")
+	ErrInvalidHorizon   = errors.New("invalid 'horizon' parameter for forecast periods")
+	ErrInvalidMethod    = errors.New("invalid forecasting method")
+	ErrMissingTarget    = errors.New("either product_id or category is required")
+	ErrInvalidInterval  = errors.New("invalid interval, expected day, week or month")
+	ErrInvalidMinVolume = errors.New("invalid 'min_volume' parameter, expected a non-negative integer")
+	ErrInvalidRows      = errors.New("invalid 'rows' dimension, expected region, category, payment_method, product or month")
+	ErrInvalidColumns   = errors.New("invalid 'columns' dimension, expected region, category, payment_method, product or month")
+	ErrSameDimensions   = errors.New("'rows' and 'columns' must be different dimensions")
+	ErrInvalidMetric    = errors.New("invalid metric, expected quantity, revenue or orders")
+	ErrInvalidPercent   = errors.New("invalid 'percent' mode, expected row or column")
+	ErrInvalidFormat    = errors.New("invalid response format")
+	ErrInvalidGroupBy   = errors.New("invalid 'group_by' dimension, expected region, category or payment_method")
+	ErrInvalidBuckets   = errors.New("invalid 'buckets' parameter for histogram buckets")
+	ErrInvalidTimezone  = errors.New("invalid 'tz' parameter, expected an IANA timezone such as Europe/Berlin")
+	ErrInvalidPageLimit = fmt.Errorf("invalid 'limit' parameter, expected 1 to %d", MaxPageSize)
+	ErrInvalidPageSize  = fmt.Errorf("invalid 'page_size' parameter, expected 1 to %d", MaxPageSize)
+	ErrInvalidPage      = errors.New("invalid 'page' parameter, expected a positive integer")
+	ErrInvalidCursor    = errors.New("invalid 'cursor' parameter, expected the next_cursor of a page with the same sort")
+	ErrInvalidSort      = errors.New("invalid 'sort' parameter, expected field:asc or field:desc on a sortable field")
+	ErrMixedPagination  = errors.New("use either limit/cursor or page/page_size, not both")
+	ErrInvalidMinPrice  = errors.New("invalid 'min_price' parameter, expected a non-negative number")
+	ErrInvalidMaxPrice  = errors.New("invalid 'max_price' parameter, expected a non-negative number")
+	ErrPriceRangeOrder  = errors.New("max_price must not be below min_price")
+	ErrInvalidOffset    = errors.New("invalid 'offset' argument, expected a non-negative integer")
+	ErrInvalidSortBy    = errors.New("invalid 'sortBy' argument, expected a sortable field")
+	ErrInvalidDimension = errors.New("invalid 'dimension' argument, expected category or region")
+
+	ErrProductNotFound   = errors.New("product not found")
+	ErrCustomerNotFound  = errors.New("customer not found")
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrOrderItemNotFound = errors.New("order item not found")
+	ErrProductExists     = errors.New("product already exists")
+	ErrCustomerExists    = errors.New("customer already exists")
+	ErrOrderExists       = errors.New("order already exists")
+	ErrProductInUse      = errors.New("product is referenced by order items")
+	ErrCustomerInUse     = errors.New("customer is referenced by orders")
+	ErrUnknownProduct    = errors.New("referenced product does not exist")
+	ErrUnknownCustomer   = errors.New("referenced customer does not exist")
+	ErrIDMismatch        = errors.New("id in body does not match the path")
+	ErrInvalidBody       = errors.New("invalid JSON body")
+
+	ErrUnauthorized  = errors.New("missing or invalid credentials")
+	ErrForbidden     = errors.New("credentials lack a role required for this operation")
+	ErrUnknownTenant = errors.New("unknown tenant")
+	ErrTenantDenied  = errors.New("credentials are bound to another tenant")
+	ErrTenantMissing = errors.New("credentials are not bound to a tenant, select one with X-Tenant-ID")
+	ErrRateLimited   = errors.New("rate limit exceeded, retry later")
+
+	ErrUnknownParameter  = errors.New("unknown parameter")
+	ErrMissingParameter  = errors.New("missing required parameter")
+	ErrRepeatedParameter = errors.New("parameter must not be repeated")
+	ErrDateRangeOrder    = errors.New("end_date must not be before start_date")
+	ErrDateRangeTooLong  = fmt.Errorf("date range must not exceed %d days", MaxDateRangeDays)
 )
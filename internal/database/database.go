@@ -2,20 +2,61 @@ package database
 
 import (
 	"fmt"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	_ "modernc.org/sqlite" // Blank import to register the driver
+	"sales/internal/constants"
+	"sales/internal/tenant"
 )
 
-func NewDatabase(dbPath string) (*gorm.DB, error) {
-	gormDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+// Config selects the database the application stores its data in.
+type Config struct {
+	Driver string // constants.DriverSQLite, constants.DriverPostgres or constants.DriverMemory
+	DSN    string // file path for SQLite, connection string for PostgreSQL, unused in memory
+}
+
+// dialector returns the gorm dialector of the configured driver.
+func (cfg Config) dialector() (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case constants.DriverSQLite:
+		return sqlite.Open(cfg.DSN), nil
+	case constants.DriverPostgres:
+		return postgres.Open(cfg.DSN), nil
+	case constants.DriverMemory:
+		return nil, fmt.Errorf("the %s driver keeps no database", constants.DriverMemory)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+}
+
+// Open connects to the configured database without migrating it.
+func Open(cfg Config) (*gorm.DB, error) {
+	dialector, err := cfg.dialector()
+	if err != nil {
+		return nil, err
+	}
+	gormDB, err := gorm.Open(dialector, &gorm.Config{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to open gorm database: %w", err)
 	}
-	err = AutoMigrateSchemas(gormDB)
+	return gormDB, nil
+}
+
+// NewDatabase opens the configured database and applies its pending migrations. It refuses a
+// schema migrated by a newer binary.
+func NewDatabase(cfg Config) (*gorm.DB, error) {
+	gormDB, err := Open(cfg)
 	if err != nil {
 		return nil, err
 	}
+	if _, err := MigrateUp(gormDB); err != nil {
+		return nil, err
+	}
+	// every statement on tenant data from here on needs a tenant in its context
+	if err := tenant.Register(gormDB); err != nil {
+		return nil, err
+	}
 
 	return gormDB, nil
 }
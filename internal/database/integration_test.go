@@ -0,0 +1,356 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sales/internal/constants"
+	"sales/internal/database"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"sales/internal/repository/memory"
+	"sales/internal/services"
+	"sales/internal/tenant"
+	"sales/pkg/cache"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// postgresDSNEnv names a PostgreSQL server to run the integration tests against as well. Each test
+// works in a scratch schema it drops afterwards.
+const postgresDSNEnv = "SALES_TEST_POSTGRES_DSN"
+
+// salesCSV sells around month and week boundaries and both 2024 daylight saving changes, with
+// customers returning in later periods.
+const salesCSV = "Order ID,Product ID,Customer ID,Product Name,Category,Region,Date of Sale,Quantity Sold,Unit Price,Discount,Shipping Cost,Payment Method,Customer Name,Customer Email,Customer Address\n" +
+	`2001,P1,C1,Trail Shoes,Shoes,North America,2024-01-01,2,100.00,0.1,10.00,Credit Card,Ann Archer,ann@example.com,"1 A St"` + "\n" +
+	`2002,P2,C2,Rain Jacket,Clothing,Europe,2024-01-07,1,80.00,0.0,5.00,PayPal,Bob Baker,bob@example.com,"2 B St"` + "\n" +
+	`2003,P1,C1,Trail Shoes,Shoes,North America,2024-02-29,3,100.00,0.0,10.00,Credit Card,Ann Archer,ann@example.com,"1 A St"` + "\n" +
+	`2004,P3,C3,Phone X,Electronics,Asia,2024-03-01,1,900.00,0.05,15.00,Debit Card,Cat Cole,cat@example.com,"3 C St"` + "\n" +
+	`2005,P2,C2,Rain Jacket,Clothing,Europe,2024-03-10,4,80.00,0.2,5.00,PayPal,Bob Baker,bob@example.com,"2 B St"` + "\n" +
+	`2006,P3,C1,Phone X,Electronics,North America,2024-03-11,1,900.00,0.0,15.00,Credit Card,Ann Archer,ann@example.com,"1 A St"` + "\n" +
+	`2007,P1,C4,Trail Shoes,Shoes,South America,2024-03-31,5,100.00,0.0,8.00,PayPal,Dan Dunn,dan@example.com,"4 D St"` + "\n" +
+	`2008,P2,C3,Rain Jacket,Clothing,Asia,2024-04-01,2,80.00,0.0,5.00,Debit Card,Cat Cole,cat@example.com,"3 C St"` + "\n" +
+	`2009,P3,C4,Phone X,Electronics,South America,2024-11-03,1,900.00,0.1,15.00,PayPal,Dan Dunn,dan@example.com,"4 D St"` + "\n" +
+	`2010,P1,C2,Trail Shoes,Shoes,Europe,2024-12-31,6,100.00,0.0,10.00,Credit Card,Bob Baker,bob@example.com,"2 B St"` + "\n"
+
+// otherTenantCSV is the data of a second tenant.
+const otherTenantCSV = "Order ID,Product ID,Customer ID,Product Name,Category,Region,Date of Sale,Quantity Sold,Unit Price,Discount,Shipping Cost,Payment Method,Customer Name,Customer Email,Customer Address\n" +
+	`3001,P9,C9,Desk Lamp,Home,Europe,2024-03-10,7,30.00,0.0,4.00,PayPal,Eve Evans,eve@example.com,"9 E St"` + "\n"
+
+// dialects returns the databases to run a test on by dialect: a temporary SQLite file, and a scratch
+// schema of the PostgreSQL server in SALES_TEST_POSTGRES_DSN when it is set. The databases are not migrated.
+func dialects(t *testing.T) map[string]func(t *testing.T) *gorm.DB {
+	openers := map[string]func(t *testing.T) *gorm.DB{
+		constants.DriverSQLite: func(t *testing.T) *gorm.DB {
+			return open(t, database.Config{Driver: constants.DriverSQLite, DSN: filepath.Join(t.TempDir(), "sales.db")})
+		},
+	}
+	if dsn := os.Getenv(postgresDSNEnv); dsn != "" {
+		openers[constants.DriverPostgres] = func(t *testing.T) *gorm.DB {
+			return openPostgresSchema(t, dsn)
+		}
+	} else {
+		t.Logf("%s is not set, skipping PostgreSQL", postgresDSNEnv)
+	}
+	return openers
+}
+
+func open(t *testing.T, cfg database.Config) *gorm.DB {
+	t.Helper()
+	db, err := database.Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return db
+}
+
+// openPostgresSchema creates a scratch schema on the server of dsn, dropped when the test ends, and
+// opens a connection whose search path is that schema.
+func openPostgresSchema(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+	admin := open(t, database.Config{Driver: constants.DriverPostgres, DSN: dsn})
+	schema := fmt.Sprintf("sales_test_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = admin.Exec("DROP SCHEMA " + schema + " CASCADE").Error })
+
+	if strings.Contains(dsn, "://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		query := u.Query()
+		query.Set("search_path", schema)
+		u.RawQuery = query.Encode()
+		dsn = u.String()
+	} else {
+		dsn += " search_path=" + schema
+	}
+	return open(t, database.Config{Driver: constants.DriverPostgres, DSN: dsn})
+}
+
+// migrate applies every migration and scopes db to tenants, like database.NewDatabase.
+func migrate(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	if _, err := database.MigrateUp(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := tenant.Register(db); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// importCSV imports data as tenant id through the refresh service.
+func importCSV(t *testing.T, store repository.Store, id, data string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), id+".csv")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	svc := services.New(store, services.NewResultCache(cache.NewLRU(constants.CacheEntries, constants.CacheTTL)))
+	if err := svc.Refresh.RefreshDatabase(tenantContext(id), path); err != nil {
+		t.Fatalf("importing tenant %s: %v", id, err)
+	}
+}
+
+func tenantContext(id string) context.Context {
+	return tenant.NewContext(context.Background(), tenant.Tenant{ID: id})
+}
+
+func TestMigrateUpAndDown(t *testing.T) {
+	for dialect, openDB := range dialects(t) {
+		t.Run(dialect, func(t *testing.T) {
+			db := openDB(t)
+			migrations, err := database.Migrations(db)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			applied, err := database.MigrateUp(db)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(applied) != len(migrations) {
+				t.Fatalf("applied %d migrations, want %d", len(applied), len(migrations))
+			}
+			if again, err := database.MigrateUp(db); err != nil || len(again) != 0 {
+				t.Fatalf("migrating an up to date database applied %d migrations: %v", len(again), err)
+			}
+			assertStatus(t, db, len(migrations))
+			for _, table := range []string{"products", "customers", "orders", "order_items", "daily_product_sales"} {
+				if !db.Migrator().HasTable(table) {
+					t.Errorf("table %s is missing after migrating up", table)
+				}
+			}
+
+			reverted, err := database.MigrateDown(db, 1)
+			if err != nil || len(reverted) != 1 || reverted[0].Version != migrations[len(migrations)-1].Version {
+				t.Fatalf("reverting one migration reverted %v: %v", reverted, err)
+			}
+			if db.Migrator().HasTable("daily_product_sales") {
+				t.Error("daily_product_sales survived reverting its migration")
+			}
+			assertStatus(t, db, len(migrations)-1)
+
+			if reverted, err := database.MigrateDown(db, len(migrations)); err != nil || len(reverted) != len(migrations)-1 {
+				t.Fatalf("reverting every migration reverted %d: %v", len(reverted), err)
+			}
+			if db.Migrator().HasTable("products") {
+				t.Error("products survived reverting every migration")
+			}
+			assertStatus(t, db, 0)
+
+			if applied, err := database.MigrateUp(db); err != nil || len(applied) != len(migrations) {
+				t.Fatalf("migrating up again applied %d migrations: %v", len(applied), err)
+			}
+		})
+	}
+}
+
+// assertStatus expects the first applied migrations to be recorded as applied and the others pending.
+func assertStatus(t *testing.T, db *gorm.DB, applied int) {
+	t.Helper()
+	statuses, err := database.Status(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, status := range statuses {
+		if (status.AppliedAt != "") != (i < applied) {
+			t.Errorf("migration %04d_%s applied at %q, want %d applied migrations", status.Version, status.Name, status.AppliedAt, applied)
+		}
+	}
+}
+
+// TestRevertTenantsKeepsDefaultTenant imports two tenants, reverts the tenant and rollup migrations
+// and migrates up again: the default tenant's data survives and the rollup is backfilled from it.
+func TestRevertTenantsKeepsDefaultTenant(t *testing.T) {
+	for dialect, openDB := range dialects(t) {
+		t.Run(dialect, func(t *testing.T) {
+			db := openDB(t)
+			migrate(t, db)
+			store := repository.NewSQLStore(db)
+			importCSV(t, store, constants.DefaultTenant, salesCSV)
+			importCSV(t, store, "other", otherTenantCSV)
+
+			ctx := tenantContext(constants.DefaultTenant)
+			rollup := models.DateRange{From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Location: time.UTC}
+			before, err := store.GetSalesByPeriod(ctx, constants.IntervalMonth, "", rollup, models.SalesFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := database.MigrateDown(db, 2); err != nil {
+				t.Fatal(err)
+			}
+			var products int64
+			if err := db.Raw("SELECT COUNT(*) FROM products").Scan(&products).Error; err != nil {
+				t.Fatal(err)
+			}
+			if products != 3 {
+				t.Errorf("%d products after reverting tenants, want the default tenant's 3", products)
+			}
+
+			if _, err := database.MigrateUp(db); err != nil {
+				t.Fatal(err)
+			}
+			after, err := store.GetSalesByPeriod(ctx, constants.IntervalMonth, "", rollup, models.SalesFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertSameBuckets(t, "monthly sales after migrating up again", after, before)
+			if other, err := store.GetSalesByPeriod(tenantContext("other"), constants.IntervalMonth, "", rollup, models.SalesFilter{}); err != nil || len(other) != 0 {
+				t.Errorf("the other tenant kept %v: %v", other, err)
+			}
+		})
+	}
+}
+
+// TestPeriodAnalytics imports sales and expects the period-bucketed analytics of every interval, on
+// the rollup and in other timezones on the raw tables, to match the in-memory store.
+func TestPeriodAnalytics(t *testing.T) {
+	reference := memory.NewStore()
+	importCSV(t, reference, constants.DefaultTenant, salesCSV)
+	ctx := tenantContext(constants.DefaultTenant)
+
+	for dialect, openDB := range dialects(t) {
+		t.Run(dialect, func(t *testing.T) {
+			db := openDB(t)
+			migrate(t, db)
+			store := repository.NewSQLStore(db)
+			importCSV(t, store, constants.DefaultTenant, salesCSV)
+			importCSV(t, store, "other", otherTenantCSV)
+
+			for _, zone := range []string{"UTC", "America/New_York", "Asia/Kolkata"} {
+				location, err := time.LoadLocation(zone)
+				if err != nil {
+					t.Fatal(err)
+				}
+				dateRange := models.DateRange{From: time.Date(2024, 1, 1, 0, 0, 0, 0, location), To: time.Date(2025, 1, 1, 0, 0, 0, 0, location), Location: location}
+				for _, interval := range constants.Intervals {
+					name := zone + " " + interval
+					got, err := store.GetSalesByPeriod(ctx, interval, "", dateRange, models.SalesFilter{})
+					if err != nil {
+						t.Fatal(err)
+					}
+					want, err := reference.GetSalesByPeriod(ctx, interval, "", dateRange, models.SalesFilter{})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(got) == 0 {
+						t.Fatalf("%s: no sales", name)
+					}
+					assertSameBuckets(t, name+" sales", got, want)
+
+					gotSegments, err := store.GetCustomerSegmentsByPeriod(ctx, interval, dateRange, models.SalesFilter{})
+					if err != nil {
+						t.Fatal(err)
+					}
+					wantSegments, err := reference.GetCustomerSegmentsByPeriod(ctx, interval, dateRange, models.SalesFilter{})
+					if err != nil {
+						t.Fatal(err)
+					}
+					assertSameSegments(t, name+" customer segments", gotSegments, wantSegments)
+				}
+
+				gotCells, err := store.GetPivotCells(ctx, constants.DimensionMonth, constants.DimensionCategory, constants.MetricRevenue, dateRange, models.SalesFilter{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				wantCells, err := reference.GetPivotCells(ctx, constants.DimensionMonth, constants.DimensionCategory, constants.MetricRevenue, dateRange, models.SalesFilter{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				assertSameCells(t, zone+" monthly pivot", gotCells, wantCells)
+			}
+		})
+	}
+}
+
+// closeTo compares sums of prices, which the databases may round differently.
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func assertSameBuckets(t *testing.T, name string, got, want []models.SalesBucket) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: got %v, want %v", name, got, want)
+		return
+	}
+	for i := range got {
+		if got[i].Period != want[i].Period || got[i].QuantitySold != want[i].QuantitySold || !closeTo(got[i].Revenue, want[i].Revenue) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+			return
+		}
+	}
+}
+
+func assertSameSegments(t *testing.T, name string, got, want []models.CustomerSegmentRow) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: got %v, want %v", name, got, want)
+		return
+	}
+	for i := range got {
+		g, w := got[i], want[i]
+		if g.Period != w.Period || g.Segment != w.Segment || g.Customers != w.Customers || g.Orders != w.Orders || !closeTo(g.Revenue, w.Revenue) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+			return
+		}
+	}
+}
+
+func assertSameCells(t *testing.T, name string, got, want []models.PivotCell) {
+	t.Helper()
+	for _, cells := range [][]models.PivotCell{got, want} {
+		sort.Slice(cells, func(i, j int) bool {
+			a, b := cells[i], cells[j]
+			return fmt.Sprint(a.RowTotal, a.ColumnTotal, a.RowKey, a.ColumnKey) < fmt.Sprint(b.RowTotal, b.ColumnTotal, b.RowKey, b.ColumnKey)
+		})
+	}
+	if len(got) != len(want) {
+		t.Errorf("%s: got %v, want %v", name, got, want)
+		return
+	}
+	for i := range got {
+		g, w := got[i], want[i]
+		if g.RowKey != w.RowKey || g.ColumnKey != w.ColumnKey || g.RowTotal != w.RowTotal || g.ColumnTotal != w.ColumnTotal || !closeTo(g.Value, w.Value) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+			return
+		}
+	}
+}
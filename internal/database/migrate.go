@@ -0,0 +1,256 @@
+package database
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationFiles holds the numbered migrations of every dialect, named
+// migrations/<dialect>/<version>_<name>.<up|down>.sql.
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// migrationsTable records the migrations applied to a database.
+const migrationsTable = "schema_migrations"
+
+// ErrSchemaTooNew refuses to run against a database migrated by a newer binary.
+var ErrSchemaTooNew = errors.New("database schema is newer than this binary")
+
+// Migration is a numbered, reversible schema change.
+type Migration struct {
+	Version int
+	Name    string
+	up      string
+	down    string
+}
+
+// MigrationStatus is a known migration and when it was applied, empty while pending. Rows of
+// schema_migrations are read into it as well.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt string
+}
+
+// Migrations returns the migrations of the database's dialect in version order.
+func Migrations(db *gorm.DB) ([]Migration, error) {
+	dir := path.Join("migrations", db.Dialector.Name())
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for %s: %w", db.Dialector.Name(), err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		base, direction, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), ".")
+		number, name, found := strings.Cut(base, "_")
+		version, err := strconv.Atoi(number)
+		if !ok || !found || err != nil || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+		data, err := fs.ReadFile(migrationFiles, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if direction == "up" {
+			migration.up = string(data)
+		} else {
+			migration.down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.up == "" || migration.down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// MigrateUp applies every pending migration in order, each in its own transaction, and returns
+// the applied ones. It fails with ErrSchemaTooNew when the database has migrations this binary
+// doesn't know.
+func MigrateUp(db *gorm.DB) ([]Migration, error) {
+	migrations, applied, err := loadState(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, migration.up); err != nil {
+				return err
+			}
+			return recordMigration(tx, migration)
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		log.Printf("Applied migration %04d_%s", migration.Version, migration.Name)
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// MigrateDown reverts the latest steps applied migrations, newest first, and returns the reverted ones.
+func MigrateDown(db *gorm.DB, steps int) ([]Migration, error) {
+	migrations, applied, err := loadState(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, migration.down); err != nil {
+				return err
+			}
+			return tx.Exec("DELETE FROM "+migrationsTable+" WHERE version = ?", migration.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("reverting migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		log.Printf("Reverted migration %04d_%s", migration.Version, migration.Name)
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Status lists every known migration and when it was applied.
+func Status(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, applied, err := loadState(db)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		statuses = append(statuses, MigrationStatus{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: applied[migration.Version].AppliedAt,
+		})
+	}
+	return statuses, nil
+}
+
+// loadState returns the known migrations and the applied ones by version, creating the migrations
+// table first if needed. The database must not be newer than the known migrations.
+func loadState(db *gorm.DB) ([]Migration, map[int]MigrationStatus, error) {
+	migrations, err := Migrations(db)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := ensureMigrationsTable(db, migrations); err != nil {
+		return nil, nil, err
+	}
+
+	var rows []MigrationStatus
+	if err := db.Raw("SELECT version, name, applied_at FROM " + migrationsTable + " ORDER BY version").Scan(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+	latest := 0
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].Version
+	}
+	applied := make(map[int]MigrationStatus, len(rows))
+	for _, row := range rows {
+		if row.Version > latest {
+			return nil, nil, fmt.Errorf("%w: it has migration %04d_%s, this binary knows up to %04d", ErrSchemaTooNew, row.Version, row.Name, latest)
+		}
+		applied[row.Version] = row
+	}
+	return migrations, applied, nil
+}
+
+// ensureMigrationsTable creates the migrations table. A schema created before migrations were
+// versioned is recorded at the version its tables match, so its migrations aren't applied twice.
+func ensureMigrationsTable(db *gorm.DB, migrations []Migration) error {
+	if db.Migrator().HasTable(migrationsTable) {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		adopted := unversionedSchemaVersion(tx)
+		err := tx.Exec("CREATE TABLE " + migrationsTable + " (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at TEXT NOT NULL)").Error
+		if err != nil {
+			return err
+		}
+		for _, migration := range migrations {
+			if migration.Version > adopted {
+				break
+			}
+			if err := recordMigration(tx, migration); err != nil {
+				return err
+			}
+			log.Printf("Recorded existing schema as migration %04d_%s", migration.Version, migration.Name)
+		}
+		return nil
+	})
+}
+
+// recordMigration marks a migration as applied now.
+func recordMigration(db *gorm.DB, migration Migration) error {
+	return db.Exec("INSERT INTO "+migrationsTable+" (version, name, applied_at) VALUES (?, ?, ?)",
+		migration.Version, migration.Name, time.Now().UTC().Format(time.RFC3339)).Error
+}
+
+// unversionedSchemaVersion returns the migration version matching the tables of a database that
+// has no migrations table: 0 when empty, 1 before tenants, 2 before the daily rollup, 3 after.
+func unversionedSchemaVersion(db *gorm.DB) int {
+	switch {
+	case !db.Migrator().HasTable("products"):
+		return 0
+	case !db.Migrator().HasColumn("products", "tenant_id"):
+		return 1
+	case !db.Migrator().HasTable("daily_product_sales"):
+		return 2
+	default:
+		return 3
+	}
+}
+
+// execScript runs the statements of a migration file, which are separated by semicolons and may be
+// preceded by -- comment lines.
+func execScript(db *gorm.DB, script string) error {
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			lines = append(lines, line)
+		}
+	}
+	for _, statement := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if statement = strings.TrimSpace(statement); statement == "" {
+			continue
+		}
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
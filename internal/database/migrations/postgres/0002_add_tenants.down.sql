@@ -0,0 +1,20 @@
+-- Only the default tenant's rows fit tables without tenants; the rows of other tenants are dropped.
+DELETE FROM order_items WHERE tenant_id <> 'default';
+DELETE FROM orders WHERE tenant_id <> 'default';
+DELETE FROM customers WHERE tenant_id <> 'default';
+DELETE FROM products WHERE tenant_id <> 'default';
+
+ALTER TABLE products DROP CONSTRAINT products_pkey;
+ALTER TABLE products DROP COLUMN tenant_id;
+ALTER TABLE products ADD PRIMARY KEY (product_id);
+
+ALTER TABLE customers DROP CONSTRAINT customers_pkey;
+ALTER TABLE customers DROP COLUMN tenant_id;
+ALTER TABLE customers ADD PRIMARY KEY (customer_id);
+
+ALTER TABLE orders DROP CONSTRAINT orders_pkey;
+ALTER TABLE orders DROP COLUMN tenant_id;
+ALTER TABLE orders ADD PRIMARY KEY (order_id);
+
+DROP INDEX idx_order_items_tenant_id;
+ALTER TABLE order_items DROP COLUMN tenant_id;
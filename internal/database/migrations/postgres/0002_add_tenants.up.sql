@@ -0,0 +1,20 @@
+-- Every row belongs to a tenant, which leads the primary keys. Existing rows belong to the default
+-- tenant.
+ALTER TABLE products ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE products ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE products DROP CONSTRAINT products_pkey;
+ALTER TABLE products ADD PRIMARY KEY (tenant_id, product_id);
+
+ALTER TABLE customers ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE customers ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE customers DROP CONSTRAINT customers_pkey;
+ALTER TABLE customers ADD PRIMARY KEY (tenant_id, customer_id);
+
+ALTER TABLE orders ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE orders ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE orders DROP CONSTRAINT orders_pkey;
+ALTER TABLE orders ADD PRIMARY KEY (tenant_id, order_id);
+
+ALTER TABLE order_items ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE order_items ALTER COLUMN tenant_id DROP DEFAULT;
+CREATE INDEX idx_order_items_tenant_id ON order_items (tenant_id);
@@ -0,0 +1,25 @@
+-- Order items summed per tenant, UTC sale date, product, region, category and payment method,
+-- backfilled from the existing sales.
+CREATE TABLE daily_product_sales (
+	tenant_id TEXT,
+	sale_date TEXT,
+	product_id TEXT,
+	region TEXT,
+	category TEXT,
+	payment_method TEXT,
+	quantity INTEGER,
+	gross_revenue DECIMAL,
+	net_revenue DECIMAL,
+	orders INTEGER,
+	PRIMARY KEY (tenant_id, sale_date, product_id, region, category, payment_method)
+);
+CREATE INDEX idx_daily_product_sales_product_id ON daily_product_sales (product_id);
+
+INSERT INTO daily_product_sales (tenant_id, sale_date, product_id, region, category, payment_method, quantity, gross_revenue, net_revenue, orders)
+SELECT order_items.tenant_id, substr(orders.date_of_sale, 1, 10), order_items.product_id, orders.region, products.category, orders.payment_method,
+	SUM(order_items.quantity_sold), SUM(order_items.quantity_sold * products.unit_price),
+	SUM(order_items.quantity_sold * products.unit_price * (1 - order_items.discount)), COUNT(DISTINCT order_items.order_id)
+FROM order_items
+JOIN orders ON orders.tenant_id = order_items.tenant_id AND orders.order_id = order_items.order_id
+JOIN products ON products.tenant_id = order_items.tenant_id AND products.product_id = order_items.product_id
+GROUP BY order_items.tenant_id, substr(orders.date_of_sale, 1, 10), order_items.product_id, orders.region, products.category, orders.payment_method;
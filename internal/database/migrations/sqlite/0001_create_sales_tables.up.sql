@@ -0,0 +1,38 @@
+-- Products, customers, orders and their line items, as first imported from the sales CSV.
+CREATE TABLE products (
+	product_id TEXT,
+	product_name TEXT,
+	category TEXT,
+	unit_price REAL,
+	PRIMARY KEY (product_id)
+);
+
+CREATE TABLE customers (
+	customer_id TEXT,
+	customer_name TEXT,
+	customer_email TEXT,
+	customer_address TEXT,
+	PRIMARY KEY (customer_id)
+);
+
+CREATE TABLE orders (
+	order_id TEXT,
+	customer_id TEXT,
+	date_of_sale TEXT,
+	shipping_cost REAL,
+	payment_method TEXT,
+	region TEXT,
+	PRIMARY KEY (order_id)
+);
+CREATE INDEX idx_orders_customer_id ON orders (customer_id);
+
+CREATE TABLE order_items (
+	order_item_id INTEGER,
+	order_id TEXT,
+	product_id TEXT,
+	quantity_sold INTEGER,
+	discount REAL,
+	PRIMARY KEY (order_item_id)
+);
+CREATE INDEX idx_order_items_order_id ON order_items (order_id);
+CREATE INDEX idx_order_items_product_id ON order_items (product_id);
@@ -0,0 +1,58 @@
+-- Only the default tenant's rows fit tables without tenants; the rows of other tenants are dropped.
+ALTER TABLE products RENAME TO products_tenanted;
+ALTER TABLE customers RENAME TO customers_tenanted;
+ALTER TABLE orders RENAME TO orders_tenanted;
+ALTER TABLE order_items RENAME TO order_items_tenanted;
+
+CREATE TABLE products (
+	product_id TEXT,
+	product_name TEXT,
+	category TEXT,
+	unit_price REAL,
+	PRIMARY KEY (product_id)
+);
+
+CREATE TABLE customers (
+	customer_id TEXT,
+	customer_name TEXT,
+	customer_email TEXT,
+	customer_address TEXT,
+	PRIMARY KEY (customer_id)
+);
+
+CREATE TABLE orders (
+	order_id TEXT,
+	customer_id TEXT,
+	date_of_sale TEXT,
+	shipping_cost REAL,
+	payment_method TEXT,
+	region TEXT,
+	PRIMARY KEY (order_id)
+);
+
+CREATE TABLE order_items (
+	order_item_id INTEGER,
+	order_id TEXT,
+	product_id TEXT,
+	quantity_sold INTEGER,
+	discount REAL,
+	PRIMARY KEY (order_item_id)
+);
+
+INSERT INTO products (product_id, product_name, category, unit_price)
+SELECT product_id, product_name, category, unit_price FROM products_tenanted WHERE tenant_id = 'default';
+INSERT INTO customers (customer_id, customer_name, customer_email, customer_address)
+SELECT customer_id, customer_name, customer_email, customer_address FROM customers_tenanted WHERE tenant_id = 'default';
+INSERT INTO orders (order_id, customer_id, date_of_sale, shipping_cost, payment_method, region)
+SELECT order_id, customer_id, date_of_sale, shipping_cost, payment_method, region FROM orders_tenanted WHERE tenant_id = 'default';
+INSERT INTO order_items (order_item_id, order_id, product_id, quantity_sold, discount)
+SELECT order_item_id, order_id, product_id, quantity_sold, discount FROM order_items_tenanted WHERE tenant_id = 'default';
+
+DROP TABLE products_tenanted;
+DROP TABLE customers_tenanted;
+DROP TABLE orders_tenanted;
+DROP TABLE order_items_tenanted;
+
+CREATE INDEX idx_orders_customer_id ON orders (customer_id);
+CREATE INDEX idx_order_items_order_id ON order_items (order_id);
+CREATE INDEX idx_order_items_product_id ON order_items (product_id);
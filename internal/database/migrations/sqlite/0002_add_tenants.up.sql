@@ -0,0 +1,63 @@
+-- Every row belongs to a tenant, which leads the primary keys. Existing rows belong to the default
+-- tenant. SQLite can't change a primary key in place, so the tables are rebuilt.
+ALTER TABLE products RENAME TO products_untenanted;
+ALTER TABLE customers RENAME TO customers_untenanted;
+ALTER TABLE orders RENAME TO orders_untenanted;
+ALTER TABLE order_items RENAME TO order_items_untenanted;
+
+CREATE TABLE products (
+	tenant_id TEXT,
+	product_id TEXT,
+	product_name TEXT,
+	category TEXT,
+	unit_price REAL,
+	PRIMARY KEY (tenant_id, product_id)
+);
+
+CREATE TABLE customers (
+	tenant_id TEXT,
+	customer_id TEXT,
+	customer_name TEXT,
+	customer_email TEXT,
+	customer_address TEXT,
+	PRIMARY KEY (tenant_id, customer_id)
+);
+
+CREATE TABLE orders (
+	tenant_id TEXT,
+	order_id TEXT,
+	customer_id TEXT,
+	date_of_sale TEXT,
+	shipping_cost REAL,
+	payment_method TEXT,
+	region TEXT,
+	PRIMARY KEY (tenant_id, order_id)
+);
+
+CREATE TABLE order_items (
+	order_item_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	tenant_id TEXT,
+	order_id TEXT,
+	product_id TEXT,
+	quantity_sold INTEGER,
+	discount REAL
+);
+
+INSERT INTO products (tenant_id, product_id, product_name, category, unit_price)
+SELECT 'default', product_id, product_name, category, unit_price FROM products_untenanted;
+INSERT INTO customers (tenant_id, customer_id, customer_name, customer_email, customer_address)
+SELECT 'default', customer_id, customer_name, customer_email, customer_address FROM customers_untenanted;
+INSERT INTO orders (tenant_id, order_id, customer_id, date_of_sale, shipping_cost, payment_method, region)
+SELECT 'default', order_id, customer_id, date_of_sale, shipping_cost, payment_method, region FROM orders_untenanted;
+INSERT INTO order_items (order_item_id, tenant_id, order_id, product_id, quantity_sold, discount)
+SELECT order_item_id, 'default', order_id, product_id, quantity_sold, discount FROM order_items_untenanted;
+
+DROP TABLE products_untenanted;
+DROP TABLE customers_untenanted;
+DROP TABLE orders_untenanted;
+DROP TABLE order_items_untenanted;
+
+CREATE INDEX idx_orders_customer_id ON orders (customer_id);
+CREATE INDEX idx_order_items_tenant_id ON order_items (tenant_id);
+CREATE INDEX idx_order_items_order_id ON order_items (order_id);
+CREATE INDEX idx_order_items_product_id ON order_items (product_id);
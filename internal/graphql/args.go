@@ -0,0 +1,101 @@
+package graphql
+
+import (
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+type dateRangeInput struct {
+	StartDate string
+	EndDate   string
+	Tz        *string
+}
+
+type salesFilterInput struct {
+	Categories     *[]string
+	Regions        *[]string
+	PaymentMethods *[]string
+	CustomerIds    *[]graphqlgo.ID
+	MinPrice       *float64
+	MaxPrice       *float64
+}
+
+type pageArgs struct {
+	Limit  int32
+	Offset int32
+	SortBy string
+	Desc   bool
+}
+
+// toDateRange validates and converts a date range argument; nil leaves the range open.
+func toDateRange(input *dateRangeInput) (models.DateRange, error) {
+	if input == nil {
+		return models.DateRange{Location: time.UTC}, nil
+	}
+
+	tz := ""
+	if input.Tz != nil {
+		tz = *input.Tz
+	}
+	return utils.ValidateDateRange(input.StartDate, input.EndDate, tz)
+}
+
+// toSalesFilter validates and converts a filter argument.
+func toSalesFilter(input *salesFilterInput) (models.SalesFilter, error) {
+	if input == nil {
+		return models.SalesFilter{}, nil
+	}
+
+	filter := models.SalesFilter{MinPrice: input.MinPrice, MaxPrice: input.MaxPrice}
+	if input.Categories != nil {
+		filter.Categories = *input.Categories
+	}
+	if input.Regions != nil {
+		filter.Regions = *input.Regions
+	}
+	if input.PaymentMethods != nil {
+		filter.PaymentMethods = *input.PaymentMethods
+	}
+	if input.CustomerIds != nil {
+		for _, id := range *input.CustomerIds {
+			filter.CustomerIDs = append(filter.CustomerIDs, string(id))
+		}
+	}
+	return utils.ValidateSalesFilter(filter)
+}
+
+// toPageRequest validates list arguments; sortBy must be one of the sortable API field names.
+func toPageRequest(args pageArgs, sortable map[string]string) (models.PageRequest, error) {
+	if args.Limit < 1 || args.Limit > constants.MaxPageSize {
+		return models.PageRequest{}, constants.ErrInvalidPageLimit
+	}
+	if args.Offset < 0 {
+		return models.PageRequest{}, constants.ErrInvalidOffset
+	}
+	column, ok := sortable[args.SortBy]
+	if !ok {
+		return models.PageRequest{}, constants.ErrInvalidSortBy
+	}
+
+	return models.PageRequest{
+		Limit:  int(args.Limit),
+		Offset: int(args.Offset),
+		Sort:   []models.SortField{{Field: args.SortBy, Column: column, Desc: args.Desc}},
+	}, nil
+}
+
+// toLimit validates a top-n argument with the REST 'n' rule.
+func toLimit(n int32) (int, error) {
+	return utils.ParseLimit(strconv.Itoa(int(n)))
+}
+
+// enumValue converts a GraphQL enum value such as PAYMENT_METHOD to the API value payment_method.
+func enumValue(value string) string {
+	return strings.ToLower(value)
+}
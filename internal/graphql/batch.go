@@ -0,0 +1,43 @@
+package graphql
+
+import "sync"
+
+// batch loads the values of a fixed set of sibling keys with a single query the first time any of
+// them is requested. List resolvers create one batch per relation for all their elements, so a
+// relation costs one query per level of the result instead of one per element.
+type batch[K comparable, V any] struct {
+	once   sync.Once
+	keys   []K
+	load   func(keys []K) (map[K]V, error)
+	values map[K]V
+	err    error
+}
+
+func newBatch[K comparable, V any](keys []K, load func(keys []K) (map[K]V, error)) *batch[K, V] {
+	return &batch[K, V]{keys: keys, load: load}
+}
+
+// get returns the value loaded for key, or the zero value when the key has none.
+func (b *batch[K, V]) get(key K) (V, error) {
+	b.once.Do(func() {
+		b.values, b.err = b.load(unique(b.keys))
+	})
+	if b.err != nil {
+		var zero V
+		return zero, b.err
+	}
+	return b.values[key], nil
+}
+
+// unique drops repeated keys, keeping the first occurrence.
+func unique[K comparable](keys []K) []K {
+	seen := make(map[K]bool, len(keys))
+	result := make([]K, 0, len(keys))
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, key)
+		}
+	}
+	return result
+}
@@ -0,0 +1,219 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"sales/internal/repository/memory"
+	"sales/internal/services"
+	"sales/internal/tenant"
+	"sales/pkg/cache"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+// testCSVFile is the sample data the schema is tested on.
+const testCSVFile = "../../data/sales_data.csv"
+
+// testContext acts for the default tenant.
+var testContext = tenant.NewContext(context.Background(), tenant.Tenant{ID: constants.DefaultTenant})
+
+// countingStore counts the calls of the repository methods the resolvers load relations with.
+type countingStore struct {
+	repository.Store
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (s *countingStore) count(method string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[method]++
+}
+
+func (s *countingStore) reset() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	calls := s.calls
+	s.calls = map[string]int{}
+	return calls
+}
+
+func (s *countingStore) GetProductsByIDs(ctx context.Context, productIDs []string) ([]models.Product, error) {
+	s.count("GetProductsByIDs")
+	return s.Store.GetProductsByIDs(ctx, productIDs)
+}
+
+func (s *countingStore) GetCustomersByIDs(ctx context.Context, customerIDs []string) ([]models.Customer, error) {
+	s.count("GetCustomersByIDs")
+	return s.Store.GetCustomersByIDs(ctx, customerIDs)
+}
+
+func (s *countingStore) GetOrdersByIDs(ctx context.Context, orderIDs []string) ([]models.Order, error) {
+	s.count("GetOrdersByIDs")
+	return s.Store.GetOrdersByIDs(ctx, orderIDs)
+}
+
+func (s *countingStore) ListOrdersByCustomers(ctx context.Context, customerIDs []string) ([]models.Order, error) {
+	s.count("ListOrdersByCustomers")
+	return s.Store.ListOrdersByCustomers(ctx, customerIDs)
+}
+
+func (s *countingStore) ListOrderItemsByOrders(ctx context.Context, orderIDs []string) ([]models.OrderItem, error) {
+	s.count("ListOrderItemsByOrders")
+	return s.Store.ListOrderItemsByOrders(ctx, orderIDs)
+}
+
+func (s *countingStore) GetProductSalesTotals(ctx context.Context, productIDs []string, dateRange models.DateRange, filter models.SalesFilter) ([]models.ProductSalesTotal, error) {
+	s.count("GetProductSalesTotals")
+	return s.Store.GetProductSalesTotals(ctx, productIDs, dateRange, filter)
+}
+
+// newTestSchema returns the schema over a counting in-memory store holding testCSVFile.
+func newTestSchema(t *testing.T) (*graphqlgo.Schema, *countingStore) {
+	t.Helper()
+	store := &countingStore{Store: memory.NewStore(), calls: map[string]int{}}
+	svc := services.New(store, services.NewResultCache(cache.NewLRU(constants.CacheEntries, constants.CacheTTL)))
+	if err := svc.Refresh.RefreshDatabase(testContext, testCSVFile); err != nil {
+		t.Fatal(err)
+	}
+	store.reset()
+	return NewSchema(svc.Entities, svc.Analytics), store
+}
+
+func TestNestedQueryLoadsEachRelationOnce(t *testing.T) {
+	schema, store := newTestSchema(t)
+
+	response := schema.Exec(testContext, `{
+		orders(limit: 100) {
+			items {
+				id
+				customer { id orders { id } }
+				items { quantitySold product { id sales { quantitySold } } }
+			}
+		}
+	}`, "", nil)
+	if len(response.Errors) > 0 {
+		t.Fatal(response.Errors)
+	}
+
+	// orders→customer, customer→orders, orders→items, items→product and product→sales
+	for method, want := range map[string]int{
+		"GetCustomersByIDs":      1,
+		"ListOrdersByCustomers":  1,
+		"ListOrderItemsByOrders": 1,
+		"GetProductsByIDs":       1,
+		"GetProductSalesTotals":  1,
+		"GetOrdersByIDs":         0,
+	} {
+		if got := store.calls[method]; got != want {
+			t.Errorf("%s called %d times, want %d", method, got, want)
+		}
+	}
+
+	var data struct {
+		Orders struct {
+			Items []struct {
+				ID       string
+				Customer struct {
+					ID     string
+					Orders []struct{ ID string }
+				}
+				Items []struct {
+					QuantitySold int
+					Product      struct {
+						ID    string
+						Sales struct{ QuantitySold int }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(response.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Orders.Items) != 6 {
+		t.Fatalf("%d orders, want 6", len(data.Orders.Items))
+	}
+	for _, order := range data.Orders.Items {
+		ids := make([]string, len(order.Customer.Orders))
+		for i, customerOrder := range order.Customer.Orders {
+			ids[i] = customerOrder.ID
+		}
+		if !slices.Contains(ids, order.ID) {
+			t.Errorf("order %s is missing from the orders %v of its customer %s", order.ID, ids, order.Customer.ID)
+		}
+		if len(order.Items) != 1 || order.Items[0].Product.Sales.QuantitySold < order.Items[0].QuantitySold {
+			t.Errorf("order %s items %+v", order.ID, order.Items)
+		}
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	schema, store := newTestSchema(t)
+
+	query := "{ orders { items { id } } }"
+	for depth := 0; depth < constants.GraphQLMaxDepth; depth++ {
+		query = strings.Replace(query, "{ id }", "{ customer { orders { id } } }", 1)
+	}
+	response := schema.Exec(testContext, query, "", nil)
+	if len(response.Errors) == 0 || !strings.Contains(response.Errors[0].Message, "depth") {
+		t.Errorf("a query nested past the maximum depth: %v", response.Errors)
+	}
+	if calls := store.reset(); len(calls) > 0 {
+		t.Errorf("a rejected query loaded %v", calls)
+	}
+
+	if response := schema.Exec(testContext, "{ orders { items { customer { orders { id } } } } }", "", nil); len(response.Errors) > 0 {
+		t.Errorf("a query within the maximum depth: %v", response.Errors)
+	}
+}
+
+func TestArgumentErrors(t *testing.T) {
+	schema, _ := newTestSchema(t)
+	const allTime = `range: {startDate: "2023-01-01", endDate: "2024-12-31"}`
+
+	for _, test := range []struct {
+		name  string
+		query string
+		err   error
+		field string
+	}{
+		{"a page limit of 0", `{ products(limit: 0) { total } }`, constants.ErrInvalidPageLimit, ""},
+		{"a negative offset", `{ customers(offset: -1) { total } }`, constants.ErrInvalidOffset, ""},
+		{"an unknown sort field", `{ orders(sortBy: "secret") { total } }`, constants.ErrInvalidSortBy, ""},
+		{"a top n of 0", `{ topProducts(n: 0, ` + allTime + `) { id } }`, constants.ErrInvalidLimit, ""},
+		{"a malformed start date", `{ topProducts(n: 1, range: {startDate: "2024-13-01", endDate: "2024-12-31"}) { id } }`, nil, constants.StartDate},
+		{"an unknown timezone", `{ topProducts(n: 1, range: {startDate: "2024-01-01", endDate: "2024-12-31", tz: "Mars/Base"}) { id } }`, nil, constants.Timezone},
+		{"a negative price", `{ topProducts(n: 1, ` + allTime + `, filter: {minPrice: -1}) { id } }`, constants.ErrInvalidMinPrice, ""},
+		{"the same pivot dimensions", `{ salesBreakdown(rows: REGION, columns: REGION, ` + allTime + `) { grandTotal } }`, nil, constants.Columns},
+		{"a nested range", `{ products { items { sales(range: {startDate: "2024-02-01", endDate: "2024-01-01"}) { orders } } } }`, nil, constants.EndDate},
+	} {
+		response := schema.Exec(testContext, test.query, "", nil)
+		if len(response.Errors) == 0 || response.Errors[0].ResolverError == nil {
+			t.Errorf("%s: %v, want a resolver error", test.name, response.Errors)
+			continue
+		}
+		err := response.Errors[0].ResolverError
+		if test.err != nil && !errors.Is(err, test.err) {
+			t.Errorf("%s: %v, want %v", test.name, err, test.err)
+		}
+		var violations models.ValidationErrors
+		if test.field != "" && (!errors.As(err, &violations) || violations[0].Field != test.field) {
+			t.Errorf("%s: %v, want a violation of %s", test.name, err, test.field)
+		}
+	}
+
+	// enum arguments are checked by the schema before any resolver runs
+	if response := schema.Exec(testContext, `{ topProductsBy(dimension: PLANET, n: 1, `+allTime+`) { key } }`, "", nil); len(response.Errors) == 0 || response.Errors[0].ResolverError != nil {
+		t.Errorf("an unknown dimension: %v, want a validation error", response.Errors)
+	}
+}
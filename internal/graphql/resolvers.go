@@ -0,0 +1,247 @@
+// Package graphql serves the sales model and its aggregates over GraphQL. Object resolvers load
+// their relations in batches shared by sibling objects, so nested selections cost one query per
+// relation and level rather than one per object.
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"sales/internal/services"
+	"sales/internal/utils"
+	"sort"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// NewSchema parses the schema and binds it to resolvers reading through the given services,
+// scoped to the tenant carried by the context the schema is executed with.
+func NewSchema(entities *services.EntityService, analytics *services.AnalyticsService) *graphqlgo.Schema {
+	resolver := &Resolver{entities: entities, analytics: analytics}
+	return graphqlgo.MustParseSchema(schema, resolver, graphqlgo.MaxDepth(constants.GraphQLMaxDepth))
+}
+
+// Resolver resolves the fields of the Query type.
+type Resolver struct {
+	entities  *services.EntityService
+	analytics *services.AnalyticsService
+}
+
+// loader returns the loader of the relations of objects resolved in ctx.
+func (r *Resolver) loader(ctx context.Context) *loader {
+	return &loader{ctx: ctx, entities: r.entities, analytics: r.analytics}
+}
+
+type salesArgs struct {
+	Range  dateRangeInput
+	Filter *salesFilterInput
+}
+
+func (args salesArgs) parse() (models.DateRange, models.SalesFilter, error) {
+	dateRange, err := toDateRange(&args.Range)
+	if err != nil {
+		return models.DateRange{}, models.SalesFilter{}, err
+	}
+	filter, err := toSalesFilter(args.Filter)
+	return dateRange, filter, err
+}
+
+func (r *Resolver) Product(ctx context.Context, args struct{ ID graphqlgo.ID }) (*productResolver, error) {
+	product, err := r.entities.GetProduct(ctx, string(args.ID))
+	if errors.Is(err, constants.ErrProductNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newProductResolvers(r.loader(ctx), []models.Product{product})[0], nil
+}
+
+func (r *Resolver) Products(ctx context.Context, args pageArgs) (*productPageResolver, error) {
+	page, err := toPageRequest(args, repository.ProductSortColumns)
+	if err != nil {
+		return nil, err
+	}
+	products, total, err := r.entities.ListProducts(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	return &productPageResolver{items: newProductResolvers(r.loader(ctx), products), total: total}, nil
+}
+
+func (r *Resolver) Customer(ctx context.Context, args struct{ ID graphqlgo.ID }) (*customerResolver, error) {
+	customer, err := r.entities.GetCustomer(ctx, string(args.ID))
+	if errors.Is(err, constants.ErrCustomerNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newCustomerResolvers(r.loader(ctx), []models.Customer{customer})[0], nil
+}
+
+func (r *Resolver) Customers(ctx context.Context, args pageArgs) (*customerPageResolver, error) {
+	page, err := toPageRequest(args, repository.CustomerSortColumns)
+	if err != nil {
+		return nil, err
+	}
+	customers, total, err := r.entities.ListCustomers(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	return &customerPageResolver{items: newCustomerResolvers(r.loader(ctx), customers), total: total}, nil
+}
+
+func (r *Resolver) Order(ctx context.Context, args struct{ ID graphqlgo.ID }) (*orderResolver, error) {
+	orders, err := r.entities.GetOrdersByIDs(ctx, []string{string(args.ID)})
+	if err != nil || len(orders) == 0 {
+		return nil, err
+	}
+	return newOrderResolvers(r.loader(ctx), orders)[0], nil
+}
+
+func (r *Resolver) Orders(ctx context.Context, args pageArgs) (*orderPageResolver, error) {
+	page, err := toPageRequest(args, repository.OrderSortColumns)
+	if err != nil {
+		return nil, err
+	}
+	orders, total, err := r.entities.ListOrders(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	return &orderPageResolver{items: newOrderResolvers(r.loader(ctx), orders), total: total}, nil
+}
+
+func (r *Resolver) TopProducts(ctx context.Context, args struct {
+	N int32
+	salesArgs
+}) ([]*productResolver, error) {
+	n, err := toLimit(args.N)
+	if err != nil {
+		return nil, err
+	}
+	dateRange, filter, err := args.parse()
+	if err != nil {
+		return nil, err
+	}
+	products, err := r.analytics.GetTopProductsOverall(ctx, n, dateRange, filter)
+	if err != nil {
+		return nil, err
+	}
+	return newProductResolvers(r.loader(ctx), products), nil
+}
+
+func (r *Resolver) TopProductsBy(ctx context.Context, args struct {
+	Dimension string
+	N         int32
+	salesArgs
+}) ([]*productGroupResolver, error) {
+	dimension := enumValue(args.Dimension)
+	if err := utils.ValidateDimension(dimension); err != nil {
+		return nil, err
+	}
+	n, err := toLimit(args.N)
+	if err != nil {
+		return nil, err
+	}
+	dateRange, filter, err := args.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	topProducts := r.analytics.GetTopProductsByCategory
+	if dimension == constants.DimensionRegion {
+		topProducts = r.analytics.GetTopProductsByRegion
+	}
+	groups, err := topProducts(ctx, n, dateRange, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// every group's products share one set of batches
+	var products []models.Product
+	keys := make([]string, 0, len(groups))
+	for key, group := range groups {
+		keys = append(keys, key)
+		products = append(products, group...)
+	}
+	sort.Strings(keys)
+	resolvers := newProductResolvers(r.loader(ctx), products)
+	byID := make(map[string]*productResolver, len(resolvers))
+	for _, resolver := range resolvers {
+		byID[resolver.product.ProductID] = resolver
+	}
+
+	result := make([]*productGroupResolver, len(keys))
+	for i, key := range keys {
+		group := &productGroupResolver{key: key, products: []*productResolver{}}
+		for _, product := range groups[key] {
+			group.products = append(group.products, byID[product.ProductID])
+		}
+		result[i] = group
+	}
+	return result, nil
+}
+
+func (r *Resolver) SalesBreakdown(ctx context.Context, args struct {
+	Rows    string
+	Columns string
+	Metric  string
+	Percent *string
+	salesArgs
+}) (*pivotTableResolver, error) {
+	rows, columns, percent := enumValue(args.Rows), enumValue(args.Columns), ""
+	if args.Percent != nil {
+		percent = enumValue(*args.Percent)
+	}
+	metric, err := utils.ValidatePivotParams(rows, columns, enumValue(args.Metric), percent)
+	if err != nil {
+		return nil, err
+	}
+	dateRange, filter, err := args.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := r.analytics.GetPivotTable(ctx, rows, columns, metric, percent, dateRange, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &pivotTableResolver{table: table}, nil
+}
+
+func (r *Resolver) SalesTimeSeries(ctx context.Context, args struct {
+	Interval  string
+	ProductID *graphqlgo.ID
+	salesArgs
+}) ([]*salesBucketResolver, error) {
+	interval, err := utils.ValidateInterval(enumValue(args.Interval))
+	if err != nil {
+		return nil, err
+	}
+	dateRange, filter, err := args.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	productID := ""
+	if args.ProductID != nil {
+		productID = string(*args.ProductID)
+	}
+	buckets, err := r.analytics.GetSalesByPeriod(ctx, interval, productID, dateRange, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*salesBucketResolver, len(buckets))
+	for i, bucket := range buckets {
+		result[i] = &salesBucketResolver{bucket: bucket}
+	}
+	return result, nil
+}
@@ -0,0 +1,352 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sales/internal/models"
+	"sales/internal/services"
+	"sync"
+	"time"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+// loader loads the relations of objects through the services, in the context of the query
+// that resolved them.
+type loader struct {
+	ctx       context.Context
+	entities  *services.EntityService
+	analytics *services.AnalyticsService
+}
+
+// productSet holds the batches shared by sibling products.
+type productSet struct {
+	loader *loader
+	ids    []string
+
+	mu sync.Mutex
+	// sales batches keyed by the range and filter arguments they were requested with
+	sales map[string]*batch[string, models.ProductSalesTotal]
+}
+
+type productResolver struct {
+	product models.Product
+	set     *productSet
+}
+
+func newProductResolvers(l *loader, products []models.Product) []*productResolver {
+	set := &productSet{loader: l, sales: map[string]*batch[string, models.ProductSalesTotal]{}}
+	resolvers := make([]*productResolver, len(products))
+	for i, product := range products {
+		set.ids = append(set.ids, product.ProductID)
+		resolvers[i] = &productResolver{product: product, set: set}
+	}
+	return resolvers
+}
+
+func (r *productResolver) ID() graphqlgo.ID { return graphqlgo.ID(r.product.ProductID) }
+func (r *productResolver) Name() string     { return r.product.ProductName }
+func (r *productResolver) Category() string { return r.product.Category }
+func (r *productResolver) UnitPrice() float64 {
+	return r.product.UnitPrice
+}
+
+func (r *productResolver) Sales(args struct {
+	Range  *dateRangeInput
+	Filter *salesFilterInput
+}) (*salesTotalsResolver, error) {
+	dateRange, err := toDateRange(args.Range)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := toSalesFilter(args.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s|%+v", dateRange, filter)
+	r.set.mu.Lock()
+	sales, ok := r.set.sales[key]
+	if !ok {
+		l := r.set.loader
+		sales = newBatch(r.set.ids, func(ids []string) (map[string]models.ProductSalesTotal, error) {
+			totals, err := l.analytics.GetProductSalesTotals(l.ctx, ids, dateRange, filter)
+			if err != nil {
+				return nil, err
+			}
+			byProduct := make(map[string]models.ProductSalesTotal, len(totals))
+			for _, total := range totals {
+				byProduct[total.ProductID] = total
+			}
+			return byProduct, nil
+		})
+		r.set.sales[key] = sales
+	}
+	r.set.mu.Unlock()
+
+	total, err := sales.get(r.product.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	return &salesTotalsResolver{total: total}, nil
+}
+
+type salesTotalsResolver struct {
+	total models.ProductSalesTotal
+}
+
+func (r *salesTotalsResolver) QuantitySold() int32 { return int32(r.total.QuantitySold) }
+func (r *salesTotalsResolver) Revenue() float64    { return r.total.Revenue }
+func (r *salesTotalsResolver) Orders() int32       { return int32(r.total.Orders) }
+
+type customerResolver struct {
+	customer models.Customer
+	orders   *batch[string, []*orderResolver]
+}
+
+func newCustomerResolvers(l *loader, customers []models.Customer) []*customerResolver {
+	ids := make([]string, len(customers))
+	for i, customer := range customers {
+		ids[i] = customer.CustomerID
+	}
+
+	orders := newBatch(ids, func(ids []string) (map[string][]*orderResolver, error) {
+		orders, err := l.entities.ListOrdersByCustomers(l.ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		byCustomer := map[string][]*orderResolver{}
+		for _, order := range newOrderResolvers(l, orders) {
+			byCustomer[order.order.CustomerID] = append(byCustomer[order.order.CustomerID], order)
+		}
+		return byCustomer, nil
+	})
+
+	resolvers := make([]*customerResolver, len(customers))
+	for i, customer := range customers {
+		resolvers[i] = &customerResolver{customer: customer, orders: orders}
+	}
+	return resolvers
+}
+
+func (r *customerResolver) ID() graphqlgo.ID { return graphqlgo.ID(r.customer.CustomerID) }
+func (r *customerResolver) Name() string     { return r.customer.CustomerName }
+func (r *customerResolver) Email() string    { return r.customer.CustomerEmail }
+func (r *customerResolver) Address() string  { return r.customer.CustomerAddress }
+
+func (r *customerResolver) Orders() ([]*orderResolver, error) {
+	orders, err := r.orders.get(r.customer.CustomerID)
+	if orders == nil && err == nil {
+		orders = []*orderResolver{}
+	}
+	return orders, err
+}
+
+type orderResolver struct {
+	order     models.Order
+	customers *batch[string, *customerResolver]
+	items     *batch[string, []*orderItemResolver]
+}
+
+func newOrderResolvers(l *loader, orders []models.Order) []*orderResolver {
+	orderIDs := make([]string, len(orders))
+	customerIDs := make([]string, len(orders))
+	for i, order := range orders {
+		orderIDs[i] = order.OrderID
+		customerIDs[i] = order.CustomerID
+	}
+
+	customers := newBatch(customerIDs, func(ids []string) (map[string]*customerResolver, error) {
+		customers, err := l.entities.GetCustomersByIDs(l.ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[string]*customerResolver, len(customers))
+		for _, customer := range newCustomerResolvers(l, customers) {
+			byID[customer.customer.CustomerID] = customer
+		}
+		return byID, nil
+	})
+
+	items := newBatch(orderIDs, func(ids []string) (map[string][]*orderItemResolver, error) {
+		items, err := l.entities.ListOrderItemsByOrders(l.ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		byOrder := map[string][]*orderItemResolver{}
+		for _, item := range newOrderItemResolvers(l, items) {
+			byOrder[item.item.OrderID] = append(byOrder[item.item.OrderID], item)
+		}
+		return byOrder, nil
+	})
+
+	resolvers := make([]*orderResolver, len(orders))
+	for i, order := range orders {
+		resolvers[i] = &orderResolver{order: order, customers: customers, items: items}
+	}
+	return resolvers
+}
+
+func (r *orderResolver) ID() graphqlgo.ID { return graphqlgo.ID(r.order.OrderID) }
+func (r *orderResolver) DateOfSale() string {
+	return r.order.DateOfSale.UTC().Format(time.RFC3339)
+}
+func (r *orderResolver) ShippingCost() float64 { return r.order.ShippingCost }
+func (r *orderResolver) PaymentMethod() string { return r.order.PaymentMethod }
+func (r *orderResolver) Region() string        { return r.order.Region }
+
+func (r *orderResolver) Customer() (*customerResolver, error) {
+	return r.customers.get(r.order.CustomerID)
+}
+
+func (r *orderResolver) Items() ([]*orderItemResolver, error) {
+	items, err := r.items.get(r.order.OrderID)
+	if items == nil && err == nil {
+		items = []*orderItemResolver{}
+	}
+	return items, err
+}
+
+type orderItemResolver struct {
+	item     models.OrderItem
+	orders   *batch[string, *orderResolver]
+	products *batch[string, *productResolver]
+}
+
+func newOrderItemResolvers(l *loader, items []models.OrderItem) []*orderItemResolver {
+	orderIDs := make([]string, len(items))
+	productIDs := make([]string, len(items))
+	for i, item := range items {
+		orderIDs[i] = item.OrderID
+		productIDs[i] = item.ProductID
+	}
+
+	orders := newBatch(orderIDs, func(ids []string) (map[string]*orderResolver, error) {
+		orders, err := l.entities.GetOrdersByIDs(l.ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[string]*orderResolver, len(orders))
+		for _, order := range newOrderResolvers(l, orders) {
+			byID[order.order.OrderID] = order
+		}
+		return byID, nil
+	})
+
+	products := newBatch(productIDs, func(ids []string) (map[string]*productResolver, error) {
+		products, err := l.entities.GetProductsByIDs(l.ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[string]*productResolver, len(products))
+		for _, product := range newProductResolvers(l, products) {
+			byID[product.product.ProductID] = product
+		}
+		return byID, nil
+	})
+
+	resolvers := make([]*orderItemResolver, len(items))
+	for i, item := range items {
+		resolvers[i] = &orderItemResolver{item: item, orders: orders, products: products}
+	}
+	return resolvers
+}
+
+func (r *orderItemResolver) ID() graphqlgo.ID {
+	return graphqlgo.ID(fmt.Sprint(r.item.OrderItemID))
+}
+func (r *orderItemResolver) QuantitySold() int32 { return int32(r.item.QuantitySold) }
+func (r *orderItemResolver) Discount() float64   { return r.item.Discount }
+
+func (r *orderItemResolver) Order() (*orderResolver, error) {
+	return r.orders.get(r.item.OrderID)
+}
+
+func (r *orderItemResolver) Product() (*productResolver, error) {
+	return r.products.get(r.item.ProductID)
+}
+
+type productGroupResolver struct {
+	key      string
+	products []*productResolver
+}
+
+func (r *productGroupResolver) Key() string                  { return r.key }
+func (r *productGroupResolver) Products() []*productResolver { return r.products }
+
+type pivotTableResolver struct {
+	table models.PivotTable
+}
+
+func (r *pivotTableResolver) RowDimension() string    { return r.table.RowDimension }
+func (r *pivotTableResolver) ColumnDimension() string { return r.table.ColumnDimension }
+func (r *pivotTableResolver) Metric() string          { return r.table.Metric }
+func (r *pivotTableResolver) Percentage() *string {
+	if r.table.Percentage == "" {
+		return nil
+	}
+	return &r.table.Percentage
+}
+func (r *pivotTableResolver) Columns() []string { return r.table.Columns }
+func (r *pivotTableResolver) ColumnLabels() *[]string {
+	if r.table.ColumnLabels == nil {
+		return nil
+	}
+	return &r.table.ColumnLabels
+}
+func (r *pivotTableResolver) ColumnTotals() []float64 { return r.table.ColumnTotals }
+func (r *pivotTableResolver) GrandTotal() float64     { return r.table.GrandTotal }
+
+func (r *pivotTableResolver) Rows() []*pivotRowResolver {
+	rows := make([]*pivotRowResolver, len(r.table.Rows))
+	for i := range r.table.Rows {
+		rows[i] = &pivotRowResolver{row: r.table.Rows[i]}
+	}
+	return rows
+}
+
+type pivotRowResolver struct {
+	row models.PivotRow
+}
+
+func (r *pivotRowResolver) Key() string { return r.row.Key }
+func (r *pivotRowResolver) Label() *string {
+	if r.row.Label == "" {
+		return nil
+	}
+	return &r.row.Label
+}
+func (r *pivotRowResolver) Values() []float64 { return r.row.Values }
+func (r *pivotRowResolver) Total() float64    { return r.row.Total }
+
+type salesBucketResolver struct {
+	bucket models.SalesBucket
+}
+
+func (r *salesBucketResolver) Period() string      { return r.bucket.Period }
+func (r *salesBucketResolver) QuantitySold() int32 { return int32(r.bucket.QuantitySold) }
+func (r *salesBucketResolver) Revenue() float64    { return r.bucket.Revenue }
+
+type productPageResolver struct {
+	items []*productResolver
+	total int64
+}
+
+func (r *productPageResolver) Items() []*productResolver { return r.items }
+func (r *productPageResolver) Total() int32              { return int32(r.total) }
+
+type customerPageResolver struct {
+	items []*customerResolver
+	total int64
+}
+
+func (r *customerPageResolver) Items() []*customerResolver { return r.items }
+func (r *customerPageResolver) Total() int32               { return int32(r.total) }
+
+type orderPageResolver struct {
+	items []*orderResolver
+	total int64
+}
+
+func (r *orderPageResolver) Items() []*orderResolver { return r.items }
+func (r *orderPageResolver) Total() int32            { return int32(r.total) }
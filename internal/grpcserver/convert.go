@@ -0,0 +1,86 @@
+package grpcserver
+
+import (
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/pkg/salespb"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// groupDimensions names the protobuf group dimensions as the REST and GraphQL APIs do; the
+// unspecified dimension has no name.
+var groupDimensions = map[salespb.GroupDimension]string{
+	salespb.GroupDimension_GROUP_DIMENSION_CATEGORY: constants.DimensionCategory,
+	salespb.GroupDimension_GROUP_DIMENSION_REGION:   constants.DimensionRegion,
+}
+
+func fromSalesFilter(filter *salespb.SalesFilter) models.SalesFilter {
+	if filter == nil {
+		return models.SalesFilter{}
+	}
+	return models.SalesFilter{
+		Categories:     filter.GetCategories(),
+		Regions:        filter.GetRegions(),
+		PaymentMethods: filter.GetPaymentMethods(),
+		CustomerIDs:    filter.GetCustomerIds(),
+		MinPrice:       filter.MinPrice,
+		MaxPrice:       filter.MaxPrice,
+	}
+}
+
+func toProduct(product models.Product) *salespb.Product {
+	return &salespb.Product{
+		ProductId:   product.ProductID,
+		ProductName: product.ProductName,
+		Category:    product.Category,
+		UnitPrice:   product.UnitPrice,
+	}
+}
+
+func toProducts(products []models.Product) []*salespb.Product {
+	result := make([]*salespb.Product, len(products))
+	for i, product := range products {
+		result[i] = toProduct(product)
+	}
+	return result
+}
+
+func toCustomer(customer models.Customer) *salespb.Customer {
+	return &salespb.Customer{
+		CustomerId:      customer.CustomerID,
+		CustomerName:    customer.CustomerName,
+		CustomerEmail:   customer.CustomerEmail,
+		CustomerAddress: customer.CustomerAddress,
+	}
+}
+
+func toOrder(order models.Order) *salespb.Order {
+	return &salespb.Order{
+		OrderId:       order.OrderID,
+		CustomerId:    order.CustomerID,
+		DateOfSale:    timestamppb.New(order.DateOfSale),
+		ShippingCost:  order.ShippingCost,
+		PaymentMethod: order.PaymentMethod,
+		Region:        order.Region,
+	}
+}
+
+func toOrderDetails(order models.OrderDetails) *salespb.OrderDetails {
+	details := &salespb.OrderDetails{
+		Order:    toOrder(order.Order),
+		Customer: toCustomer(order.Customer),
+		Items:    make([]*salespb.OrderItem, len(order.Items)),
+	}
+	for i, item := range order.Items {
+		details.Items[i] = &salespb.OrderItem{
+			OrderItemId:  uint64(item.OrderItemID),
+			OrderId:      item.OrderID,
+			ProductId:    item.ProductID,
+			QuantitySold: int32(item.QuantitySold),
+			Discount:     item.Discount,
+			Product:      toProduct(item.Product),
+		}
+	}
+	return details
+}
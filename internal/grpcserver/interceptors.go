@@ -0,0 +1,161 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sales/internal/auth"
+	"sales/internal/constants"
+	"sales/internal/handlers"
+	"sales/internal/tenant"
+	"sales/pkg/salespb"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// apiKeyMetadata carries the API key on calls; a bearer authorization entry carrying an API key or
+// a JWT is accepted as well.
+const apiKeyMetadata = "x-api-key"
+
+// tenantMetadata selects the tenant of a call made with credentials not bound to one.
+const tenantMetadata = "x-tenant-id"
+
+// methodRoles are the roles each method requires; admins may call every method and methods
+// missing here are refused.
+var methodRoles = map[string][]string{
+	salespb.SalesService_GetTopProducts_FullMethodName:           {constants.RoleAnalyst},
+	salespb.SalesService_StreamTopProductsByGroup_FullMethodName: {constants.RoleAnalyst},
+	salespb.SalesService_RefreshData_FullMethodName:              {constants.RoleIngestor},
+	salespb.SalesService_GetProduct_FullMethodName:               readRoles,
+	salespb.SalesService_GetCustomer_FullMethodName:              readRoles,
+	salespb.SalesService_GetOrder_FullMethodName:                 readRoles,
+	salespb.SalesService_StreamProducts_FullMethodName:           readRoles,
+	salespb.SalesService_StreamCustomers_FullMethodName:          readRoles,
+	salespb.SalesService_StreamOrders_FullMethodName:             readRoles,
+}
+
+var readRoles = []string{constants.RoleViewer, constants.RoleAnalyst, constants.RoleIngestor}
+
+// errorDomain qualifies the REST error codes sent as the reason of an ErrorInfo detail.
+const errorDomain = "sales"
+
+// statusCodes maps the HTTP status of an error to its gRPC code.
+var statusCodes = map[int]codes.Code{
+	http.StatusBadRequest:   codes.InvalidArgument,
+	http.StatusUnauthorized: codes.Unauthenticated,
+	http.StatusForbidden:    codes.PermissionDenied,
+	http.StatusNotFound:     codes.NotFound,
+	http.StatusConflict:     codes.FailedPrecondition,
+}
+
+func unaryInterceptor(authenticator *auth.Authenticator, tenants *tenant.Registry) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authorize(ctx, authenticator, tenants, info.FullMethod)
+		if err != nil {
+			return nil, toStatus(info.FullMethod, err)
+		}
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, toStatus(info.FullMethod, err)
+		}
+		return resp, nil
+	}
+}
+
+func streamInterceptor(authenticator *auth.Authenticator, tenants *tenant.Registry) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(stream.Context(), authenticator, tenants, info.FullMethod)
+		if err != nil {
+			return toStatus(info.FullMethod, err)
+		}
+		if err := handler(srv, &tenantStream{ServerStream: stream, ctx: ctx}); err != nil {
+			return toStatus(info.FullMethod, err)
+		}
+		return nil
+	}
+}
+
+// tenantStream is a server stream whose context carries the tenant of the call.
+type tenantStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tenantStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorize authenticates the credential in the call metadata, checks the method's roles and
+// returns ctx carrying the tenant of the call.
+func authorize(ctx context.Context, authenticator *auth.Authenticator, tenants *tenant.Registry, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	credential := firstValue(md, apiKeyMetadata)
+	if credential == "" {
+		credential, _ = strings.CutPrefix(firstValue(md, "authorization"), "Bearer ")
+	}
+
+	principal, err := authenticator.Authenticate(credential)
+	if err != nil {
+		return nil, err
+	}
+	roles, ok := methodRoles[method]
+	if !ok || !principal.HasRole(roles...) {
+		return nil, constants.ErrForbidden
+	}
+
+	t, err := tenants.Resolve(principal.Tenant, firstValue(md, tenantMetadata))
+	if err != nil {
+		return nil, err
+	}
+	return tenant.NewContext(ctx, t), nil
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// toStatus converts an error to a gRPC status with the code and message the REST API reports it
+// with. The REST code is sent as an ErrorInfo reason and invalid fields as BadRequest violations.
+func toStatus(method string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	httpStatus, apiError := handlers.ToAPIError(err)
+	code, ok := statusCodes[httpStatus]
+	if !ok {
+		log.Printf("Call %s failed: %v", method, err)
+		code = codes.Internal
+	}
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: apiError.Code, Domain: errorDomain}}
+	badRequest := &errdetails.BadRequest{}
+	for _, violation := range apiError.Details {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: violation.Field, Description: violation.Message})
+	}
+	if len(badRequest.FieldViolations) == 0 && apiError.Field != "" {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: apiError.Field, Description: apiError.Message})
+	}
+	if len(badRequest.FieldViolations) > 0 {
+		details = append(details, badRequest)
+	}
+
+	st := status.New(code, apiError.Message)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
@@ -0,0 +1,206 @@
+// Package grpcserver serves the sales service defined in proto/sales/v1/sales.proto. It calls the
+// same services layer as the gin handlers and reports errors with their codes and messages.
+package grpcserver
+
+import (
+	"context"
+	"sales/internal/auth"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"sales/internal/services"
+	"sales/internal/tenant"
+	"sales/internal/utils"
+	"sales/pkg/salespb"
+	"sort"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements salespb.SalesServiceServer.
+type Server struct {
+	salespb.UnimplementedSalesServiceServer
+	svc *services.Services
+}
+
+// NewServer returns a gRPC server with the sales service registered on svc. Every call must carry
+// credentials accepted by authenticator whose roles allow the method, and only sees the data of
+// the tenant the caller is bound to or selects among tenants.
+func NewServer(svc *services.Services, authenticator *auth.Authenticator, tenants *tenant.Registry) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(unaryInterceptor(authenticator, tenants)),
+		grpc.StreamInterceptor(streamInterceptor(authenticator, tenants)),
+	)
+	salespb.RegisterSalesServiceServer(server, &Server{svc: svc})
+	return server
+}
+
+func (s *Server) GetTopProducts(ctx context.Context, req *salespb.GetTopProductsRequest) (*salespb.GetTopProductsResponse, error) {
+	n, dateRange, filter, err := parseTopProducts(req.GetN(), req.GetRange(), req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.svc.Analytics.GetTopProductsOverall(ctx, n, dateRange, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &salespb.GetTopProductsResponse{Products: toProducts(products)}, nil
+}
+
+func (s *Server) StreamTopProductsByGroup(req *salespb.StreamTopProductsByGroupRequest, stream grpc.ServerStreamingServer[salespb.ProductGroup]) error {
+	n, dateRange, filter, err := parseTopProducts(req.GetN(), req.GetRange(), req.GetFilter())
+	if err != nil {
+		return err
+	}
+
+	dimension := groupDimensions[req.GetDimension()]
+	if err := utils.ValidateDimension(dimension); err != nil {
+		return err
+	}
+	topProducts := s.svc.Analytics.GetTopProductsByCategory
+	if dimension == constants.DimensionRegion {
+		topProducts = s.svc.Analytics.GetTopProductsByRegion
+	}
+
+	groups, err := topProducts(stream.Context(), n, dateRange, filter)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := stream.Send(&salespb.ProductGroup{Key: key, Products: toProducts(groups[key])}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) RefreshData(ctx context.Context, _ *salespb.RefreshDataRequest) (*salespb.RefreshDataResponse, error) {
+	t, _ := tenant.FromContext(ctx)
+	if err := s.svc.Refresh.RefreshDatabase(ctx, t.CSVFile); err != nil {
+		return nil, err
+	}
+	return &salespb.RefreshDataResponse{RefreshedAt: timestamppb.New(time.Now())}, nil
+}
+
+func (s *Server) GetProduct(ctx context.Context, req *salespb.GetProductRequest) (*salespb.Product, error) {
+	product, err := s.svc.Entities.GetProduct(ctx, req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+	return toProduct(product), nil
+}
+
+func (s *Server) GetCustomer(ctx context.Context, req *salespb.GetCustomerRequest) (*salespb.Customer, error) {
+	customer, err := s.svc.Entities.GetCustomer(ctx, req.GetCustomerId())
+	if err != nil {
+		return nil, err
+	}
+	return toCustomer(customer), nil
+}
+
+func (s *Server) GetOrder(ctx context.Context, req *salespb.GetOrderRequest) (*salespb.OrderDetails, error) {
+	order, err := s.svc.Entities.GetOrder(ctx, req.GetOrderId())
+	if err != nil {
+		return nil, err
+	}
+	return toOrderDetails(order), nil
+}
+
+func (s *Server) StreamProducts(req *salespb.StreamProductsRequest, stream grpc.ServerStreamingServer[salespb.Product]) error {
+	page, err := streamPageRequest(req.GetSortBy(), "product_id", req.GetDesc(), repository.ProductSortColumns)
+	if err != nil {
+		return err
+	}
+	return streamPages(page, func(page models.PageRequest) ([]models.Product, int64, error) {
+		return s.svc.Entities.ListProducts(stream.Context(), page)
+	}, func(product models.Product) error {
+		return stream.Send(toProduct(product))
+	})
+}
+
+func (s *Server) StreamCustomers(req *salespb.StreamCustomersRequest, stream grpc.ServerStreamingServer[salespb.Customer]) error {
+	page, err := streamPageRequest(req.GetSortBy(), "customer_id", req.GetDesc(), repository.CustomerSortColumns)
+	if err != nil {
+		return err
+	}
+	return streamPages(page, func(page models.PageRequest) ([]models.Customer, int64, error) {
+		return s.svc.Entities.ListCustomers(stream.Context(), page)
+	}, func(customer models.Customer) error {
+		return stream.Send(toCustomer(customer))
+	})
+}
+
+func (s *Server) StreamOrders(req *salespb.StreamOrdersRequest, stream grpc.ServerStreamingServer[salespb.Order]) error {
+	page, err := streamPageRequest(req.GetSortBy(), "order_id", req.GetDesc(), repository.OrderSortColumns)
+	if err != nil {
+		return err
+	}
+	return streamPages(page, func(page models.PageRequest) ([]models.Order, int64, error) {
+		return s.svc.Entities.ListOrders(stream.Context(), page)
+	}, func(order models.Order) error {
+		return stream.Send(toOrder(order))
+	})
+}
+
+// parseTopProducts validates the arguments shared by the top-products calls with the REST rules.
+func parseTopProducts(n int32, dateRange *salespb.DateRange, filter *salespb.SalesFilter) (int, models.DateRange, models.SalesFilter, error) {
+	limit, err := utils.ParseLimit(strconv.Itoa(int(n)))
+	if err != nil {
+		return 0, models.DateRange{}, models.SalesFilter{}, err
+	}
+
+	parsedRange, err := utils.ValidateDateRange(dateRange.GetStartDate(), dateRange.GetEndDate(), dateRange.GetTz())
+	if err != nil {
+		return 0, models.DateRange{}, models.SalesFilter{}, err
+	}
+
+	parsedFilter, err := utils.ValidateSalesFilter(fromSalesFilter(filter))
+	if err != nil {
+		return 0, models.DateRange{}, models.SalesFilter{}, err
+	}
+	return limit, parsedRange, parsedFilter, nil
+}
+
+// streamPageRequest builds the first page of a stream, sorted by sortBy or defaultSort when empty.
+func streamPageRequest(sortBy, defaultSort string, desc bool, sortable map[string]string) (models.PageRequest, error) {
+	if sortBy == "" {
+		sortBy = defaultSort
+	}
+	column, ok := sortable[sortBy]
+	if !ok {
+		return models.PageRequest{}, constants.ErrInvalidSortBy
+	}
+	return models.PageRequest{
+		Limit: constants.StreamPageSize,
+		Sort:  []models.SortField{{Field: sortBy, Column: column, Desc: desc}},
+	}, nil
+}
+
+// streamPages lists page after page and sends every element, so only one page is held in memory.
+func streamPages[T any](page models.PageRequest, list func(models.PageRequest) ([]T, int64, error), send func(T) error) error {
+	for {
+		items, _, err := list(page)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := send(item); err != nil {
+				return err
+			}
+		}
+		if len(items) < page.Limit {
+			return nil
+		}
+		page.Offset += page.Limit
+	}
+}
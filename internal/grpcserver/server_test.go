@@ -0,0 +1,358 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sales/internal/auth"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/repository/memory"
+	"sales/internal/services"
+	"sales/internal/tenant"
+	"sales/pkg/cache"
+	"sales/pkg/salespb"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testCSVFile is the sample data every test tenant is refreshed from.
+const testCSVFile = "../../data/sales_data.csv"
+
+// testKeys are the API keys accepted by the test server, named after their role.
+var testKeys = map[string]string{
+	constants.RoleViewer:   "viewer-key",
+	constants.RoleAnalyst:  "analyst-key",
+	constants.RoleIngestor: "ingestor-key",
+	constants.RoleAdmin:    "admin-key",
+}
+
+// newTestClient serves an in-memory store over a bufconn listener and returns a client of it.
+func newTestClient(t *testing.T) salespb.SalesServiceClient {
+	t.Helper()
+	var apiKeys []auth.APIKey
+	for role, key := range testKeys {
+		hash := sha256.Sum256([]byte(key))
+		apiKeys = append(apiKeys, auth.APIKey{Name: role, SHA256: hex.EncodeToString(hash[:]), Roles: []string{role}})
+	}
+	authenticator, err := auth.NewAuthenticator(apiKeys, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tenants, err := tenant.NewRegistry([]tenant.Tenant{{ID: constants.DefaultTenant}}, tenant.Tenant{CSVFile: testCSVFile, Schedule: constants.DefaultCronSchedule})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := services.New(memory.NewStore(), services.NewResultCache(cache.NewLRU(constants.CacheEntries, constants.CacheTTL)))
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(svc, authenticator, tenants)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return salespb.NewSalesServiceClient(conn)
+}
+
+// withKey returns a context sending the API key of role.
+func withKey(role string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, testKeys[role])
+}
+
+// newLoadedClient returns a test client whose default tenant is refreshed from testCSVFile.
+func newLoadedClient(t *testing.T) salespb.SalesServiceClient {
+	t.Helper()
+	client := newTestClient(t)
+	resp, err := client.RefreshData(withKey(constants.RoleIngestor), &salespb.RefreshDataRequest{})
+	if err != nil {
+		t.Fatalf("RefreshData: %v", err)
+	}
+	if resp.GetRefreshedAt() == nil {
+		t.Fatal("RefreshData returned no refresh time")
+	}
+	return client
+}
+
+// wholeRange covers every sale of testCSVFile.
+var wholeRange = &salespb.DateRange{StartDate: "2023-01-01", EndDate: "2024-12-31"}
+
+func TestGetTopProducts(t *testing.T) {
+	client := newLoadedClient(t)
+
+	resp, err := client.GetTopProducts(withKey(constants.RoleAnalyst), &salespb.GetTopProductsRequest{N: 10, Range: wholeRange})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(resp.GetProducts()); got != 4 {
+		t.Fatalf("got %d products, want 4", got)
+	}
+	if last := resp.GetProducts()[3]; last.GetProductId() != "P234" {
+		t.Errorf("least sold product = %s, want P234", last.GetProductId())
+	}
+
+	resp, err = client.GetTopProducts(withKey(constants.RoleAnalyst), &salespb.GetTopProductsRequest{
+		N: 10, Range: wholeRange, Filter: &salespb.SalesFilter{Categories: []string{"Electronics"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, product := range resp.GetProducts() {
+		if product.GetCategory() != "Electronics" {
+			t.Errorf("filtered top products include %s of %s", product.GetProductId(), product.GetCategory())
+		}
+	}
+}
+
+func TestStreamTopProductsByGroup(t *testing.T) {
+	client := newLoadedClient(t)
+
+	stream, err := client.StreamTopProductsByGroup(withKey(constants.RoleAnalyst), &salespb.StreamTopProductsByGroupRequest{
+		Dimension: salespb.GroupDimension_GROUP_DIMENSION_CATEGORY, N: 1, Range: wholeRange,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for {
+		group, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(group.GetProducts()) != 1 {
+			t.Errorf("group %s has %d products, want 1", group.GetKey(), len(group.GetProducts()))
+		}
+		keys = append(keys, group.GetKey())
+	}
+	if want := "[Clothing Electronics Shoes]"; fmt.Sprint(keys) != want {
+		t.Errorf("streamed groups %v, want %s", keys, want)
+	}
+}
+
+func TestEntityCalls(t *testing.T) {
+	client := newLoadedClient(t)
+	ctx := withKey(constants.RoleViewer)
+
+	product, err := client.GetProduct(ctx, &salespb.GetProductRequest{ProductId: "P456"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.GetProductName() != "iPhone 15 Pro" || product.GetUnitPrice() != 1299 {
+		t.Errorf("GetProduct = %v", product)
+	}
+
+	customer, err := client.GetCustomer(ctx, &salespb.GetCustomerRequest{CustomerId: "C456"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.GetCustomerName() != "John Smith" {
+		t.Errorf("GetCustomer = %v", customer)
+	}
+
+	order, err := client.GetOrder(ctx, &salespb.GetOrderRequest{OrderId: "1003"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.GetOrder().GetRegion() != "Asia" || order.GetCustomer().GetCustomerId() != "C456" ||
+		len(order.GetItems()) != 1 || order.GetItems()[0].GetProduct().GetProductId() != "P789" {
+		t.Errorf("GetOrder = %v", order)
+	}
+}
+
+func TestStreamEntities(t *testing.T) {
+	client := newLoadedClient(t)
+	ctx := withKey(constants.RoleViewer)
+
+	products, err := client.StreamProducts(ctx, &salespb.StreamProductsRequest{Desc: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var productIDs []string
+	for product, err := products.Recv(); !errors.Is(err, io.EOF); product, err = products.Recv() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		productIDs = append(productIDs, product.GetProductId())
+	}
+	if want := "[P789 P456 P234 P123]"; fmt.Sprint(productIDs) != want {
+		t.Errorf("streamed products %v, want %s", productIDs, want)
+	}
+
+	customers, err := client.StreamCustomers(ctx, &salespb.StreamCustomersRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := countStream(t, customers.Recv); got != 3 {
+		t.Errorf("streamed %d customers, want 3", got)
+	}
+
+	orders, err := client.StreamOrders(ctx, &salespb.StreamOrdersRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := countStream(t, orders.Recv); got != 6 {
+		t.Errorf("streamed %d orders, want 6", got)
+	}
+}
+
+// countStream receives a stream to its end and returns the number of messages.
+func countStream[T any](t *testing.T, recv func() (T, error)) int {
+	t.Helper()
+	n := 0
+	for {
+		_, err := recv()
+		if errors.Is(err, io.EOF) {
+			return n
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	client := newTestClient(t)
+	request := &salespb.GetTopProductsRequest{N: 5, Range: wholeRange}
+
+	_, err := client.GetTopProducts(context.Background(), request)
+	assertCode(t, "missing key", err, codes.Unauthenticated)
+
+	_, err = client.GetTopProducts(metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, "wrong-key"), request)
+	assertCode(t, "unknown key", err, codes.Unauthenticated)
+
+	_, err = client.GetTopProducts(withKey(constants.RoleViewer), request)
+	assertCode(t, "viewer calling an analyst method", err, codes.PermissionDenied)
+
+	_, err = client.RefreshData(withKey(constants.RoleAnalyst), &salespb.RefreshDataRequest{})
+	assertCode(t, "analyst refreshing", err, codes.PermissionDenied)
+
+	stream, err := client.StreamProducts(context.Background(), &salespb.StreamProductsRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	assertCode(t, "stream without key", err, codes.Unauthenticated)
+
+	bearer := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testKeys[constants.RoleAdmin])
+	if _, err := client.GetTopProducts(bearer, request); err != nil {
+		t.Errorf("admin bearer key: %v", err)
+	}
+
+	tenantCtx := metadata.AppendToOutgoingContext(withKey(constants.RoleAnalyst), tenantMetadata, "unknown")
+	_, err = client.GetTopProducts(tenantCtx, request)
+	assertCode(t, "unknown tenant", err, codes.InvalidArgument)
+}
+
+func TestErrorStatuses(t *testing.T) {
+	client := newLoadedClient(t)
+	ctx := withKey(constants.RoleAnalyst)
+
+	_, err := client.GetProduct(ctx, &salespb.GetProductRequest{ProductId: "missing"})
+	assertCode(t, "missing product", err, codes.NotFound)
+	assertReason(t, err, "NOT_FOUND")
+
+	_, err = client.GetTopProducts(ctx, &salespb.GetTopProductsRequest{N: 0, Range: wholeRange})
+	assertCode(t, "invalid n", err, codes.InvalidArgument)
+	assertViolation(t, err, constants.Limit)
+
+	_, err = client.GetTopProducts(ctx, &salespb.GetTopProductsRequest{N: 5, Range: &salespb.DateRange{StartDate: "2024-02-01", EndDate: "2024-01-01"}})
+	assertCode(t, "reversed range", err, codes.InvalidArgument)
+	assertViolation(t, err, constants.EndDate)
+
+	stream, err := client.StreamTopProductsByGroup(ctx, &salespb.StreamTopProductsByGroupRequest{N: 5, Range: wholeRange})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	assertCode(t, "unspecified dimension", err, codes.InvalidArgument)
+	assertViolation(t, err, constants.GRPCDimension)
+
+	products, err := client.StreamProducts(ctx, &salespb.StreamProductsRequest{SortBy: "secret"})
+	if err == nil {
+		_, err = products.Recv()
+	}
+	assertCode(t, "unknown sort field", err, codes.InvalidArgument)
+}
+
+func TestToStatus(t *testing.T) {
+	for _, test := range []struct {
+		err  error
+		code codes.Code
+	}{
+		{constants.ErrUnauthorized, codes.Unauthenticated},
+		{constants.ErrForbidden, codes.PermissionDenied},
+		{constants.ErrTenantDenied, codes.PermissionDenied},
+		{constants.ErrUnknownTenant, codes.InvalidArgument},
+		{constants.ErrInvalidLimit, codes.InvalidArgument},
+		{models.ValidationErrors{{Field: constants.Limit, Message: "invalid"}}, codes.InvalidArgument},
+		{constants.ErrProductNotFound, codes.NotFound},
+		{fmt.Errorf("loading: %w", constants.ErrOrderNotFound), codes.NotFound},
+		{errors.Join(constants.ErrProductInUse, constants.ErrForbidden), codes.PermissionDenied},
+		{constants.ErrProductExists, codes.FailedPrecondition},
+		{constants.ErrProductInUse, codes.FailedPrecondition},
+		{context.Canceled, codes.Canceled},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{status.Error(codes.Unavailable, "down"), codes.Unavailable},
+		{errors.New("disk full"), codes.Internal},
+	} {
+		assertCode(t, test.err.Error(), toStatus("/test", test.err), test.code)
+	}
+
+	st, _ := status.FromError(toStatus("/test", errors.New("disk full")))
+	if st.Message() == "disk full" {
+		t.Error("an internal error leaked its message")
+	}
+}
+
+func assertCode(t *testing.T, name string, err error, code codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != code {
+		t.Errorf("%s: code %s, want %s (%v)", name, got, code, err)
+	}
+}
+
+// assertReason expects err to carry an ErrorInfo detail with the REST error code.
+func assertReason(t *testing.T, err error, reason string) {
+	t.Helper()
+	st, _ := status.FromError(err)
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetReason() == reason && info.GetDomain() == errorDomain {
+			return
+		}
+	}
+	t.Errorf("%v carries no %s ErrorInfo", err, reason)
+}
+
+// assertViolation expects err to carry a BadRequest detail reporting field.
+func assertViolation(t *testing.T, err error, field string) {
+	t.Helper()
+	st, _ := status.FromError(err)
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				if violation.GetField() == field {
+					return
+				}
+			}
+		}
+	}
+	t.Errorf("%v reports no violation of %s", err, field)
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+	"sales/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler serves the administration endpoints.
+type AdminHandler struct {
+	config config.Config
+}
+
+// NewAdminHandler returns an admin handler showing cfg with its secrets redacted.
+func NewAdminHandler(cfg config.Config) *AdminHandler {
+	return &AdminHandler{config: cfg.Redacted()}
+}
+
+// GetConfig handles the effective configuration endpoint.
+func (h *AdminHandler) GetConfig(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, h.config)
+}
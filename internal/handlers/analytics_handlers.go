@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"sales/internal/constants"
+	"sales/internal/services"
+	"sales/internal/utils"
+	"sales/pkg/export"
+)
+
+// AnalyticsHandler serves the analytics endpoints.
+type AnalyticsHandler struct {
+	analytics *services.AnalyticsService
+}
+
+// NewAnalyticsHandler returns an analytics handler computing results with analytics.
+func NewAnalyticsHandler(analytics *services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{analytics: analytics}
+}
+
+// GetSalesForecast handles the weekly sales forecast for a product or category.
+func (h *AnalyticsHandler) GetSalesForecast(ctx *gin.Context) {
+	query := validatedQuery(ctx)
+	result, err := h.analytics.GetSalesForecast(ctx.Request.Context(), query.Get(constants.ProductID), query.Int(constants.Horizon), query.Get(constants.Method), query.DateRange, query.Filter)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	writeResult(ctx, "forecast", result, func() export.Table { return utils.ForecastTable(result) })
+}
+
+// GetNewVsReturningCustomers handles the new vs returning customer revenue split per period.
+func (h *AnalyticsHandler) GetNewVsReturningCustomers(ctx *gin.Context) {
+	query := validatedQuery(ctx)
+	periods, err := h.analytics.GetNewVsReturningCustomers(ctx.Request.Context(), query.Get(constants.Interval), query.DateRange, query.Filter)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	writeResult(ctx, "new-vs-returning", periods, func() export.Table { return utils.CustomerRetentionTable(periods) })
+}
+
+// GetPivotTable handles the pivot of a metric across two dimensions.
+func (h *AnalyticsHandler) GetPivotTable(ctx *gin.Context) {
+	query := validatedQuery(ctx)
+	table, err := h.analytics.GetPivotTable(ctx.Request.Context(), query.Get(constants.Rows), query.Get(constants.Columns), query.Get(constants.Metric), query.Get(constants.Percent), query.DateRange, query.Filter)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	writeResult(ctx, "pivot", table, func() export.Table { return utils.PivotTableRows(table) })
+}
+
+// GetDistributionStatistics handles the order value and quantity-per-line distribution statistics.
+func (h *AnalyticsHandler) GetDistributionStatistics(ctx *gin.Context) {
+	query := validatedQuery(ctx)
+	result, err := h.analytics.GetDistributionStatistics(ctx.Request.Context(), query.Get(constants.GroupBy), query.Int(constants.Buckets), query.DateRange, query.Filter)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	writeResult(ctx, "distribution", result, func() export.Table { return utils.DistributionTable(result) })
+}
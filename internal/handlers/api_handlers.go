@@ -2,90 +2,79 @@ package handlers
 
 import (
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 	"net/http"
 	"sales/internal/constants"
 	"sales/internal/services"
+	"sales/internal/tenant"
 	"sales/internal/utils"
+	"sales/pkg/export"
 )
 
-// RefreshHandler handles the data refresh endpoint.
-func RefreshHandler(db *gorm.DB) gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		err := services.RefreshDatabase(db)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		ctx.String(http.StatusOK, "Data refreshed successfully.")
-	}
+// RefreshHandler serves the data refresh endpoint.
+type RefreshHandler struct {
+	refresh *services.RefreshService
 }
 
-// GetTopProductsOverallHandler handles the retrieval of top N products overall.
-func GetTopProductsOverallHandler(db *gorm.DB) gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		nStr := ctx.Query(constants.Limit)
-		startDate := ctx.Query(constants.StartDate)
-		endDate := ctx.Query(constants.EndDate)
-
-		n, err := utils.ValidateParamsAndGetLimit(nStr, startDate, endDate)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-
-		topProducts, err := services.GetTopProductsOverall(db, n, startDate, endDate)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+// NewRefreshHandler returns a refresh handler importing through refresh.
+func NewRefreshHandler(refresh *services.RefreshService) *RefreshHandler {
+	return &RefreshHandler{refresh: refresh}
+}
 
-		ctx.JSON(http.StatusOK, topProducts)
+// Refresh handles the data refresh endpoint, reloading the CSV of the request's tenant.
+func (h *RefreshHandler) Refresh(ctx *gin.Context) {
+	t, _ := tenant.FromContext(ctx.Request.Context())
+	err := h.refresh.RefreshDatabase(ctx.Request.Context(), t.CSVFile)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
 	}
+	ctx.String(http.StatusOK, "Data refreshed successfully.")
 }
 
-// GetTopProductsByCategoryHandler handles the retrieval of top N products by category.
-func GetTopProductsByCategoryHandler(db *gorm.DB) gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		nStr := ctx.Query("n")
-		startDate := ctx.Query("start_date")
-		endDate := ctx.Query("end_date")
-
-		n, err := utils.ValidateParamsAndGetLimit(nStr, startDate, endDate)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+// GetTopProductsOverall handles the retrieval of top N products overall.
+func (h *AnalyticsHandler) GetTopProductsOverall(ctx *gin.Context) {
+	query := validatedQuery(ctx)
+	topProducts, err := h.analytics.GetTopProductsOverall(ctx.Request.Context(), query.Int(constants.Limit), query.DateRange, query.Filter)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 
-		topProductsByCategory, err := services.GetTopProductsByCategory(db, n, startDate, endDate)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+	writeResult(ctx, "top-products", topProducts, func() export.Table { return utils.ProductsTable(topProducts) })
+}
 
-		ctx.JSON(http.StatusOK, topProductsByCategory)
+// GetTopProductsByCategory handles the retrieval of top N products by category.
+func (h *AnalyticsHandler) GetTopProductsByCategory(ctx *gin.Context) {
+	query := validatedQuery(ctx)
+	topProductsByCategory, err := h.analytics.GetTopProductsByCategory(ctx.Request.Context(), query.Int(constants.Limit), query.DateRange, query.Filter)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
 	}
-}
 
-// GetTopProductsByRegionHandler handles the retrieval of top N products by region.
-func GetTopProductsByRegionHandler(db *gorm.DB) gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		nStr := ctx.Query("n")
-		startDate := ctx.Query("start_date")
-		endDate := ctx.Query("end_date")
+	writeResult(ctx, "top-products-by-category", topProductsByCategory, func() export.Table { return utils.GroupedProductsTable("Category", topProductsByCategory) })
+}
 
-		n, err := utils.ValidateParamsAndGetLimit(nStr, startDate, endDate)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+// GetTopProductsByRegion handles the retrieval of top N products by region.
+func (h *AnalyticsHandler) GetTopProductsByRegion(ctx *gin.Context) {
+	query := validatedQuery(ctx)
+	topProductsByRegion, err := h.analytics.GetTopProductsByRegion(ctx.Request.Context(), query.Int(constants.Limit), query.DateRange, query.Filter)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 
-		topProductsByRegion, err := services.GetTopProductsByRegion(db, n, startDate, endDate)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+	writeResult(ctx, "top-products-by-region", topProductsByRegion, func() export.Table { return utils.GroupedProductsTable("Region", topProductsByRegion) })
+}
 
-		ctx.JSON(http.StatusOK, topProductsByRegion)
+// GetTrendingProducts handles the retrieval of the fastest growing and declining products.
+func (h *AnalyticsHandler) GetTrendingProducts(ctx *gin.Context) {
+	query := validatedQuery(ctx)
+	trending, err := h.analytics.GetTrendingProducts(ctx.Request.Context(), query.Int(constants.Limit), query.Int(constants.MinVolume), query.DateRange, query.Filter)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
 	}
+
+	writeResult(ctx, "trending-products", trending, func() export.Table { return utils.TrendingProductsTable(trending) })
 }
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/repository/memory"
+	"testing"
+)
+
+// allTime is a query string covering every sale of the sample data.
+const allTime = "start_date=2023-01-01&end_date=2024-12-31"
+
+func TestRefresh(t *testing.T) {
+	router := newTestRouter(t, memory.NewStore())
+	if w := serve(router, http.MethodGet, "/v1/top-products/overall?n=5&"+allTime, constants.RoleAnalyst, nil); w.Code != http.StatusOK || w.Body.String() != "[]" {
+		t.Errorf("top products before a refresh: %d %s, want an empty list", w.Code, w.Body)
+	}
+
+	router = newLoadedRouter(t)
+	if w := serve(router, http.MethodGet, "/v1/top-products/overall?n=1&"+allTime, constants.RoleAnalyst, nil); w.Code != http.StatusOK || w.Body.String() == "[]" {
+		t.Errorf("top products after a refresh: %d %s", w.Code, w.Body)
+	}
+}
+
+func TestTopProducts(t *testing.T) {
+	router := newLoadedRouter(t)
+
+	w := serve(router, http.MethodGet, "/v1/top-products/overall?n=3&"+allTime, constants.RoleAnalyst, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("%d %s", w.Code, w.Body)
+	}
+	var ids []string
+	for _, product := range decode[[]models.Product](t, w) {
+		ids = append(ids, product.ProductID)
+	}
+	if fmt.Sprint(ids) != "[P123 P456 P789]" {
+		t.Errorf("top products %v, want [P123 P456 P789]", ids)
+	}
+
+	w = serve(router, http.MethodGet, "/v1/top-products/region?n=5&region=Europe&"+allTime, constants.RoleAnalyst, nil)
+	byRegion := decode[map[string][]models.Product](t, w)
+	if len(byRegion) != 1 || len(byRegion["Europe"]) != 1 || byRegion["Europe"][0].ProductID != "P456" {
+		t.Errorf("top products in Europe %v, want only P456", byRegion)
+	}
+}
+
+func TestConditionalRequests(t *testing.T) {
+	router := newLoadedRouter(t)
+	target := "/v1/top-products/category?n=1&" + allTime
+
+	w := serve(router, http.MethodGet, target, constants.RoleAnalyst, nil)
+	tag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || tag == "" {
+		t.Fatalf("%d with ETag %q", w.Code, tag)
+	}
+	req := newRequest(http.MethodGet, target, constants.RoleAnalyst, nil)
+	req.Header.Set("If-None-Match", tag)
+	if w := record(router, req); w.Code != http.StatusNotModified {
+		t.Errorf("revalidating a current ETag: %d, want 304", w.Code)
+	}
+}
+
+func TestGetPivotTable(t *testing.T) {
+	router := newLoadedRouter(t)
+
+	w := serve(router, http.MethodGet, "/v1/analytics/pivot?rows=region&columns=category&"+allTime, constants.RoleAnalyst, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("%d %s", w.Code, w.Body)
+	}
+	table := decode[models.PivotTable](t, w)
+	if got := fmt.Sprint(table.Columns, table.ColumnTotals, table.GrandTotal, len(table.Rows)); got != "[Clothing Electronics Shoes] [3 4 3] 10 4" {
+		t.Errorf("columns, totals, grand total and rows %s", got)
+	}
+
+	w = serve(router, http.MethodGet, "/v1/analytics/pivot?rows=product&columns=region&"+allTime, constants.RoleAnalyst, nil)
+	table = decode[models.PivotTable](t, w)
+	if len(table.Rows) != 4 || table.Rows[0].Key != "P123" || table.Rows[0].Label != "UltraBoost Running Shoes" {
+		t.Errorf("product rows %+v, want keyed by id and labelled by name", table.Rows)
+	}
+}
+
+func TestGetSalesForecast(t *testing.T) {
+	router := newLoadedRouter(t)
+
+	w := serve(router, http.MethodGet, "/v1/analytics/forecast?product_id=P456&horizon=2&method=moving_average", constants.RoleAnalyst, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("%d %s", w.Code, w.Body)
+	}
+	result := decode[models.ForecastResult](t, w)
+	if len(result.Methods) != 1 || len(result.Methods[0].Forecast) != 2 || result.Methods[0].Forecast[0].PeriodStart != "2024-05-20" {
+		t.Errorf("forecast %+v, want 2 weeks from 2024-05-20", result.Methods)
+	}
+}
+
+func TestGetNewVsReturningCustomers(t *testing.T) {
+	router := newLoadedRouter(t)
+
+	w := serve(router, http.MethodGet, "/v1/analytics/customers/new-vs-returning?interval=month&start_date=2023-12-01&end_date=2024-05-31", constants.RoleAnalyst, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("%d %s", w.Code, w.Body)
+	}
+	var got []string
+	for _, period := range decode[[]models.CustomerRetentionPeriod](t, w) {
+		got = append(got, fmt.Sprintf("%s %d/%d", period.Period, period.NewCustomers, period.ReturningCustomers))
+	}
+	want := "[2023-12-01 1/0 2024-01-01 1/0 2024-02-01 0/1 2024-03-01 1/0 2024-04-01 0/1 2024-05-01 0/1]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("new/returning customers %v, want %s", got, want)
+	}
+}
+
+func TestGetDistributionStatistics(t *testing.T) {
+	router := newLoadedRouter(t)
+
+	w := serve(router, http.MethodGet, "/v1/analytics/distribution?group_by=payment_method&buckets=4&"+allTime, constants.RoleAnalyst, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("%d %s", w.Code, w.Body)
+	}
+	result := decode[models.DistributionResult](t, w)
+	if result.GroupBy != "payment_method" || len(result.Groups) != 3 || result.Groups[2].Key != "PayPal" {
+		t.Fatalf("groups %+v, want one per payment method", result.Groups)
+	}
+	if paypal := result.Groups[2].OrderValue; paypal.Count != 2 || paypal.Max != 1299 || len(paypal.Histogram) != 4 {
+		t.Errorf("PayPal order values %+v", paypal)
+	}
+}
+
+func TestParameterErrors(t *testing.T) {
+	router := newLoadedRouter(t)
+
+	req := newRequest(http.MethodGet, "/v1/top-products/overall?n=0&start_date=2024-13-01&end_date=2024-12-31", constants.RoleAnalyst, nil)
+	req.Header.Set(RequestIDHeader, "trace-1")
+	w := record(router, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("%d %s, want 400", w.Code, w.Body)
+	}
+	apiError := decode[models.APIError](t, w)
+	if apiError.Code != CodeInvalidParameter || apiError.RequestID != "trace-1" || w.Header().Get(RequestIDHeader) != "trace-1" {
+		t.Errorf("error %+v, want INVALID_PARAMETER for request trace-1", apiError)
+	}
+	fields := map[string]bool{}
+	for _, detail := range apiError.Details {
+		fields[detail.Field] = true
+	}
+	if len(apiError.Details) != 2 || !fields[constants.Limit] || !fields[constants.StartDate] || !fields[apiError.Field] {
+		t.Errorf("details %+v, want one per invalid parameter", apiError.Details)
+	}
+
+	for target, field := range map[string]string{
+		"/v1/analytics/pivot?rows=region&columns=region&" + allTime: constants.Columns,
+		"/v1/analytics/pivot?columns=region&" + allTime:             constants.Rows,
+		"/v1/analytics/forecast?" + allTime:                         constants.ProductID,
+		"/v1/analytics/distribution?group_by=product&" + allTime:    constants.GroupBy,
+	} {
+		w := serve(router, http.MethodGet, target, constants.RoleAnalyst, nil)
+		if apiError := decode[models.APIError](t, w); w.Code != http.StatusBadRequest || apiError.Field != field || apiError.Message == "" {
+			t.Errorf("%s: %d %+v, want a 400 on %s", target, w.Code, apiError, field)
+		}
+	}
+
+	w = serve(router, http.MethodGet, "/v1/unknown", constants.RoleAnalyst, nil)
+	if apiError := decode[models.APIError](t, w); w.Code != http.StatusNotFound || apiError.Code != CodeNotFound || apiError.RequestID == "" {
+		t.Errorf("unknown route: %d %+v", w.Code, apiError)
+	}
+}
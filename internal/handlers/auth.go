@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"sales/internal/auth"
+	"sales/internal/constants"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader carries the API key on requests; a bearer Authorization header carrying an API key
+// or a JWT is accepted as well.
+const APIKeyHeader = "X-API-Key"
+
+// PrincipalKey is the context key holding the authenticated auth.Principal.
+const PrincipalKey = "principal"
+
+// route roles, passed to RequireRoles and documented in routeDocs
+var (
+	readRoles    = []string{constants.RoleViewer, constants.RoleAnalyst, constants.RoleIngestor}
+	analystRoles = []string{constants.RoleAnalyst}
+	ingestRoles  = []string{constants.RoleIngestor}
+	adminRoles   = []string{} // admin only, as admins pass every check
+)
+
+// AuthMiddleware rejects requests without valid credentials and stores the caller's principal.
+// With no credentials configured every request is rejected.
+func AuthMiddleware(authenticator *auth.Authenticator) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, err := authenticator.Authenticate(requestCredential(ctx))
+		if err != nil {
+			_ = ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+		ctx.Set(PrincipalKey, principal)
+		ctx.Next()
+	}
+}
+
+// RequireRoles rejects requests whose principal holds none of roles; admins pass every check.
+// It must run after AuthMiddleware.
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, _ := ctx.Value(PrincipalKey).(auth.Principal)
+		if !principal.HasRole(roles...) {
+			_ = ctx.Error(constants.ErrForbidden)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// requestCredential reads the API key header, falling back to a bearer Authorization header.
+func requestCredential(ctx *gin.Context) string {
+	if key := ctx.GetHeader(APIKeyHeader); key != "" {
+		return key
+	}
+	if credential, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer "); ok {
+		return credential
+	}
+	return ""
+}
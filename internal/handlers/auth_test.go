@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/repository/memory"
+	"testing"
+)
+
+func TestAuthentication(t *testing.T) {
+	router := newTestRouter(t, memory.NewStore())
+
+	for name, req := range map[string]*http.Request{
+		"no credentials": newRequest(http.MethodGet, "/v1/products", "", nil),
+		"an unknown key": func() *http.Request {
+			req := newRequest(http.MethodGet, "/v1/products", "", nil)
+			req.Header.Set(APIKeyHeader, "unknown-key")
+			return req
+		}(),
+	} {
+		w := record(router, req)
+		if apiError := decode[models.APIError](t, w); w.Code != http.StatusUnauthorized || apiError.Code != CodeUnauthorized || apiError.RequestID == "" {
+			t.Errorf("%s: %d %+v, want 401", name, w.Code, apiError)
+		}
+	}
+
+	req := newRequest(http.MethodGet, "/v1/products", "", nil)
+	req.Header.Set("Authorization", "Bearer "+testKeys[constants.RoleViewer])
+	if w := record(router, req); w.Code != http.StatusOK {
+		t.Errorf("bearer key: %d %s, want 200", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodGet, "/openapi.json", "", nil); w.Code != http.StatusOK {
+		t.Errorf("the OpenAPI document without credentials: %d, want 200", w.Code)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	router := newTestRouter(t, memory.NewStore())
+	product := map[string]any{"ProductID": "P900", "ProductName": "Trail Socks", "Category": "Clothing", "UnitPrice": 9.5}
+
+	for _, test := range []struct {
+		role, method, target string
+		body                 any
+		want                 int
+	}{
+		{constants.RoleViewer, http.MethodGet, "/v1/products", nil, http.StatusOK},
+		{constants.RoleViewer, http.MethodGet, "/v1/top-products/overall?n=1&" + allTime, nil, http.StatusForbidden},
+		{constants.RoleViewer, http.MethodPost, "/v1/products", product, http.StatusForbidden},
+		{constants.RoleAnalyst, http.MethodGet, "/v1/top-products/overall?n=1&" + allTime, nil, http.StatusOK},
+		{constants.RoleAnalyst, http.MethodPost, "/v1/refresh", nil, http.StatusForbidden},
+		{constants.RoleIngestor, http.MethodPost, "/v1/products", product, http.StatusCreated},
+		{constants.RoleIngestor, http.MethodGet, "/v1/admin/config", nil, http.StatusForbidden},
+		{constants.RoleAdmin, http.MethodGet, "/v1/admin/config", nil, http.StatusOK},
+		{constants.RoleAdmin, http.MethodGet, "/v1/top-products/overall?n=1&" + allTime, nil, http.StatusOK},
+	} {
+		w := serve(router, test.method, test.target, test.role, test.body)
+		if w.Code != test.want {
+			t.Errorf("%s %s %s: %d %s, want %d", test.role, test.method, test.target, w.Code, w.Body, test.want)
+		}
+		if test.want == http.StatusForbidden && decode[models.APIError](t, w).Code != CodeForbidden {
+			t.Errorf("%s %s %s: %s, want FORBIDDEN", test.role, test.method, test.target, w.Body)
+		}
+	}
+}
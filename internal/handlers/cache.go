@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"sales/internal/constants"
+	"sales/internal/services"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETagKey is the gin context key holding the entity tag of the result being served.
+const ETagKey = "etag"
+
+// ConditionalGetMiddleware tags an analytics result with an ETag derived from the route, its
+// normalized query, the negotiated format and the generation of the tenant's data, and answers 304
+// Not Modified when the caller's If-None-Match already holds it. It must run after
+// TenantMiddleware and ValidateQueryMiddleware.
+func ConditionalGetMiddleware(cache *services.ResultCache) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tag, ok := cache.Tag(ctx.Request.Context(), resultRequest(ctx))
+		if !ok {
+			ctx.Next()
+			return
+		}
+		ctx.Header("Vary", "Accept")
+		if matchesETag(ctx.GetHeader("If-None-Match"), tag) {
+			setCacheHeaders(ctx, tag)
+			ctx.AbortWithStatus(http.StatusNotModified)
+			return
+		}
+		ctx.Set(ETagKey, tag)
+		ctx.Next()
+	}
+}
+
+// resultRequest describes the result a request asks for: its route and query parameters with the
+// format replaced by the negotiated one. Parameters are sorted by name, so their order is irrelevant.
+func resultRequest(ctx *gin.Context) string {
+	query := ctx.Request.URL.Query()
+	query.Del(constants.Format)
+	return ctx.FullPath() + "?" + query.Encode() + "#" + negotiateFormat(ctx)
+}
+
+// matchesETag reports whether an If-None-Match header lists tag, comparing weakly as RFC 9110 requires.
+func matchesETag(header string, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// setCacheHeaders lets the caller keep the result as long as it revalidates it with tag.
+func setCacheHeaders(ctx *gin.Context, tag string) {
+	ctx.Header("ETag", tag)
+	ctx.Header("Cache-Control", constants.CacheControl)
+}
@@ -0,0 +1,393 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"sales/internal/services"
+	"sales/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EntityHandler serves the product, customer and order endpoints.
+type EntityHandler struct {
+	entities *services.EntityService
+}
+
+// NewEntityHandler returns an entity handler managing entities through entities.
+func NewEntityHandler(entities *services.EntityService) *EntityHandler {
+	return &EntityHandler{entities: entities}
+}
+
+// ListProducts handles the paginated product listing.
+func (h *EntityHandler) ListProducts(ctx *gin.Context) {
+	page, sortParam := parsePageRequest(validatedQuery(ctx), repository.ProductSortColumns)
+	products, total, err := h.entities.ListProducts(ctx.Request.Context(), page)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	writePage(ctx, products, total, page, sortParam)
+}
+
+// ListCustomers handles the paginated customer listing.
+func (h *EntityHandler) ListCustomers(ctx *gin.Context) {
+	page, sortParam := parsePageRequest(validatedQuery(ctx), repository.CustomerSortColumns)
+	customers, total, err := h.entities.ListCustomers(ctx.Request.Context(), page)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	writePage(ctx, customers, total, page, sortParam)
+}
+
+// ListOrders handles the paginated order listing.
+func (h *EntityHandler) ListOrders(ctx *gin.Context) {
+	page, sortParam := parsePageRequest(validatedQuery(ctx), repository.OrderSortColumns)
+	orders, total, err := h.entities.ListOrders(ctx.Request.Context(), page)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	writePage(ctx, orders, total, page, sortParam)
+}
+
+// bindJSON decodes the request body into input, rejecting malformed JSON and unknown fields.
+func bindJSON(ctx *gin.Context, input any) error {
+	decoder := json.NewDecoder(ctx.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(input); err != nil {
+		return fmt.Errorf("%w: %v", constants.ErrInvalidBody, err)
+	}
+	return nil
+}
+
+// pathID returns the id path parameter, rejecting a body id that names a different resource.
+func pathID(ctx *gin.Context, bodyID string) (string, error) {
+	id := ctx.Param("id")
+	if bodyID != "" && bodyID != id {
+		return "", constants.ErrIDMismatch
+	}
+	return id, nil
+}
+
+// orderItemID parses the item_id path parameter; ids that can't exist are reported as not found.
+func orderItemID(ctx *gin.Context) (uint, error) {
+	id, err := strconv.ParseUint(ctx.Param("item_id"), 10, 32)
+	if err != nil {
+		return 0, constants.ErrOrderItemNotFound
+	}
+	return uint(id), nil
+}
+
+// CreateProduct handles creating a product.
+func (h *EntityHandler) CreateProduct(ctx *gin.Context) {
+	var input models.ProductInput
+	if err := bindJSON(ctx, &input); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	product, err := utils.ValidateProductInput(input)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := h.entities.CreateProduct(ctx.Request.Context(), product); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, product)
+}
+
+// GetProduct handles reading a product.
+func (h *EntityHandler) GetProduct(ctx *gin.Context) {
+	product, err := h.entities.GetProduct(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, product)
+}
+
+// UpdateProduct handles replacing a product.
+func (h *EntityHandler) UpdateProduct(ctx *gin.Context) {
+	var input models.ProductInput
+	if err := bindJSON(ctx, &input); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	id, err := pathID(ctx, input.ProductID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	input.ProductID = id
+
+	product, err := utils.ValidateProductInput(input)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := h.entities.UpdateProduct(ctx.Request.Context(), product); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, product)
+}
+
+// DeleteProduct handles deleting a product.
+func (h *EntityHandler) DeleteProduct(ctx *gin.Context) {
+	if err := h.entities.DeleteProduct(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// CreateCustomer handles creating a customer.
+func (h *EntityHandler) CreateCustomer(ctx *gin.Context) {
+	var input models.CustomerInput
+	if err := bindJSON(ctx, &input); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	customer, err := utils.ValidateCustomerInput(input)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := h.entities.CreateCustomer(ctx.Request.Context(), customer); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, customer)
+}
+
+// GetCustomer handles reading a customer.
+func (h *EntityHandler) GetCustomer(ctx *gin.Context) {
+	customer, err := h.entities.GetCustomer(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, customer)
+}
+
+// UpdateCustomer handles replacing a customer.
+func (h *EntityHandler) UpdateCustomer(ctx *gin.Context) {
+	var input models.CustomerInput
+	if err := bindJSON(ctx, &input); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	id, err := pathID(ctx, input.CustomerID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	input.CustomerID = id
+
+	customer, err := utils.ValidateCustomerInput(input)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := h.entities.UpdateCustomer(ctx.Request.Context(), customer); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, customer)
+}
+
+// DeleteCustomer handles deleting a customer.
+func (h *EntityHandler) DeleteCustomer(ctx *gin.Context) {
+	if err := h.entities.DeleteCustomer(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// CreateOrder handles creating an order along with its items.
+func (h *EntityHandler) CreateOrder(ctx *gin.Context) {
+	var input models.OrderInput
+	if err := bindJSON(ctx, &input); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	order, items, err := utils.ValidateOrderInput(input)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	details, err := h.entities.CreateOrder(ctx.Request.Context(), order, items)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, details)
+}
+
+// GetOrder handles reading an order with its customer and items.
+func (h *EntityHandler) GetOrder(ctx *gin.Context) {
+	details, err := h.entities.GetOrder(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, details)
+}
+
+// UpdateOrder handles replacing an order; items are managed through the nested items resource.
+func (h *EntityHandler) UpdateOrder(ctx *gin.Context) {
+	var input models.OrderInput
+	if err := bindJSON(ctx, &input); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if len(input.Items) > 0 {
+		_ = ctx.Error(models.ValidationErrors{{Field: "Items", Message: "items are managed through the order items resource"}})
+		return
+	}
+
+	id, err := pathID(ctx, input.OrderID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	input.OrderID = id
+
+	order, _, err := utils.ValidateOrderInput(input)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	details, err := h.entities.UpdateOrder(ctx.Request.Context(), order)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, details)
+}
+
+// DeleteOrder handles deleting an order and its items.
+func (h *EntityHandler) DeleteOrder(ctx *gin.Context) {
+	if err := h.entities.DeleteOrder(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListOrderItems handles listing the items of an order.
+func (h *EntityHandler) ListOrderItems(ctx *gin.Context) {
+	items, err := h.entities.ListOrderItems(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, items)
+}
+
+// CreateOrderItem handles adding an item to an order.
+func (h *EntityHandler) CreateOrderItem(ctx *gin.Context) {
+	var input models.OrderItemInput
+	if err := bindJSON(ctx, &input); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	item, err := utils.ValidateOrderItemInput(input)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	item.OrderID = ctx.Param("id")
+
+	created, err := h.entities.CreateOrderItem(ctx.Request.Context(), item)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// UpdateOrderItem handles replacing an item of an order.
+func (h *EntityHandler) UpdateOrderItem(ctx *gin.Context) {
+	itemID, err := orderItemID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	var input models.OrderItemInput
+	if err := bindJSON(ctx, &input); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	item, err := utils.ValidateOrderItemInput(input)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	item.OrderItemID = itemID
+	item.OrderID = ctx.Param("id")
+
+	updated, err := h.entities.UpdateOrderItem(ctx.Request.Context(), item)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// DeleteOrderItem handles removing an item from an order.
+func (h *EntityHandler) DeleteOrderItem(ctx *gin.Context) {
+	itemID, err := orderItemID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := h.entities.DeleteOrderItem(ctx.Request.Context(), ctx.Param("id"), itemID); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"net/http"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestProductEndpoints(t *testing.T) {
+	router := newLoadedRouter(t)
+	product := map[string]any{"ProductID": "P900", "ProductName": "Trail Socks", "Category": "Clothing", "UnitPrice": "9.50"}
+
+	w := serve(router, http.MethodPost, "/v1/products", constants.RoleIngestor, product)
+	if created := decode[models.Product](t, w); w.Code != http.StatusCreated || created.UnitPrice != 9.5 {
+		t.Fatalf("create: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodPost, "/v1/products", constants.RoleIngestor, product); w.Code != http.StatusConflict || decode[models.APIError](t, w).Code != CodeConflict {
+		t.Errorf("creating a product twice: %d %s, want 409", w.Code, w.Body)
+	}
+
+	product["UnitPrice"] = 11
+	w = serve(router, http.MethodPut, "/v1/products/P900", constants.RoleIngestor, product)
+	if updated := decode[models.Product](t, w); w.Code != http.StatusOK || updated.UnitPrice != 11 {
+		t.Errorf("update: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodPut, "/v1/products/P901", constants.RoleIngestor, product); w.Code != http.StatusBadRequest {
+		t.Errorf("updating with a mismatched id: %d, want 400", w.Code)
+	}
+
+	w = serve(router, http.MethodGet, "/v1/products?limit=2&sort=unit_price:desc", constants.RoleViewer, nil)
+	page := decode[models.Page[models.Product]](t, w)
+	if w.Code != http.StatusOK || page.Meta.Total != 5 || len(page.Data) != 2 || page.Data[0].ProductID != "P456" {
+		t.Errorf("list: %d %+v, want 2 of 5 products, most expensive first", w.Code, page)
+	}
+
+	if w := serve(router, http.MethodDelete, "/v1/products/P900", constants.RoleIngestor, nil); w.Code != http.StatusNoContent {
+		t.Errorf("delete: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodGet, "/v1/products/P900", constants.RoleViewer, nil); w.Code != http.StatusNotFound || decode[models.APIError](t, w).Code != CodeNotFound {
+		t.Errorf("getting a deleted product: %d %s, want 404", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodDelete, "/v1/products/P123", constants.RoleIngestor, nil); w.Code != http.StatusConflict {
+		t.Errorf("deleting a sold product: %d, want 409", w.Code)
+	}
+}
+
+func TestCustomerEndpoints(t *testing.T) {
+	router := newLoadedRouter(t)
+	customer := map[string]any{"CustomerID": "C900", "CustomerName": "Ann Lee", "CustomerEmail": "ann@example.com", "CustomerAddress": "1 Elm St"}
+
+	if w := serve(router, http.MethodPost, "/v1/customers", constants.RoleIngestor, customer); w.Code != http.StatusCreated {
+		t.Fatalf("create: %d %s", w.Code, w.Body)
+	}
+	customer["CustomerEmail"] = "not an email"
+	w := serve(router, http.MethodPut, "/v1/customers/C900", constants.RoleIngestor, customer)
+	if apiError := decode[models.APIError](t, w); w.Code != http.StatusBadRequest || apiError.Field != "CustomerEmail" {
+		t.Errorf("updating with an invalid email: %d %+v", w.Code, apiError)
+	}
+	if w := serve(router, http.MethodPost, "/v1/customers", constants.RoleIngestor, map[string]any{"CustomerID": "C901", "Unknown": 1}); w.Code != http.StatusBadRequest || decode[models.APIError](t, w).Code != CodeInvalidBody {
+		t.Errorf("creating with an unknown field: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodGet, "/v1/customers/C900", constants.RoleViewer, nil); w.Code != http.StatusOK || decode[models.Customer](t, w).CustomerEmail != "ann@example.com" {
+		t.Errorf("get: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodDelete, "/v1/customers/C456", constants.RoleIngestor, nil); w.Code != http.StatusConflict {
+		t.Errorf("deleting a customer with orders: %d, want 409", w.Code)
+	}
+	if w := serve(router, http.MethodDelete, "/v1/customers/C900", constants.RoleIngestor, nil); w.Code != http.StatusNoContent {
+		t.Errorf("delete: %d %s", w.Code, w.Body)
+	}
+}
+
+func TestOrderEndpoints(t *testing.T) {
+	router := newLoadedRouter(t)
+	order := map[string]any{
+		"OrderID": "2001", "CustomerID": "C456", "DateOfSale": "2024-06-01", "ShippingCost": 5, "PaymentMethod": "PayPal", "Region": "Europe",
+		"Items": []map[string]any{{"ProductID": "P456", "QuantitySold": 2, "Discount": 0}},
+	}
+
+	w := serve(router, http.MethodPost, "/v1/orders", constants.RoleIngestor, order)
+	created := decode[models.OrderDetails](t, w)
+	if w.Code != http.StatusCreated || created.Customer.CustomerName != "John Smith" || len(created.Items) != 1 {
+		t.Fatalf("create: %d %s", w.Code, w.Body)
+	}
+	order["OrderID"], order["CustomerID"] = "2002", "C000"
+	if w := serve(router, http.MethodPost, "/v1/orders", constants.RoleIngestor, order); w.Code != http.StatusConflict {
+		t.Errorf("creating an order of an unknown customer: %d, want 409", w.Code)
+	}
+
+	w = serve(router, http.MethodPost, "/v1/orders/2001/items", constants.RoleIngestor, map[string]any{"ProductID": "P789", "QuantitySold": 1, "Discount": 0.1})
+	item := decode[models.OrderItem](t, w)
+	if w.Code != http.StatusCreated || item.OrderID != "2001" || item.Product.ProductName != "Levi's 501 Jeans" {
+		t.Fatalf("create item: %d %s", w.Code, w.Body)
+	}
+	itemPath := "/v1/orders/2001/items/" + strconv.FormatUint(uint64(item.OrderItemID), 10)
+	w = serve(router, http.MethodPut, itemPath, constants.RoleIngestor, map[string]any{"ProductID": "P789", "QuantitySold": 4, "Discount": 0})
+	if updated := decode[models.OrderItem](t, w); w.Code != http.StatusOK || updated.QuantitySold != 4 {
+		t.Errorf("update item: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodGet, "/v1/orders/2001/items", constants.RoleViewer, nil); w.Code != http.StatusOK || len(decode[[]models.OrderItem](t, w)) != 2 {
+		t.Errorf("list items: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodDelete, itemPath, constants.RoleIngestor, nil); w.Code != http.StatusNoContent {
+		t.Errorf("delete item: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodDelete, "/v1/orders/2001/items/x", constants.RoleIngestor, nil); w.Code != http.StatusNotFound {
+		t.Errorf("deleting an item with a malformed id: %d, want 404", w.Code)
+	}
+
+	order["OrderID"], order["CustomerID"], order["Region"] = "2001", "C456", "Asia"
+	delete(order, "Items")
+	w = serve(router, http.MethodPut, "/v1/orders/2001", constants.RoleIngestor, order)
+	if updated := decode[models.OrderDetails](t, w); w.Code != http.StatusOK || updated.Region != "Asia" || len(updated.Items) != 1 {
+		t.Errorf("update: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodDelete, "/v1/orders/2001", constants.RoleIngestor, nil); w.Code != http.StatusNoContent {
+		t.Errorf("delete: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodGet, "/v1/orders/2001", constants.RoleViewer, nil); w.Code != http.StatusNotFound {
+		t.Errorf("getting a deleted order: %d, want 404", w.Code)
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	router := newLoadedRouter(t)
+
+	seen := map[string]bool{}
+	target := "/v1/products?limit=3&sort=unit_price:desc"
+	for pages := 0; target != ""; pages++ {
+		w := serve(router, http.MethodGet, target, constants.RoleViewer, nil)
+		page := decode[models.Page[models.Product]](t, w)
+		if w.Code != http.StatusOK || pages > 2 {
+			t.Fatalf("%s: %d %s", target, w.Code, w.Body)
+		}
+		for _, product := range page.Data {
+			seen[product.ProductID] = true
+		}
+		target = ""
+		if page.Meta.NextCursor != "" {
+			target = "/v1/products?limit=3&sort=unit_price:desc&cursor=" + page.Meta.NextCursor
+		}
+		if len(seen) == int(page.Meta.Total) && target != "" {
+			t.Errorf("a next cursor after the last page: %+v", page.Meta)
+		}
+	}
+	if len(seen) != 4 {
+		t.Errorf("paged through %d products, want 4", len(seen))
+	}
+
+	w := serve(router, http.MethodGet, "/v1/products?limit=3&sort=unit_price:desc", constants.RoleViewer, nil)
+	cursor := decode[models.Page[models.Product]](t, w).Meta.NextCursor
+
+	for _, test := range []struct {
+		query  string
+		fields []string
+	}{
+		{"sort=secret&limit=0&cursor=%25%25", []string{constants.Cursor, constants.PageLimit, constants.Sort}},
+		{"sort=product_name:sideways&page=0", []string{constants.PageNumber, constants.Sort}},
+		{"cursor=" + cursor, []string{constants.Cursor}},
+		{"sort=unit_price:desc&page=2&cursor=" + cursor, []string{constants.PageLimit}},
+		{"sort=product_id:asc&page_size=2&cursor=" + cursor, []string{constants.PageLimit, constants.Cursor}},
+	} {
+		w := serve(router, http.MethodGet, "/v1/products?"+test.query, constants.RoleViewer, nil)
+		apiError := decode[models.APIError](t, w)
+		var fields []string
+		for _, detail := range apiError.Details {
+			fields = append(fields, detail.Field)
+		}
+		if w.Code != http.StatusBadRequest || !slices.Equal(fields, test.fields) {
+			t.Errorf("%s: %d reports %v, want %v", test.query, w.Code, fields, test.fields)
+		}
+	}
+}
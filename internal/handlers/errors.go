@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sales/internal/constants"
+	"sales/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// error codes returned in the error envelope
+const (
+	CodeInvalidParameter = "INVALID_PARAMETER"
+	CodeValidationError  = "VALIDATION_ERROR"
+	CodeInvalidBody      = "INVALID_BODY"
+	CodeUnauthorized     = "UNAUTHORIZED"
+	CodeForbidden        = "FORBIDDEN"
+	CodeInvalidTenant    = "INVALID_TENANT"
+	CodeNotFound         = "NOT_FOUND"
+	CodeConflict         = "CONFLICT"
+	CodeRateLimited      = "RATE_LIMITED"
+	CodeInternalError    = "INTERNAL_ERROR"
+)
+
+// errorMapping is the HTTP status, code and, for parameter errors, the query parameter an error is reported with.
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+	field  string
+}
+
+// errorMappings is matched in order, so an error wrapping several sentinels is reported as the first one
+// listed: authentication before authorization, tenant and rate limit errors, then the request, and the
+// resource errors last.
+var errorMappings = []errorMapping{
+	{constants.ErrUnauthorized, http.StatusUnauthorized, CodeUnauthorized, ""},
+	{constants.ErrForbidden, http.StatusForbidden, CodeForbidden, ""},
+	{constants.ErrTenantDenied, http.StatusForbidden, CodeForbidden, ""},
+	{constants.ErrUnknownTenant, http.StatusBadRequest, CodeInvalidTenant, ""},
+	{constants.ErrTenantMissing, http.StatusBadRequest, CodeInvalidTenant, ""},
+	{constants.ErrRateLimited, http.StatusTooManyRequests, CodeRateLimited, ""},
+	{constants.ErrInvalidBody, http.StatusBadRequest, CodeInvalidBody, ""},
+	{constants.ErrIDMismatch, http.StatusBadRequest, CodeInvalidBody, ""},
+	{constants.ErrInvalidLimit, http.StatusBadRequest, CodeInvalidParameter, constants.Limit},
+	{constants.ErrInvalidStartDate, http.StatusBadRequest, CodeInvalidParameter, constants.StartDate},
+	{constants.ErrInvalidEndDate, http.StatusBadRequest, CodeInvalidParameter, constants.EndDate},
+	{constants.ErrInvalidHorizon, http.StatusBadRequest, CodeInvalidParameter, constants.Horizon},
+	{constants.ErrInvalidMethod, http.StatusBadRequest, CodeInvalidParameter, constants.Method},
+	{constants.ErrMissingTarget, http.StatusBadRequest, CodeInvalidParameter, constants.ProductID},
+	{constants.ErrInvalidInterval, http.StatusBadRequest, CodeInvalidParameter, constants.Interval},
+	{constants.ErrInvalidMinVolume, http.StatusBadRequest, CodeInvalidParameter, constants.MinVolume},
+	{constants.ErrInvalidRows, http.StatusBadRequest, CodeInvalidParameter, constants.Rows},
+	{constants.ErrInvalidColumns, http.StatusBadRequest, CodeInvalidParameter, constants.Columns},
+	{constants.ErrSameDimensions, http.StatusBadRequest, CodeInvalidParameter, constants.Columns},
+	{constants.ErrInvalidMetric, http.StatusBadRequest, CodeInvalidParameter, constants.Metric},
+	{constants.ErrInvalidPercent, http.StatusBadRequest, CodeInvalidParameter, constants.Percent},
+	{constants.ErrInvalidFormat, http.StatusBadRequest, CodeInvalidParameter, constants.Format},
+	{constants.ErrInvalidGroupBy, http.StatusBadRequest, CodeInvalidParameter, constants.GroupBy},
+	{constants.ErrInvalidBuckets, http.StatusBadRequest, CodeInvalidParameter, constants.Buckets},
+	{constants.ErrInvalidTimezone, http.StatusBadRequest, CodeInvalidParameter, constants.Timezone},
+	{constants.ErrInvalidPageLimit, http.StatusBadRequest, CodeInvalidParameter, constants.PageLimit},
+	{constants.ErrInvalidPageSize, http.StatusBadRequest, CodeInvalidParameter, constants.PageSize},
+	{constants.ErrInvalidPage, http.StatusBadRequest, CodeInvalidParameter, constants.PageNumber},
+	{constants.ErrInvalidCursor, http.StatusBadRequest, CodeInvalidParameter, constants.Cursor},
+	{constants.ErrInvalidSort, http.StatusBadRequest, CodeInvalidParameter, constants.Sort},
+	{constants.ErrMixedPagination, http.StatusBadRequest, CodeInvalidParameter, constants.PageLimit},
+	{constants.ErrInvalidMinPrice, http.StatusBadRequest, CodeInvalidParameter, constants.MinPrice},
+	{constants.ErrInvalidMaxPrice, http.StatusBadRequest, CodeInvalidParameter, constants.MaxPrice},
+	{constants.ErrPriceRangeOrder, http.StatusBadRequest, CodeInvalidParameter, constants.MaxPrice},
+	{constants.ErrInvalidOffset, http.StatusBadRequest, CodeInvalidParameter, constants.GraphQLOffset},
+	{constants.ErrInvalidSortBy, http.StatusBadRequest, CodeInvalidParameter, constants.GraphQLSortBy},
+	{constants.ErrInvalidDimension, http.StatusBadRequest, CodeInvalidParameter, constants.GRPCDimension},
+	{constants.ErrDateRangeOrder, http.StatusBadRequest, CodeInvalidParameter, constants.EndDate},
+	{constants.ErrDateRangeTooLong, http.StatusBadRequest, CodeInvalidParameter, constants.EndDate},
+	{constants.ErrProductNotFound, http.StatusNotFound, CodeNotFound, ""},
+	{constants.ErrCustomerNotFound, http.StatusNotFound, CodeNotFound, ""},
+	{constants.ErrOrderNotFound, http.StatusNotFound, CodeNotFound, ""},
+	{constants.ErrOrderItemNotFound, http.StatusNotFound, CodeNotFound, ""},
+	{constants.ErrProductExists, http.StatusConflict, CodeConflict, ""},
+	{constants.ErrCustomerExists, http.StatusConflict, CodeConflict, ""},
+	{constants.ErrOrderExists, http.StatusConflict, CodeConflict, ""},
+	{constants.ErrProductInUse, http.StatusConflict, CodeConflict, ""},
+	{constants.ErrCustomerInUse, http.StatusConflict, CodeConflict, ""},
+	{constants.ErrUnknownProduct, http.StatusConflict, CodeConflict, ""},
+	{constants.ErrUnknownCustomer, http.StatusConflict, CodeConflict, ""},
+}
+
+// ErrorMiddleware renders the last error attached to the context with ctx.Error as an error envelope.
+// Client errors are reported as 4xx with their message, anything else as a 500 with a generic message.
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+			return
+		}
+
+		err := ctx.Errors.Last().Err
+		status, apiError := ToAPIError(err)
+		apiError.RequestID = ctx.GetString(RequestIDKey)
+		if status >= http.StatusInternalServerError {
+			log.Printf("Request %s failed: %v", apiError.RequestID, err)
+		}
+
+		ctx.AbortWithStatusJSON(status, apiError)
+	}
+}
+
+// NotFoundHandler renders unknown routes as an error envelope.
+func NotFoundHandler(ctx *gin.Context) {
+	ctx.AbortWithStatusJSON(http.StatusNotFound, models.APIError{
+		Code:      CodeNotFound,
+		Message:   "route not found",
+		RequestID: ctx.GetString(RequestIDKey),
+	})
+}
+
+// ToAPIError maps an error to its HTTP status and envelope. The GraphQL and gRPC servers use it to
+// report errors with the same codes and messages as the REST API.
+func ToAPIError(err error) (int, models.APIError) {
+	var violations models.ValidationErrors
+	if errors.As(err, &violations) && len(violations) > 0 {
+		return http.StatusBadRequest, models.APIError{
+			Code:    CodeInvalidParameter,
+			Message: fmt.Sprintf("request has %d invalid parameter(s)", len(violations)),
+			Field:   violations[0].Field,
+			Details: violations,
+		}
+	}
+
+	for _, mapping := range errorMappings {
+		if !errors.Is(err, mapping.err) {
+			continue
+		}
+		// parameter errors are reported with the sentinel's message, free of any wrapping context
+		if mapping.field != "" {
+			return mapping.status, models.APIError{Code: mapping.code, Message: mapping.err.Error(), Field: mapping.field}
+		}
+		return mapping.status, models.APIError{Code: mapping.code, Message: err.Error()}
+	}
+
+	var customErr *models.CustomError
+	if errors.As(err, &customErr) {
+		return http.StatusBadRequest, models.APIError{Code: CodeValidationError, Message: customErr.Error()}
+	}
+
+	return http.StatusInternalServerError, models.APIError{Code: CodeInternalError, Message: "internal server error"}
+}
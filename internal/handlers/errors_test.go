@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"testing"
+)
+
+func TestToAPIError(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		field  string
+	}{
+		{"a parameter error", constants.ErrInvalidLimit, http.StatusBadRequest, CodeInvalidParameter, constants.Limit},
+		{"a wrapped resource error", fmt.Errorf("loading: %w", constants.ErrOrderNotFound), http.StatusNotFound, CodeNotFound, ""},
+		{"authentication before a missing resource", fmt.Errorf("%w: %w", constants.ErrProductNotFound, constants.ErrUnauthorized), http.StatusUnauthorized, CodeUnauthorized, ""},
+		{"authorization before a parameter", errors.Join(constants.ErrInvalidLimit, constants.ErrForbidden), http.StatusForbidden, CodeForbidden, ""},
+		{"a parameter before a conflict", errors.Join(constants.ErrProductInUse, fmt.Errorf("checking: %w", constants.ErrInvalidSort)), http.StatusBadRequest, CodeInvalidParameter, constants.Sort},
+		{"a tenant before the body", errors.Join(constants.ErrInvalidBody, constants.ErrTenantMissing), http.StatusBadRequest, CodeInvalidTenant, ""},
+		{"violations", models.ValidationErrors{{Field: constants.Buckets, Message: "invalid"}}, http.StatusBadRequest, CodeInvalidParameter, constants.Buckets},
+		{"a custom error", &models.CustomError{Message: "bad row"}, http.StatusBadRequest, CodeValidationError, ""},
+		{"an unknown error", errors.New("disk full"), http.StatusInternalServerError, CodeInternalError, ""},
+	} {
+		status, apiError := ToAPIError(test.err)
+		if status != test.status || apiError.Code != test.code || apiError.Field != test.field {
+			t.Errorf("%s: %d %+v, want %d %s on %q", test.name, status, apiError, test.status, test.code, test.field)
+		}
+	}
+
+	// a parameter error reports the sentinel's message, whatever wraps it
+	if _, apiError := ToAPIError(fmt.Errorf("parsing n: %w", constants.ErrInvalidLimit)); apiError.Message != constants.ErrInvalidLimit.Error() {
+		t.Errorf("message %q, want %q", apiError.Message, constants.ErrInvalidLimit)
+	}
+}
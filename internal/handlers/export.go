@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"sales/internal/constants"
+	"sales/internal/utils"
+	"sales/pkg/export"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// formatRule validates the format parameter of the analytics endpoints.
+var formatRule = utils.QueryRule{Validate: utils.EnumRule(constants.ErrInvalidFormat, constants.Formats...)}
+
+// negotiateFormat picks the response format from the format parameter, then the Accept header by
+// quality, defaulting to json when neither names a supported format.
+func negotiateFormat(ctx *gin.Context) string {
+	if format := ctx.Query(constants.Format); format != "" {
+		return format
+	}
+
+	format, bestQuality := constants.FormatJSON, 0.0
+	for _, accepted := range strings.Split(ctx.GetHeader("Accept"), ",") {
+		mediaType, params, _ := strings.Cut(accepted, ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			quality, _ = strconv.ParseFloat(q, 64)
+		}
+		for candidate, candidateType := range constants.FormatMediaTypes {
+			if strings.TrimSpace(mediaType) == candidateType && quality > bestQuality {
+				format, bestQuality = candidate, quality
+			}
+		}
+	}
+	return format
+}
+
+// writeResult responds with result as JSON, or writes its tidy table as CSV, XLSX or NDJSON
+// in the negotiated format. name is used for the download's file and sheet name.
+// Results tagged by ConditionalGetMiddleware carry their ETag.
+func writeResult(ctx *gin.Context, name string, result any, table func() export.Table) {
+	if tag := ctx.GetString(ETagKey); tag != "" {
+		setCacheHeaders(ctx, tag)
+	}
+	format := negotiateFormat(ctx)
+	if format == constants.FormatJSON {
+		ctx.JSON(http.StatusOK, result)
+		return
+	}
+
+	contentType := constants.FormatMediaTypes[format]
+	if format != constants.FormatXLSX {
+		contentType += "; charset=utf-8"
+	}
+	ctx.Header("Content-Type", contentType)
+	if format != constants.FormatNDJSON {
+		ctx.Header("Content-Disposition", `attachment; filename="`+name+"."+format+`"`)
+	}
+	ctx.Status(http.StatusOK)
+
+	// the status is already sent once rows are flushed, so failures can only be logged
+	var writer export.Writer
+	var err error
+	switch format {
+	case constants.FormatCSV:
+		writer = export.NewCSVWriter(ctx.Writer)
+	case constants.FormatNDJSON:
+		writer = export.NewNDJSONWriter(ctx.Writer)
+	case constants.FormatXLSX:
+		writer, err = export.NewXLSXWriter(ctx.Writer, name)
+	}
+	if err == nil {
+		err = export.Write(writer, table(), constants.ExportFlushRows, ctx.Writer.Flush)
+	}
+	if err != nil {
+		log.Printf("Request %s failed while streaming %s: %v", ctx.GetString(RequestIDKey), format, err)
+	}
+}
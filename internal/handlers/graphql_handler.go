@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sales/internal/constants"
+	"sales/internal/models"
+
+	"github.com/gin-gonic/gin"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+// GraphQLHandler executes a GraphQL query against schema. Resolver errors are reported in the
+// response's errors list with the code and message the REST error envelope would carry.
+func GraphQLHandler(schema *graphqlgo.Schema) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request models.GraphQLRequest
+		if err := bindJSON(ctx, &request); err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		if request.Query == "" {
+			_ = ctx.Error(fmt.Errorf("%w: missing query", constants.ErrInvalidBody))
+			return
+		}
+
+		response := schema.Exec(ctx.Request.Context(), request.Query, request.OperationName, request.Variables)
+		for _, queryErr := range response.Errors {
+			if queryErr.ResolverError == nil {
+				continue
+			}
+
+			status, apiError := ToAPIError(queryErr.ResolverError)
+			if status >= http.StatusInternalServerError {
+				log.Printf("Request %s failed: %v", ctx.GetString(RequestIDKey), queryErr.ResolverError)
+			}
+			queryErr.Message = apiError.Message
+			queryErr.Extensions = map[string]any{"code": apiError.Code}
+			if apiError.Field != "" {
+				queryErr.Extensions["field"] = apiError.Field
+			}
+			if len(apiError.Details) > 0 {
+				queryErr.Extensions["details"] = apiError.Details
+			}
+		}
+
+		ctx.JSON(http.StatusOK, response)
+	}
+}
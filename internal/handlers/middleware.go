@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request id on requests and responses.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey is the gin context key holding the request id.
+const RequestIDKey = "request_id"
+
+// validRequestID matches the caller request ids that are safe to echo in headers and logs.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestIDMiddleware reuses the caller's X-Request-ID when it is at most 128 letters, digits, dots,
+// underscores or hyphens, generates one otherwise, and echoes it on the response.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+		ctx.Set(RequestIDKey, requestID)
+		ctx.Header(RequestIDHeader, requestID)
+		ctx.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// DeprecatedAliasMiddleware marks responses of unversioned alias routes as deprecated and links the
+// versioned route replacing them.
+func DeprecatedAliasMiddleware(version string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Deprecation", "true")
+		ctx.Header("Link", "</"+version+ctx.Request.URL.Path+`>; rel="successor-version"`)
+		ctx.Next()
+	}
+}
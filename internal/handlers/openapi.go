@@ -0,0 +1,442 @@
+package handlers
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+	"sales/internal/config"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/utils"
+	"sales/pkg/forecast"
+	"sales/pkg/openapi"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed swagger.html
+var swaggerUIPage []byte
+
+// routeDoc documents a registered route for the OpenAPI document.
+type routeDoc struct {
+	Tag         string
+	Summary     string
+	Description string
+	// Rules are the query rules the route validates with; its parameters are documented from them
+	Rules *utils.QueryRules
+	// Body is a value of the request body type, nil when the route takes no body
+	Body any
+	// Status is the success status, 200 when zero
+	Status int
+	// Response is a value of the success response type, nil when the route responds without content
+	Response any
+	// ContentType is the success content type, application/json when empty
+	ContentType string
+	// Export marks analytics routes that can also respond with CSV, XLSX or NDJSON
+	Export bool
+	// Roles are the roles accepted by the route's RequireRoles check, nil for public routes
+	Roles []string
+	// Root marks routes served only at the root, outside the API versions
+	Root bool
+}
+
+// paramDocs describes every query parameter accepted by some route.
+var paramDocs = map[string]struct {
+	Description string
+	Schema      *openapi.Schema
+}{
+	constants.Limit:         {"Number of products to return, at most 100.", intSchema(1, constants.MaxLimit)},
+	constants.StartDate:     {"Inclusive start date (YYYY-MM-DD).", &openapi.Schema{Type: "string", Format: "date"}},
+	constants.EndDate:       {"Inclusive end date (YYYY-MM-DD).", &openapi.Schema{Type: "string", Format: "date"}},
+	constants.Timezone:      {"IANA timezone day boundaries and buckets follow, UTC by default.", &openapi.Schema{Type: "string"}},
+	constants.ProductID:     {"Product to forecast.", &openapi.Schema{Type: "string"}},
+	constants.Horizon:       {"Weeks to forecast, 4 by default.", intSchema(1, constants.MaxForecastHorizon)},
+	constants.Method:        {"Forecasting method, all methods when omitted.", enumSchema(forecast.Methods...)},
+	constants.Interval:      {"Time bucket, month by default.", enumSchema(constants.Intervals...)},
+	constants.MinVolume:     {"Minimum units sold in the compared window, 5 by default.", intSchema(0, -1)},
+	constants.Rows:          {"Row dimension.", enumSchema(constants.PivotDimensions...)},
+	constants.Columns:       {"Column dimension, different from rows.", enumSchema(constants.PivotDimensions...)},
+	constants.Metric:        {"Aggregated metric, quantity by default.", enumSchema(constants.Metrics...)},
+	constants.Percent:       {"Express values as a percentage of their row or column total.", enumSchema(constants.PercentModes...)},
+	constants.Format:        {"Response format, overriding the Accept header; json by default.", enumSchema(constants.Formats...)},
+	constants.GroupBy:       {"Optional breakdown dimension.", enumSchema(constants.DistributionDimensions...)},
+	constants.Buckets:       {"Histogram bins, 10 by default.", intSchema(1, constants.MaxHistogramBuckets)},
+	constants.Category:      {"Product categories to include, repeated or comma separated.", nil},
+	constants.Region:        {"Order regions to include, repeated or comma separated.", nil},
+	constants.PaymentMethod: {"Payment methods to include, repeated or comma separated.", nil},
+	constants.CustomerID:    {"Customers to include, repeated or comma separated.", nil},
+	constants.MinPrice:      {"Minimum product unit price.", &openapi.Schema{Type: "number", Minimum: float(0)}},
+	constants.MaxPrice:      {"Maximum product unit price.", &openapi.Schema{Type: "number", Minimum: float(0)}},
+	constants.PageLimit:     {"Page size in cursor mode, 20 by default.", intSchema(1, constants.MaxPageSize)},
+	constants.Cursor:        {"Offset token from the previous page's meta.next_cursor, only valid with the same sort.", &openapi.Schema{Type: "string"}},
+	constants.PageNumber:    {"1-based page number in page mode.", intSchema(1, -1)},
+	constants.PageSize:      {"Page size in page mode, 20 by default.", intSchema(1, constants.MaxPageSize)},
+	constants.Sort:          {"Comma separated field:dir pairs, dir being asc or desc.", &openapi.Schema{Type: "string"}},
+}
+
+// entityTags tag the entity routes, whose writes may conflict with existing resources.
+var entityTags = []string{"products", "customers", "orders"}
+
+// routeDocs documents every route registered by SetupRoutes, keyed by "METHOD path" without the
+// version prefix.
+var routeDocs = map[string]routeDoc{
+	"GET /openapi.json": {Tag: "docs", Summary: "This OpenAPI document", Response: map[string]any{}, Root: true},
+	"GET /docs":         {Tag: "docs", Summary: "Swagger UI for this API", Response: "", ContentType: "text/html", Root: true},
+
+	"POST /graphql": {
+		Tag: "graphql", Summary: "Run a GraphQL query over products, customers, orders and sales aggregates",
+		Description: "Responds 200 with data and errors as in the GraphQL spec; resolver errors carry the REST error code in extensions.code. The schema is available through introspection.",
+		Body:        models.GraphQLRequest{}, Response: map[string]any{}, Roles: analystRoles, Root: true,
+	},
+
+	"POST /refresh": {
+		Tag: "ingestion", Summary: "Reload the database from the CSV file",
+		Response: "", ContentType: "text/plain", Roles: ingestRoles,
+	},
+	"GET /top-products/overall": {
+		Tag: "top products", Summary: "Top n products by quantity sold",
+		Rules: &topProductsRules, Response: []models.Product{}, Export: true, Roles: analystRoles,
+	},
+	"GET /top-products/category": {
+		Tag: "top products", Summary: "Top n products per category by quantity sold",
+		Rules: &topProductsRules, Response: map[string][]models.Product{}, Export: true, Roles: analystRoles,
+	},
+	"GET /top-products/region": {
+		Tag: "top products", Summary: "Top n products per region by quantity sold",
+		Rules: &topProductsRules, Response: map[string][]models.Product{}, Export: true, Roles: analystRoles,
+	},
+	"GET /top-products/trending": {
+		Tag: "top products", Summary: "Fastest growing and declining products",
+		Description: "Compares quantity sold in the range against the equally long window just before it.",
+		Rules:       &trendingRules, Response: models.TrendingProducts{}, Export: true, Roles: analystRoles,
+	},
+	"GET /analytics/forecast": {
+		Tag: "analytics", Summary: "Weekly sales forecast for a product or category",
+		Description: "Requires product_id or category. Every method is back-tested to report MAE and MAPE.",
+		Rules:       &forecastRules, Response: models.ForecastResult{}, Export: true, Roles: analystRoles,
+	},
+	"GET /analytics/customers/new-vs-returning": {
+		Tag: "analytics", Summary: "Customers, orders and revenue split between new and returning customers",
+		Rules: &customerSegmentRules, Response: []models.CustomerRetentionPeriod{}, Export: true, Roles: analystRoles,
+	},
+	"GET /analytics/pivot": {
+		Tag: "analytics", Summary: "Pivot a metric across two dimensions",
+		Rules: &pivotRules, Response: models.PivotTable{}, Export: true, Roles: analystRoles,
+	},
+	"GET /analytics/distribution": {
+		Tag: "analytics", Summary: "Order value and quantity per line distributions",
+		Rules: &distributionRules, Response: models.DistributionResult{}, Export: true, Roles: analystRoles,
+	},
+
+	"GET /products": {
+		Tag: "products", Summary: "List products", Roles: readRoles,
+		Rules: &productListRules, Response: models.Page[models.Product]{},
+	},
+	"POST /products": {
+		Tag: "products", Summary: "Create a product", Roles: ingestRoles,
+		Body: models.ProductInput{}, Status: http.StatusCreated, Response: models.Product{},
+	},
+	"GET /products/:id":    {Tag: "products", Summary: "Get a product", Roles: readRoles, Response: models.Product{}},
+	"PUT /products/:id":    {Tag: "products", Summary: "Replace a product", Roles: ingestRoles, Body: models.ProductInput{}, Response: models.Product{}},
+	"DELETE /products/:id": {Tag: "products", Summary: "Delete a product without order items", Roles: ingestRoles, Status: http.StatusNoContent},
+
+	"GET /customers": {
+		Tag: "customers", Summary: "List customers", Roles: readRoles,
+		Rules: &customerListRules, Response: models.Page[models.Customer]{},
+	},
+	"POST /customers": {
+		Tag: "customers", Summary: "Create a customer", Roles: ingestRoles,
+		Body: models.CustomerInput{}, Status: http.StatusCreated, Response: models.Customer{},
+	},
+	"GET /customers/:id":    {Tag: "customers", Summary: "Get a customer", Roles: readRoles, Response: models.Customer{}},
+	"PUT /customers/:id":    {Tag: "customers", Summary: "Replace a customer", Roles: ingestRoles, Body: models.CustomerInput{}, Response: models.Customer{}},
+	"DELETE /customers/:id": {Tag: "customers", Summary: "Delete a customer without orders", Roles: ingestRoles, Status: http.StatusNoContent},
+
+	"GET /orders": {
+		Tag: "orders", Summary: "List orders with their customer", Roles: readRoles,
+		Rules: &orderListRules, Response: models.Page[models.Order]{},
+	},
+	"POST /orders": {
+		Tag: "orders", Summary: "Create an order with its items", Roles: ingestRoles,
+		Body: models.OrderInput{}, Status: http.StatusCreated, Response: models.OrderDetails{},
+	},
+	"GET /orders/:id": {Tag: "orders", Summary: "Get an order with its customer and items", Roles: readRoles, Response: models.OrderDetails{}},
+	"PUT /orders/:id": {
+		Tag: "orders", Summary: "Replace an order's fields", Roles: ingestRoles,
+		Description: "Items are managed through the order items resource and must be omitted.",
+		Body:        models.OrderInput{}, Response: models.OrderDetails{},
+	},
+	"DELETE /orders/:id":    {Tag: "orders", Summary: "Delete an order and its items", Roles: ingestRoles, Status: http.StatusNoContent},
+	"GET /orders/:id/items": {Tag: "orders", Summary: "List the items of an order", Roles: readRoles, Response: []models.OrderItem{}},
+	"POST /orders/:id/items": {
+		Tag: "orders", Summary: "Add an item to an order", Roles: ingestRoles,
+		Body: models.OrderItemInput{}, Status: http.StatusCreated, Response: models.OrderItem{},
+	},
+	"PUT /orders/:id/items/:item_id": {
+		Tag: "orders", Summary: "Replace an item of an order", Roles: ingestRoles,
+		Body: models.OrderItemInput{}, Response: models.OrderItem{},
+	},
+	"DELETE /orders/:id/items/:item_id": {Tag: "orders", Summary: "Remove an item from an order", Roles: ingestRoles, Status: http.StatusNoContent},
+
+	"GET /admin/config": {
+		Tag: "admin", Summary: "The effective configuration",
+		Description: "Settings after the configuration file, environment and flags are applied, with the passwords of the database DSN and Redis URL redacted.",
+		Response:    config.Config{}, Roles: adminRoles,
+	},
+}
+
+// BuildOpenAPISpec documents the registered routes, failing when a route has no entry in routeDocs.
+// Unversioned aliases of versioned routes are documented as deprecated.
+func BuildOpenAPISpec(routes gin.RoutesInfo) (openapi.Document, error) {
+	generator := openapi.NewGenerator()
+	errorSchema := generator.SchemaOf(models.APIError{})
+
+	doc := openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:       "Sales Insights API",
+			Version:     "1.0.0",
+			Description: "Sales analytics over products, customers and orders.",
+		},
+		Paths: map[string]openapi.PathItem{},
+		Components: openapi.Components{
+			SecuritySchemes: map[string]openapi.SecurityScheme{
+				"apiKey":     {Type: "apiKey", Name: APIKeyHeader, In: "header"},
+				"bearerAuth": {Type: "http", Scheme: "bearer", Description: "An API key, or an HS256 or RS256 JWT listing the caller's roles in its roles claim."},
+			},
+		},
+	}
+
+	var undocumented []string
+	for _, route := range routes {
+		version, unversionedPath := splitVersion(route.Path)
+		routeDoc, ok := routeDocs[route.Method+" "+unversionedPath]
+		if !ok || (routeDoc.Root && version != "") {
+			undocumented = append(undocumented, route.Method+" "+route.Path)
+			continue
+		}
+
+		path := openAPIPath(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = openapi.PathItem{}
+		}
+		operation := buildOperation(generator, route, routeDoc, errorSchema)
+		if version == "" && !routeDoc.Root {
+			operation.Deprecated = true
+			operation.Description = strings.TrimSpace(operation.Description + " Deprecated alias of /" + constants.APIVersionV1 + route.Path + ".")
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = operation
+	}
+	if len(undocumented) > 0 {
+		sort.Strings(undocumented)
+		return openapi.Document{}, fmt.Errorf("routes missing from the OpenAPI document: %s", strings.Join(undocumented, ", "))
+	}
+
+	doc.Components.Schemas = generator.Schemas()
+	return doc, nil
+}
+
+func buildOperation(generator *openapi.Generator, route gin.RouteInfo, routeDoc routeDoc, errorSchema *openapi.Schema) *openapi.Operation {
+	operation := &openapi.Operation{
+		Summary:     routeDoc.Summary,
+		Description: routeDoc.Description,
+		OperationID: operationID(route.Method, route.Path),
+		Tags:        []string{routeDoc.Tag},
+		Responses:   map[string]openapi.Response{},
+	}
+
+	for _, segment := range strings.Split(route.Path, "/") {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			operation.Parameters = append(operation.Parameters, openapi.Parameter{
+				Name: name, In: "path", Required: true, Schema: &openapi.Schema{Type: "string"},
+			})
+		}
+	}
+	if routeDoc.Rules != nil {
+		operation.Parameters = append(operation.Parameters, queryParameters(*routeDoc.Rules)...)
+	}
+
+	if routeDoc.Body != nil {
+		operation.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content:  map[string]openapi.MediaType{"application/json": {Schema: generator.SchemaOf(routeDoc.Body)}},
+		}
+	}
+
+	status := routeDoc.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := openapi.Response{Description: http.StatusText(status)}
+	if routeDoc.Response != nil {
+		contentType := routeDoc.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		success.Content = map[string]openapi.MediaType{contentType: {Schema: generator.SchemaOf(routeDoc.Response)}}
+		if routeDoc.Export {
+			for _, format := range constants.Formats {
+				if format == constants.FormatJSON {
+					continue
+				}
+				schema := &openapi.Schema{Type: "string", Description: "The result flattened into tidy rows."}
+				if format == constants.FormatXLSX {
+					schema.Format = "binary"
+				}
+				success.Content[constants.FormatMediaTypes[format]] = openapi.MediaType{Schema: schema}
+			}
+		}
+	}
+	if routeDoc.Export {
+		// analytics results are tagged by ConditionalGetMiddleware
+		success.Headers = map[string]openapi.Header{
+			"ETag":          {Description: "Tag of the result, changed by any write to the tenant's data", Schema: &openapi.Schema{Type: "string"}},
+			"Cache-Control": {Description: "Always " + constants.CacheControl + ": reuse requires revalidation", Schema: &openapi.Schema{Type: "string"}},
+		}
+		operation.Parameters = append(operation.Parameters, openapi.Parameter{
+			Name: "If-None-Match", In: "header", Schema: &openapi.Schema{Type: "string"},
+			Description: "ETag of a result held by the caller; answered with 304 when it is still current.",
+		})
+		operation.Responses[strconv.Itoa(http.StatusNotModified)] = openapi.Response{Description: "The result held by the caller is current"}
+	}
+	operation.Responses[strconv.Itoa(status)] = success
+
+	errorResponse := func(status int, description string) {
+		operation.Responses[strconv.Itoa(status)] = openapi.Response{
+			Description: description,
+			Content:     map[string]openapi.MediaType{"application/json": {Schema: errorSchema}},
+		}
+	}
+	if routeDoc.Rules != nil || routeDoc.Body != nil {
+		errorResponse(http.StatusBadRequest, "Invalid query parameters or body")
+	}
+	if routeDoc.Roles != nil {
+		operation.Security = []map[string][]string{{"apiKey": {}}, {"bearerAuth": {}}}
+		operation.Description = strings.TrimSpace(operation.Description + " Requires one of the roles: " + strings.Join(append(slices.Clone(routeDoc.Roles), constants.RoleAdmin), ", ") + ".")
+		operation.Parameters = append(operation.Parameters, openapi.Parameter{
+			Name: TenantHeader, In: "header", Schema: &openapi.Schema{Type: "string"},
+			Description: "Tenant to act for when the credentials are not bound to one; defaults to the first configured tenant.",
+		})
+		if _, ok := operation.Responses[strconv.Itoa(http.StatusBadRequest)]; !ok {
+			errorResponse(http.StatusBadRequest, "Unknown tenant")
+		}
+		errorResponse(http.StatusUnauthorized, "Missing or invalid credentials")
+		errorResponse(http.StatusForbidden, "Credentials lack a required role or are bound to another tenant")
+	}
+	if strings.Contains(route.Path, ":") {
+		errorResponse(http.StatusNotFound, "Resource not found")
+	}
+	if slices.Contains(entityTags, routeDoc.Tag) && route.Method != http.MethodGet {
+		errorResponse(http.StatusConflict, "Resource exists, is referenced or references a missing resource")
+	}
+	operation.Responses[strconv.Itoa(http.StatusTooManyRequests)] = openapi.Response{
+		Description: "Rate limit exceeded",
+		Headers: map[string]openapi.Header{
+			"Retry-After": {Description: "Seconds until the request would be allowed", Schema: &openapi.Schema{Type: "integer"}},
+		},
+		Content: map[string]openapi.MediaType{"application/json": {Schema: errorSchema}},
+	}
+	errorResponse(http.StatusInternalServerError, "Internal error")
+
+	return operation
+}
+
+// queryParameters documents the query parameters of a rule set in a stable order.
+func queryParameters(rules utils.QueryRules) []openapi.Parameter {
+	names := make([]string, 0, len(rules.Params))
+	for name := range rules.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parameters := make([]openapi.Parameter, 0, len(names))
+	for _, name := range names {
+		rule := rules.Params[name]
+		paramDoc := paramDocs[name]
+		parameter := openapi.Parameter{
+			Name:        name,
+			In:          "query",
+			Description: paramDoc.Description,
+			Required:    rule.Required,
+			Schema:      paramDoc.Schema,
+		}
+		if rule.Multi {
+			explode := true
+			parameter.Style = "form"
+			parameter.Explode = &explode
+			parameter.Schema = &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}}
+		}
+		if parameter.Schema == nil {
+			parameter.Schema = &openapi.Schema{Type: "string"}
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters
+}
+
+// splitVersion separates a mounted API version prefix from a route path.
+func splitVersion(path string) (string, string) {
+	for _, version := range apiVersions {
+		if rest, ok := strings.CutPrefix(path, "/"+version.Name+"/"); ok {
+			return version.Name, "/" + rest
+		}
+	}
+	return "", path
+}
+
+// openAPIPath converts gin path parameters (:id) to OpenAPI templates ({id}).
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID derives a camel case id such as getOrdersByIdItems from the method and path.
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, segment := range strings.Split(path, "/") {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segment = "by_" + name
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			id += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return id
+}
+
+func intSchema(min, max int) *openapi.Schema {
+	schema := &openapi.Schema{Type: "integer", Minimum: float(float64(min))}
+	if max >= 0 {
+		schema.Maximum = float(float64(max))
+	}
+	return schema
+}
+
+func enumSchema(values ...string) *openapi.Schema {
+	return &openapi.Schema{Type: "string", Enum: values}
+}
+
+func float(v float64) *float64 {
+	return &v
+}
+
+// OpenAPIHandler serves the OpenAPI document, which is built once every route is registered.
+func OpenAPIHandler(spec *openapi.Document) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, spec)
+	}
+}
+
+// SwaggerUIHandler serves a Swagger UI page rendering /openapi.json.
+func SwaggerUIHandler(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", swaggerUIPage)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"math"
+	"net/http"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"sales/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listRules returns the rules of a list endpoint: limit and cursor or page and page_size, and sort, a
+// comma separated list of field:dir pairs over the API field names sortable maps to columns.
+func listRules(sortable map[string]string, defaultSort string) utils.QueryRules {
+	return utils.QueryRules{
+		Params: map[string]utils.QueryRule{
+			constants.PageLimit:  {Validate: utils.IntRangeRule(constants.ErrInvalidPageLimit, 1, constants.MaxPageSize)},
+			constants.PageSize:   {Validate: utils.IntRangeRule(constants.ErrInvalidPageSize, 1, constants.MaxPageSize)},
+			constants.PageNumber: {Validate: utils.IntRangeRule(constants.ErrInvalidPage, 1, math.MaxInt32)},
+			constants.Cursor:     {Validate: func(v string) error { _, _, err := decodeOffsetToken(v); return err }},
+			constants.Sort:       {Default: defaultSort, Validate: func(v string) error { _, err := parseSort(v, sortable); return err }},
+		},
+		Check: checkPagination,
+	}
+}
+
+// rules of the list endpoints
+var (
+	productListRules  = listRules(repository.ProductSortColumns, "product_id:asc")
+	customerListRules = listRules(repository.CustomerSortColumns, "customer_id:asc")
+	orderListRules    = listRules(repository.OrderSortColumns, "date_of_sale:desc")
+)
+
+// checkPagination reports limit/cursor mixed with page/page_size and a cursor issued for another sort.
+func checkPagination(query utils.Query) models.ValidationErrors {
+	var violations models.ValidationErrors
+	cursorMode := query.Get(constants.PageLimit) != "" || query.Get(constants.Cursor) != ""
+	if cursorMode && (query.Get(constants.PageNumber) != "" || query.Get(constants.PageSize) != "") {
+		violations = append(violations, models.FieldError{Field: constants.PageLimit, Message: constants.ErrMixedPagination.Error()})
+	}
+	if cursor := query.Get(constants.Cursor); cursor != "" {
+		if _, sortParam, _ := decodeOffsetToken(cursor); sortParam != query.Get(constants.Sort) {
+			violations = append(violations, models.FieldError{Field: constants.Cursor, Message: constants.ErrInvalidCursor.Error()})
+		}
+	}
+	return violations
+}
+
+// parsePageRequest reads a query validated with listRules into a page request over the columns of sortable.
+func parsePageRequest(query utils.Query, sortable map[string]string) (models.PageRequest, string) {
+	sortParam := query.Get(constants.Sort)
+	sortFields, _ := parseSort(sortParam, sortable)
+	page := models.PageRequest{Limit: constants.DefaultPageSize, Sort: sortFields}
+
+	if query.Get(constants.PageNumber) != "" || query.Get(constants.PageSize) != "" {
+		if pageSize := query.Int(constants.PageSize); pageSize > 0 {
+			page.Limit = pageSize
+		}
+		number := 1
+		if pageNumber := query.Int(constants.PageNumber); pageNumber > 0 {
+			number = pageNumber
+		}
+		page.Offset = (number - 1) * page.Limit
+		return page, sortParam
+	}
+
+	page.UseCursor = true
+	if limit := query.Int(constants.PageLimit); limit > 0 {
+		page.Limit = limit
+	}
+	if cursor := query.Get(constants.Cursor); cursor != "" {
+		page.Offset, _, _ = decodeOffsetToken(cursor)
+	}
+	return page, sortParam
+}
+
+// parseSort parses a comma separated list of field:dir pairs; the direction defaults to asc.
+func parseSort(sortParam string, sortable map[string]string) ([]models.SortField, error) {
+	if sortParam == "" {
+		return nil, nil
+	}
+
+	var fields []models.SortField
+	for _, part := range strings.Split(sortParam, ",") {
+		name, direction, _ := strings.Cut(strings.TrimSpace(part), ":")
+		column, ok := sortable[name]
+		if !ok {
+			return nil, constants.ErrInvalidSort
+		}
+		switch strings.ToLower(direction) {
+		case "", "asc":
+			fields = append(fields, models.SortField{Field: name, Column: column})
+		case "desc":
+			fields = append(fields, models.SortField{Field: name, Column: column, Desc: true})
+		default:
+			return nil, constants.ErrInvalidSort
+		}
+	}
+	return fields, nil
+}
+
+// encodeOffsetToken produces the cursor of the page starting at offset. It is an opaque offset token
+// rather than a keyset position: records inserted or deleted before the offset shift the next page.
+// The token records the sort it was issued for.
+func encodeOffsetToken(offset int, sortParam string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset) + "|" + sortParam))
+}
+
+// decodeOffsetToken returns the offset of a cursor and the sort it was issued for.
+func decodeOffsetToken(cursor string) (int, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", constants.ErrInvalidCursor
+	}
+	offsetStr, sortParam, ok := strings.Cut(string(raw), "|")
+	offset, err := strconv.Atoi(offsetStr)
+	if !ok || err != nil || offset < 0 {
+		return 0, "", constants.ErrInvalidCursor
+	}
+	return offset, sortParam, nil
+}
+
+// writePage responds with a page of items and its metadata.
+func writePage[T any](ctx *gin.Context, items []T, total int64, page models.PageRequest, sortParam string) {
+	if items == nil {
+		items = []T{}
+	}
+
+	meta := models.PageMeta{Total: total, Limit: page.Limit, Sort: sortParam}
+	if page.UseCursor {
+		if next := page.Offset + len(items); int64(next) < total {
+			meta.NextCursor = encodeOffsetToken(next, sortParam)
+		}
+	} else {
+		meta.Page = page.Offset/page.Limit + 1
+		meta.TotalPages = int((total + int64(page.Limit) - 1) / int64(page.Limit))
+	}
+
+	ctx.JSON(http.StatusOK, models.Page[T]{Data: items, Meta: meta})
+}
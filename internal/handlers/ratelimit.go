@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sales/internal/auth"
+	"sales/internal/constants"
+	"sales/pkg/ratelimit"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultRateLimits are the limits of each route class unless the rate limits file overrides them.
+var DefaultRateLimits = map[string]ratelimit.Limit{
+	constants.RateClassAnalytics: {PerMinute: constants.AnalyticsPerMinute, Burst: constants.AnalyticsBurst},
+	constants.RateClassRefresh:   {PerMinute: constants.RefreshPerMinute, Burst: constants.RefreshBurst},
+	constants.RateClassDefault:   {PerMinute: constants.DefaultPerMinute, Burst: constants.DefaultBurst},
+}
+
+// LoadRateLimits returns the default limits overridden per class by the rate limits file at path,
+// if any.
+func LoadRateLimits(path string) (map[string]ratelimit.Limit, error) {
+	limits := make(map[string]ratelimit.Limit, len(DefaultRateLimits))
+	for class, limit := range DefaultRateLimits {
+		limits[class] = limit
+	}
+
+	if path == "" {
+		return limits, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var overrides map[string]ratelimit.Limit
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("invalid rate limits file %s: %w", path, err)
+	}
+	for class, limit := range overrides {
+		if _, ok := limits[class]; !ok {
+			return nil, fmt.Errorf("rate limits file %s: unknown route class %q", path, class)
+		}
+		if limit.PerMinute < 0 || (!limit.Unlimited() && limit.Burst < 1) {
+			return nil, fmt.Errorf("rate limits file %s: class %q needs a non-negative per_minute and a burst of at least 1", path, class)
+		}
+		limits[class] = limit
+	}
+	return limits, nil
+}
+
+// RateLimitMiddleware takes a token from the caller's bucket, keyed by the authenticated principal
+// or else the client IP, and rejects the request with 429 when the bucket is empty. Responses carry
+// RateLimit-* headers; rejections also carry Retry-After. It must run after AuthMiddleware to
+// limit per principal.
+func RateLimitMiddleware(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	limit := limiter.Limit()
+	if limit.Unlimited() {
+		return func(ctx *gin.Context) { ctx.Next() }
+	}
+	policy := fmt.Sprintf("%d;w=%d", limit.Burst, seconds(limit.Window()))
+
+	return func(ctx *gin.Context) {
+		result := limiter.Allow(rateLimitKey(ctx), time.Now())
+		ctx.Header("RateLimit-Policy", policy)
+		ctx.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		ctx.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.Header("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+		if !result.Allowed {
+			ctx.Header("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+			_ = ctx.Error(constants.ErrRateLimited)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// rateLimitKey identifies the client a request is counted against.
+func rateLimitKey(ctx *gin.Context) string {
+	if principal, ok := ctx.Value(PrincipalKey).(auth.Principal); ok {
+		return "principal:" + principal.Name
+	}
+	return "ip:" + ctx.ClientIP()
+}
+
+// seconds rounds a duration up to whole seconds, as the rate limit headers expect.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/repository/memory"
+	"sales/pkg/ratelimit"
+	"strings"
+	"testing"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	// two requests, then one more a second
+	router := newLimitedRouter(t, memory.NewStore(), map[string]ratelimit.Limit{
+		constants.RateClassDefault: {PerMinute: 60, Burst: 2},
+	})
+
+	for i, want := range []struct{ remaining, reset string }{{"1", "1"}, {"0", "2"}} {
+		w := serve(router, http.MethodGet, "/v1/products", constants.RoleViewer, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: %d %s", i+1, w.Code, w.Body)
+		}
+		for header, value := range map[string]string{
+			"RateLimit-Policy":    "2;w=2",
+			"RateLimit-Limit":     "2",
+			"RateLimit-Remaining": want.remaining,
+			"RateLimit-Reset":     want.reset,
+		} {
+			if got := w.Header().Get(header); got != value {
+				t.Errorf("request %d: %s %q, want %q", i+1, header, got, value)
+			}
+		}
+		if got := w.Header().Get("Retry-After"); got != "" {
+			t.Errorf("request %d: Retry-After %q on an allowed request", i+1, got)
+		}
+	}
+
+	// the deprecated alias shares the bucket of its v1 route
+	w := serve(router, http.MethodGet, "/products", constants.RoleViewer, nil)
+	if apiError := decode[models.APIError](t, w); w.Code != http.StatusTooManyRequests || apiError.Code != CodeRateLimited || apiError.RequestID == "" {
+		t.Fatalf("request past the burst: %d %s, want 429 %s", w.Code, w.Body, CodeRateLimited)
+	}
+	for header, want := range map[string]string{
+		"Retry-After":         "1",
+		"RateLimit-Limit":     "2",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "2",
+	} {
+		if got := w.Header().Get(header); got != want {
+			t.Errorf("request past the burst: %s %q, want %q", header, got, want)
+		}
+	}
+
+	// buckets are per principal, or per client IP before authentication
+	if w := serve(router, http.MethodGet, "/v1/products", constants.RoleAnalyst, nil); w.Code != http.StatusOK {
+		t.Errorf("another principal: %d %s, want 200", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodGet, "/openapi.json", "", nil); w.Code != http.StatusOK {
+		t.Errorf("anonymous client: %d, want 200", w.Code)
+	}
+	// an unlimited class sends no rate limit headers
+	if w := serve(router, http.MethodPost, "/v1/refresh", constants.RoleIngestor, nil); w.Header().Get("RateLimit-Limit") != "" {
+		t.Errorf("unlimited class: RateLimit-Limit %q, want none", w.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestLoadRateLimits(t *testing.T) {
+	limits, err := LoadRateLimits("")
+	if err != nil || len(limits) != len(DefaultRateLimits) {
+		t.Fatalf("no file: %v, %v, want the defaults", limits, err)
+	}
+
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "rate_limits.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	limits, err = LoadRateLimits(write(`{"refresh": {"per_minute": 6, "burst": 2}, "analytics": {"per_minute": 0}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for class, want := range map[string]ratelimit.Limit{
+		constants.RateClassRefresh:   {PerMinute: 6, Burst: 2},
+		constants.RateClassAnalytics: {},
+		constants.RateClassDefault:   DefaultRateLimits[constants.RateClassDefault],
+	} {
+		if limits[class] != want {
+			t.Errorf("class %s: %+v, want %+v", class, limits[class], want)
+		}
+	}
+
+	for _, test := range []struct {
+		name, content, want string
+	}{
+		{"unknown class", `{"reports": {"per_minute": 10, "burst": 5}}`, `unknown route class "reports"`},
+		{"zero burst", `{"refresh": {"per_minute": 10, "burst": 0}}`, `class "refresh"`},
+		{"negative rate", `{"default": {"per_minute": -1, "burst": 5}}`, `class "default"`},
+		{"invalid JSON", `{"default": 5}`, "invalid rate limits file"},
+	} {
+		if _, err := LoadRateLimits(write(test.content)); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: %v, want an error mentioning %s", test.name, err, test.want)
+		}
+	}
+	if _, err := LoadRateLimits(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("missing file: no error")
+	}
+}
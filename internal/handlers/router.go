@@ -1,14 +1,129 @@
 package handlers
 
 import (
+	"sales/internal/auth"
+	"sales/internal/config"
+	"sales/internal/constants"
+	"sales/internal/graphql"
+	"sales/internal/services"
+	"sales/internal/tenant"
+	"sales/pkg/openapi"
+	"sales/pkg/ratelimit"
+
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-// SetupRoutes initializes the routes for the application.
-func SetupRoutes(router *gin.Engine, db *gorm.DB) {
-	router.POST("/refresh", RefreshHandler(db))
-	router.GET("/top-products/overall", GetTopProductsOverallHandler(db))
-	router.GET("/top-products/category", GetTopProductsByCategoryHandler(db))
-	router.GET("/top-products/region", GetTopProductsByRegionHandler(db))
+// routeDeps are the shared dependencies every API version registers its routes with.
+// authenticate resolves the caller and their tenant; rateLimits holds the rate limit middleware of
+// each route class, shared by every version so aliases draw from the same buckets.
+type routeDeps struct {
+	refresh      *RefreshHandler
+	analytics    *AnalyticsHandler
+	entities     *EntityHandler
+	admin        *AdminHandler
+	cache        *services.ResultCache
+	authenticate gin.HandlersChain
+	rateLimits   map[string]gin.HandlerFunc
+}
+
+// apiVersion mounts one version of the API under /<Name>. Versions share the services layer,
+// so a new version registers only the routes it changes and reuses the rest.
+type apiVersion struct {
+	Name     string
+	Register func(group *gin.RouterGroup, deps routeDeps)
+}
+
+// apiVersions lists the mounted versions, oldest first.
+var apiVersions = []apiVersion{
+	{Name: constants.APIVersionV1, Register: registerV1Routes},
+}
+
+// SetupRoutes initializes the routes for the application, served by svc and configured by cfg.
+// Every version is mounted under its prefix and the unversioned paths are deprecated aliases of v1.
+// API routes require credentials accepted by authenticator and check the caller's roles per route.
+// They only see the data of the tenant the caller is bound to or selects among tenants.
+// Every route is rate limited per client with the limits of its route class.
+// Analytics results carry an ETag and are answered with 304 when the caller's copy is current.
+// Every request is tagged with an id and handler errors are rendered as error envelopes.
+// It fails when a registered route is missing from the OpenAPI document.
+func SetupRoutes(router *gin.Engine, cfg config.Config, svc *services.Services, authenticator *auth.Authenticator, tenants *tenant.Registry, rateLimits map[string]ratelimit.Limit) error {
+	router.Use(RequestIDMiddleware(), ErrorMiddleware())
+	router.NoRoute(NotFoundHandler)
+
+	deps := routeDeps{
+		refresh:      NewRefreshHandler(svc.Refresh),
+		analytics:    NewAnalyticsHandler(svc.Analytics),
+		entities:     NewEntityHandler(svc.Entities),
+		admin:        NewAdminHandler(cfg),
+		cache:        svc.Cache,
+		authenticate: gin.HandlersChain{AuthMiddleware(authenticator), TenantMiddleware(tenants)},
+		rateLimits:   make(map[string]gin.HandlerFunc, len(rateLimits)),
+	}
+	for class, limit := range rateLimits {
+		deps.rateLimits[class] = RateLimitMiddleware(ratelimit.New(limit))
+	}
+
+	var spec openapi.Document
+	public := router.Group("", deps.rateLimits[constants.RateClassDefault])
+	public.GET("/openapi.json", OpenAPIHandler(&spec))
+	public.GET("/docs", SwaggerUIHandler)
+
+	// the schema is unversioned; GraphQL evolves it by deprecating fields instead
+	router.Group("", deps.authenticate...).
+		POST("/graphql", deps.rateLimits[constants.RateClassAnalytics], RequireRoles(analystRoles...), GraphQLHandler(graphql.NewSchema(svc.Entities, svc.Analytics)))
+
+	for _, version := range apiVersions {
+		version.Register(router.Group("/"+version.Name), deps)
+	}
+	registerV1Routes(router.Group("", DeprecatedAliasMiddleware(constants.APIVersionV1)), deps)
+
+	// every route must be documented, so a missing entry in routeDocs fails at startup
+	var err error
+	spec, err = BuildOpenAPISpec(router.Routes())
+	return err
+}
+
+// registerV1Routes registers the v1 API.
+func registerV1Routes(group *gin.RouterGroup, deps routeDeps) {
+	group = group.Group("", deps.authenticate...)
+	read, analyze, ingest, admin := RequireRoles(readRoles...), RequireRoles(analystRoles...), RequireRoles(ingestRoles...), RequireRoles(adminRoles...)
+	refresh := group.Group("", deps.rateLimits[constants.RateClassRefresh])
+	analytics := group.Group("", deps.rateLimits[constants.RateClassAnalytics])
+	conditional := ConditionalGetMiddleware(deps.cache)
+	entities := group.Group("", deps.rateLimits[constants.RateClassDefault])
+	admins := group.Group("", deps.rateLimits[constants.RateClassDefault])
+
+	refresh.POST("/refresh", ingest, deps.refresh.Refresh)
+	analytics.GET("/top-products/overall", analyze, ValidateQueryMiddleware(topProductsRules), conditional, deps.analytics.GetTopProductsOverall)
+	analytics.GET("/top-products/category", analyze, ValidateQueryMiddleware(topProductsRules), conditional, deps.analytics.GetTopProductsByCategory)
+	analytics.GET("/top-products/region", analyze, ValidateQueryMiddleware(topProductsRules), conditional, deps.analytics.GetTopProductsByRegion)
+	analytics.GET("/top-products/trending", analyze, ValidateQueryMiddleware(trendingRules), conditional, deps.analytics.GetTrendingProducts)
+	analytics.GET("/analytics/forecast", analyze, ValidateQueryMiddleware(forecastRules), conditional, deps.analytics.GetSalesForecast)
+	analytics.GET("/analytics/customers/new-vs-returning", analyze, ValidateQueryMiddleware(customerSegmentRules), conditional, deps.analytics.GetNewVsReturningCustomers)
+	analytics.GET("/analytics/pivot", analyze, ValidateQueryMiddleware(pivotRules), conditional, deps.analytics.GetPivotTable)
+	analytics.GET("/analytics/distribution", analyze, ValidateQueryMiddleware(distributionRules), conditional, deps.analytics.GetDistributionStatistics)
+
+	entities.GET("/products", read, ValidateQueryMiddleware(productListRules), deps.entities.ListProducts)
+	entities.POST("/products", ingest, deps.entities.CreateProduct)
+	entities.GET("/products/:id", read, deps.entities.GetProduct)
+	entities.PUT("/products/:id", ingest, deps.entities.UpdateProduct)
+	entities.DELETE("/products/:id", ingest, deps.entities.DeleteProduct)
+
+	entities.GET("/customers", read, ValidateQueryMiddleware(customerListRules), deps.entities.ListCustomers)
+	entities.POST("/customers", ingest, deps.entities.CreateCustomer)
+	entities.GET("/customers/:id", read, deps.entities.GetCustomer)
+	entities.PUT("/customers/:id", ingest, deps.entities.UpdateCustomer)
+	entities.DELETE("/customers/:id", ingest, deps.entities.DeleteCustomer)
+
+	entities.GET("/orders", read, ValidateQueryMiddleware(orderListRules), deps.entities.ListOrders)
+	entities.POST("/orders", ingest, deps.entities.CreateOrder)
+	entities.GET("/orders/:id", read, deps.entities.GetOrder)
+	entities.PUT("/orders/:id", ingest, deps.entities.UpdateOrder)
+	entities.DELETE("/orders/:id", ingest, deps.entities.DeleteOrder)
+	entities.GET("/orders/:id/items", read, deps.entities.ListOrderItems)
+	entities.POST("/orders/:id/items", ingest, deps.entities.CreateOrderItem)
+	entities.PUT("/orders/:id/items/:item_id", ingest, deps.entities.UpdateOrderItem)
+	entities.DELETE("/orders/:id/items/:item_id", ingest, deps.entities.DeleteOrderItem)
+
+	admins.GET("/admin/config", admin, deps.admin.GetConfig)
 }
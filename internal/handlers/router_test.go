@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sales/internal/auth"
+	"sales/internal/config"
+	"sales/internal/constants"
+	"sales/internal/repository"
+	"sales/internal/repository/memory"
+	"sales/internal/services"
+	"sales/internal/tenant"
+	"sales/pkg/cache"
+	"sales/pkg/ratelimit"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testKeys are the API keys accepted by test routers, named after their role.
+var testKeys = map[string]string{
+	constants.RoleViewer:   "viewer-key",
+	constants.RoleAnalyst:  "analyst-key",
+	constants.RoleIngestor: "ingestor-key",
+	constants.RoleAdmin:    "admin-key",
+}
+
+// testCSVFile is the sample sales export the default tenant of test routers refreshes from.
+const testCSVFile = "../../data/sales_data.csv"
+
+// newTestRouter serves store with SetupRoutes behind testKeys, for the default tenant and without rate limits.
+func newTestRouter(t *testing.T, store repository.Store) *gin.Engine {
+	t.Helper()
+	return newLimitedRouter(t, store, nil)
+}
+
+// newLimitedRouter is newTestRouter with the rate limits of the classes in rateLimits; the other
+// classes are unlimited.
+func newLimitedRouter(t *testing.T, store repository.Store, rateLimits map[string]ratelimit.Limit) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	var apiKeys []auth.APIKey
+	for role, key := range testKeys {
+		hash := sha256.Sum256([]byte(key))
+		apiKeys = append(apiKeys, auth.APIKey{Name: role, SHA256: hex.EncodeToString(hash[:]), Roles: []string{role}})
+	}
+	authenticator, err := auth.NewAuthenticator(apiKeys, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tenants, err := tenant.NewRegistry([]tenant.Tenant{{ID: constants.DefaultTenant}}, tenant.Tenant{CSVFile: testCSVFile, Schedule: constants.DefaultCronSchedule})
+	if err != nil {
+		t.Fatal(err)
+	}
+	limits := make(map[string]ratelimit.Limit, len(DefaultRateLimits))
+	for class := range DefaultRateLimits {
+		limits[class] = rateLimits[class]
+	}
+
+	router := gin.New()
+	svc := services.New(store, services.NewResultCache(cache.NewLRU(constants.CacheEntries, constants.CacheTTL)))
+	if err := SetupRoutes(router, config.Default(), svc, authenticator, tenants, limits); err != nil {
+		t.Fatal(err)
+	}
+	return router
+}
+
+// newLoadedRouter returns a test router over a memory store refreshed from testCSVFile.
+func newLoadedRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	router := newTestRouter(t, memory.NewStore())
+	if w := serve(router, http.MethodPost, "/v1/refresh", constants.RoleIngestor, nil); w.Code != http.StatusOK {
+		t.Fatalf("refresh: %d %s", w.Code, w.Body)
+	}
+	return router
+}
+
+// newRequest builds a request with the key of role, or without credentials when role is empty, and
+// a JSON encoding of body when it isn't nil.
+func newRequest(method, target, role string, body any) *http.Request {
+	var reader io.Reader
+	if body != nil {
+		encoded, _ := json.Marshal(body)
+		reader = bytes.NewReader(encoded)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	if role != "" {
+		req.Header.Set(APIKeyHeader, testKeys[role])
+	}
+	return req
+}
+
+// record serves req and returns the recorded response.
+func record(router http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// serve sends a request built by newRequest.
+func serve(router http.Handler, method, target, role string, body any) *httptest.ResponseRecorder {
+	return record(router, newRequest(method, target, role, body))
+}
+
+// decode unmarshals a JSON response body, failing the test when it doesn't decode.
+func decode[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decoding %q: %v", w.Body, err)
+	}
+	return v
+}
+
+// TestRoutesAreDocumented expects every registered route to have an entry in routeDocs and every
+// entry to document a registered route.
+func TestRoutesAreDocumented(t *testing.T) {
+	router := newTestRouter(t, memory.NewStore())
+
+	documented := map[string]bool{}
+	for _, route := range router.Routes() {
+		version, path := splitVersion(route.Path)
+		doc, ok := routeDocs[route.Method+" "+path]
+		if !ok {
+			t.Errorf("%s %s has no entry in routeDocs", route.Method, route.Path)
+			continue
+		}
+		if doc.Root && version != "" {
+			t.Errorf("%s %s is documented as a root route but served under %s", route.Method, route.Path, version)
+		}
+		documented[route.Method+" "+path] = true
+	}
+	for key := range routeDocs {
+		if !documented[key] {
+			t.Errorf("routeDocs entry %q documents no registered route", key)
+		}
+	}
+}
+
+// TestBuildOpenAPISpecRejectsUndocumentedRoutes expects SetupRoutes' check to fail on a route
+// missing from routeDocs instead of serving it undocumented.
+func TestBuildOpenAPISpecRejectsUndocumentedRoutes(t *testing.T) {
+	routes := gin.RoutesInfo{{Method: "GET", Path: "/" + constants.APIVersionV1 + "/undocumented"}}
+	if _, err := BuildOpenAPISpec(routes); err == nil {
+		t.Fatal("BuildOpenAPISpec accepted an undocumented route")
+	}
+}
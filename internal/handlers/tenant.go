@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"sales/internal/auth"
+	"sales/internal/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TenantHeader selects the tenant of a request made with credentials not bound to one.
+const TenantHeader = "X-Tenant-ID"
+
+// TenantMiddleware resolves the tenant of the request from the caller's credentials and the
+// X-Tenant-ID header and stores it in the request context. It must run after AuthMiddleware.
+func TenantMiddleware(tenants *tenant.Registry) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, _ := ctx.Value(PrincipalKey).(auth.Principal)
+		t, err := tenants.Resolve(principal.Tenant, ctx.GetHeader(TenantHeader))
+		if err != nil {
+			_ = ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+		ctx.Request = ctx.Request.WithContext(tenant.NewContext(ctx.Request.Context(), t))
+		ctx.Next()
+	}
+}
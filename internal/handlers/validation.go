@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"math"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryKey is the gin context key holding the utils.Query validated by ValidateQueryMiddleware.
+const QueryKey = "query"
+
+// ValidateQueryMiddleware rejects requests whose query string violates the rules before the handler
+// runs, and stores the parsed query for the handler to read.
+func ValidateQueryMiddleware(rules utils.QueryRules) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		query, err := utils.ValidateQuery(ctx.Request.URL.Query(), rules)
+		if err != nil {
+			_ = ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+		ctx.Set(QueryKey, query)
+		ctx.Next()
+	}
+}
+
+// validatedQuery returns the query parsed by ValidateQueryMiddleware.
+func validatedQuery(ctx *gin.Context) utils.Query {
+	return ctx.MustGet(QueryKey).(utils.Query)
+}
+
+// query parameter rules shared by several endpoints
+var (
+	startDateRule = utils.QueryRule{Required: true, Validate: utils.DateRule(constants.ErrInvalidStartDate)}
+	endDateRule   = utils.QueryRule{Required: true, Validate: utils.DateRule(constants.ErrInvalidEndDate)}
+	limitRule     = utils.QueryRule{Required: true, Validate: func(v string) error { _, err := utils.ParseLimit(v); return err }}
+	freeTextRule  = utils.QueryRule{}
+	timezoneRule  = utils.QueryRule{Validate: func(v string) error { _, err := utils.ParseTimezone(v); return err }}
+)
+
+// salesFilterRules validates the optional filters and export format accepted by every analytics endpoint.
+var salesFilterRules = map[string]utils.QueryRule{
+	constants.Format:        formatRule,
+	constants.Category:      {Multi: true},
+	constants.Region:        {Multi: true},
+	constants.PaymentMethod: {Multi: true},
+	constants.CustomerID:    {Multi: true},
+	constants.MinPrice:      {Validate: utils.PriceRule(constants.ErrInvalidMinPrice)},
+	constants.MaxPrice:      {Validate: utils.PriceRule(constants.ErrInvalidMaxPrice)},
+}
+
+// withSalesFilterRules adds the sales filter and format rules to an endpoint's own rules.
+func withSalesFilterRules(params map[string]utils.QueryRule) map[string]utils.QueryRule {
+	merged := make(map[string]utils.QueryRule, len(params)+len(salesFilterRules))
+	for name, rule := range salesFilterRules {
+		merged[name] = rule
+	}
+	for name, rule := range params {
+		merged[name] = rule
+	}
+	return merged
+}
+
+// topProductsRules validates the top-N product endpoints.
+var topProductsRules = utils.QueryRules{
+	Params: withSalesFilterRules(map[string]utils.QueryRule{
+		constants.Limit:     limitRule,
+		constants.StartDate: startDateRule,
+		constants.EndDate:   endDateRule,
+		constants.Timezone:  timezoneRule,
+	}),
+	MaxRangeDays: constants.MaxDateRangeDays,
+}
+
+// trendingRules validates the trending products endpoint.
+var trendingRules = utils.QueryRules{
+	Params: withSalesFilterRules(map[string]utils.QueryRule{
+		constants.Limit:     limitRule,
+		constants.StartDate: startDateRule,
+		constants.EndDate:   endDateRule,
+		constants.Timezone:  timezoneRule,
+		constants.MinVolume: {Default: strconv.Itoa(constants.DefaultTrendingMinVolume), Validate: utils.IntRangeRule(constants.ErrInvalidMinVolume, 0, math.MaxInt)},
+	}),
+	MaxRangeDays: constants.MaxDateRangeDays,
+}
+
+// forecastRules validates the forecast endpoint; its history range is optional and unbounded.
+var forecastRules = utils.QueryRules{
+	Params: withSalesFilterRules(map[string]utils.QueryRule{
+		constants.ProductID: freeTextRule,
+		constants.Horizon:   {Default: strconv.Itoa(constants.DefaultForecastHorizon), Validate: utils.IntRangeRule(constants.ErrInvalidHorizon, 1, constants.MaxForecastHorizon)},
+		constants.Method:    utils.MethodRule,
+		constants.StartDate: {Validate: startDateRule.Validate},
+		constants.EndDate:   {Validate: endDateRule.Validate},
+		constants.Timezone:  timezoneRule,
+	}),
+	Check: func(query utils.Query) models.ValidationErrors {
+		if query.Get(constants.ProductID) == "" && len(query.Filter.Categories) == 0 {
+			return models.ValidationErrors{{Field: constants.ProductID, Message: constants.ErrMissingTarget.Error()}}
+		}
+		return nil
+	},
+}
+
+// customerSegmentRules validates the new vs returning customers endpoint.
+var customerSegmentRules = utils.QueryRules{
+	Params: withSalesFilterRules(map[string]utils.QueryRule{
+		constants.StartDate: startDateRule,
+		constants.EndDate:   endDateRule,
+		constants.Timezone:  timezoneRule,
+		constants.Interval:  utils.IntervalRule,
+	}),
+	MaxRangeDays: constants.MaxDateRangeDays,
+}
+
+// pivotRules validates the pivot table endpoint.
+var pivotRules = utils.QueryRules{
+	Params: withSalesFilterRules(map[string]utils.QueryRule{
+		constants.StartDate: startDateRule,
+		constants.EndDate:   endDateRule,
+		constants.Timezone:  timezoneRule,
+		constants.Rows:      utils.PivotRowsRule,
+		constants.Columns:   utils.PivotColumnsRule,
+		constants.Metric:    utils.MetricRule,
+		constants.Percent:   utils.PercentRule,
+	}),
+	MaxRangeDays: constants.MaxDateRangeDays,
+	Check:        utils.CheckPivotDimensions,
+}
+
+// distributionRules validates the distribution statistics endpoint.
+var distributionRules = utils.QueryRules{
+	Params: withSalesFilterRules(map[string]utils.QueryRule{
+		constants.StartDate: startDateRule,
+		constants.EndDate:   endDateRule,
+		constants.Timezone:  timezoneRule,
+		constants.GroupBy:   utils.GroupByRule,
+		constants.Buckets:   {Default: strconv.Itoa(constants.DefaultHistogramBuckets), Validate: utils.IntRangeRule(constants.ErrInvalidBuckets, 1, constants.MaxHistogramBuckets)},
+	}),
+	MaxRangeDays: constants.MaxDateRangeDays,
+}
@@ -1,15 +1,24 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"sales/pkg/stats"
+	"strings"
+	"time"
+)
 
+// Product, Customer, Order and OrderItem belong to the tenant in TenantID, which is set and
+// filtered on by the tenant package and never exposed through the API.
 type Product struct {
-	ProductID   string  `gorm:"primaryKey;type:TEXT;column:product_id"`
-	ProductName string  `gorm:"type:TEXT"`
-	Category    string  `gorm:"type:TEXT"`
-	UnitPrice   float64 `gorm:"type:REAL"`
+	TenantID    string `gorm:"primaryKey;type:TEXT;column:tenant_id" json:"-"`
+	ProductID   string `gorm:"primaryKey;type:TEXT;column:product_id"`
+	ProductName string `gorm:"type:TEXT"`
+	Category    string `gorm:"type:TEXT"`
+	UnitPrice   float64
 }
 
 type Customer struct {
+	TenantID        string `gorm:"primaryKey;type:TEXT;column:tenant_id" json:"-"`
 	CustomerID      string `gorm:"primaryKey;type:TEXT;column:customer_id"`
 	CustomerName    string `gorm:"type:TEXT"`
 	CustomerEmail   string `gorm:"type:TEXT"`
@@ -17,25 +26,117 @@ type Customer struct {
 }
 
 type Order struct {
+	TenantID      string    `gorm:"primaryKey;type:TEXT;column:tenant_id" json:"-"`
 	OrderID       string    `gorm:"primaryKey;type:TEXT;column:order_id"`
 	CustomerID    string    `gorm:"index;type:TEXT;column:customer_id"`
-	DateOfSale    time.Time `gorm:"type:TEXT"`
-	ShippingCost  float64   `gorm:"type:REAL"`
-	PaymentMethod string    `gorm:"type:TEXT"`
-	Region        string    `gorm:"type:TEXT;column:region"`
-	Customer      Customer  `gorm:"foreignKey:CustomerID;references:CustomerID"`
+	DateOfSale    time.Time `gorm:"type:TEXT;serializer:timestamp"`
+	ShippingCost  float64
+	PaymentMethod string   `gorm:"type:TEXT"`
+	Region        string   `gorm:"type:TEXT;column:region"`
+	Customer      Customer `gorm:"foreignKey:CustomerID;references:CustomerID"`
 }
 
 type OrderItem struct {
-	OrderItemID  uint    `gorm:"primaryKey;autoIncrement;type:INTEGER;column:order_item_id"`
-	OrderID      string  `gorm:"index;type:TEXT;column:order_id"`
-	ProductID    string  `gorm:"index;type:TEXT;column:product_id"`
-	QuantitySold int     `gorm:"type:INTEGER"`
-	Discount     float64 `gorm:"type:REAL"`
-	Order        Order   `gorm:"foreignKey:OrderID;references:OrderID"`
+	OrderItemID  uint   `gorm:"primaryKey;autoIncrement;column:order_item_id"`
+	TenantID     string `gorm:"index;type:TEXT;column:tenant_id" json:"-"`
+	OrderID      string `gorm:"index;type:TEXT;column:order_id"`
+	ProductID    string `gorm:"index;type:TEXT;column:product_id"`
+	QuantitySold int    `gorm:"type:INTEGER"`
+	Discount     float64
+	Order        Order   `gorm:"foreignKey:OrderID;references:OrderID" json:"-"`
 	Product      Product `gorm:"foreignKey:ProductID;references:ProductID"`
 }
 
+// DailyProductSales is a row of the daily_product_sales rollup: the order items of a tenant summed
+// per UTC sale date, product, region, category and payment method. The repository rebuilds the
+// rows of every date a write touches, so the rollup always agrees with the raw tables.
+type DailyProductSales struct {
+	TenantID      string  `gorm:"primaryKey;type:TEXT;column:tenant_id" json:"-"`
+	SaleDate      string  `gorm:"primaryKey;type:TEXT;column:sale_date"`
+	ProductID     string  `gorm:"primaryKey;type:TEXT;column:product_id;index"`
+	Region        string  `gorm:"primaryKey;type:TEXT;column:region"`
+	Category      string  `gorm:"primaryKey;type:TEXT;column:category"`
+	PaymentMethod string  `gorm:"primaryKey;type:TEXT;column:payment_method"`
+	Quantity      int     `gorm:"type:INTEGER;column:quantity"`
+	GrossRevenue  float64 `gorm:"column:gross_revenue"` // before discounts
+	NetRevenue    float64 `gorm:"column:net_revenue"`   // after discounts, like analytics revenue
+	Orders        int     `gorm:"type:INTEGER;column:orders"`
+}
+
+func (DailyProductSales) TableName() string {
+	return "daily_product_sales"
+}
+
+// DateRange is the half-open interval [From, To) of sale instants in Location.
+// A zero From or To leaves that side of the range open.
+type DateRange struct {
+	From     time.Time
+	To       time.Time
+	Location *time.Location
+}
+
+// ZoneOffset is the UTC offset in minutes of a range's Location from an instant on.
+type ZoneOffset struct {
+	From    time.Time
+	Minutes int
+}
+
+// ZoneOffsets returns the UTC offsets of Location in effect across the range in order, one more for
+// every daylight saving transition inside it, used for day bucketing. The first offset, with a zero
+// From, applies to every instant before the second. An open range uses the offset at its bounded side.
+func (r DateRange) ZoneOffsets() []ZoneOffset {
+	if r.Location == nil {
+		return []ZoneOffset{{}}
+	}
+	at := r.From
+	if at.IsZero() {
+		at = r.To
+	}
+	_, offset := at.In(r.Location).Zone()
+	offsets := []ZoneOffset{{Minutes: offset / 60}}
+	if r.From.IsZero() || r.To.IsZero() {
+		return offsets
+	}
+	for {
+		_, end := at.In(r.Location).ZoneBounds()
+		if end.IsZero() || !end.Before(r.To) {
+			return offsets
+		}
+		if _, offset = end.In(r.Location).Zone(); offset/60 != offsets[len(offsets)-1].Minutes {
+			offsets = append(offsets, ZoneOffset{From: end, Minutes: offset / 60})
+		}
+		at = end
+	}
+}
+
+// OnUTCDays reports whether the range buckets sales on UTC days, i.e. its Location is at UTC throughout.
+func (r DateRange) OnUTCDays() bool {
+	offsets := r.ZoneOffsets()
+	return len(offsets) == 1 && offsets[0].Minutes == 0
+}
+
+// String formats the range for logging.
+func (r DateRange) String() string {
+	return "[" + formatBound(r.From) + ", " + formatBound(r.To) + ")"
+}
+
+func formatBound(t time.Time) string {
+	if t.IsZero() {
+		return "open"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// SalesFilter narrows analytics to matching order items; empty fields don't filter.
+type SalesFilter struct {
+	Categories     []string
+	Regions        []string
+	PaymentMethods []string
+	CustomerIDs    []string
+	MinPrice       *float64
+	MaxPrice       *float64
+}
+
 type ProductResult struct {
 	Category     string
 	ProductID    string  `gorm:"column:product_id"`
@@ -45,6 +146,31 @@ type ProductResult struct {
 	Region       string  `gorm:"column:region"`
 }
 
+type APIError struct {
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Field     string       `json:"field,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Details   []FieldError `json:"details,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors collects every invalid field of a request.
+type ValidationErrors []FieldError
+
+// Error returns the violations joined into a single message.
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		messages = append(messages, fieldErr.Field+": "+fieldErr.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
 type CustomError struct {
 	Prefix  string
 	Message string
@@ -54,3 +180,210 @@ type CustomError struct {
 func (e *CustomError) Error() string {
 	return e.Prefix + ": " + e.Message
 }
+
+type SalesBucket struct {
+	Period       string  `gorm:"column:period"`
+	QuantitySold int     `gorm:"column:quantity_sold"`
+	Revenue      float64 `gorm:"column:revenue"`
+}
+
+type ProductSalesTotal struct {
+	ProductID    string  `gorm:"column:product_id"`
+	QuantitySold int     `gorm:"column:quantity_sold"`
+	Revenue      float64 `gorm:"column:revenue"`
+	Orders       int     `gorm:"column:orders"`
+}
+
+type ForecastPoint struct {
+	PeriodStart string
+	Quantity    float64
+	Lower       float64
+	Upper       float64
+}
+
+type BacktestMetrics struct {
+	MAE  float64
+	MAPE *float64
+}
+
+type MethodForecast struct {
+	Method   string
+	Forecast []ForecastPoint
+	Backtest *BacktestMetrics
+	Error    string `json:",omitempty"`
+}
+
+type ForecastResult struct {
+	ProductID  string   `json:",omitempty"`
+	Categories []string `json:",omitempty"`
+	Interval   string
+	Horizon    int
+	History    []SalesBucket
+	Methods    []MethodForecast
+}
+
+type CustomerSegmentRow struct {
+	Period    string  `gorm:"column:period"`
+	Segment   string  `gorm:"column:segment"`
+	Customers int     `gorm:"column:customers"`
+	Orders    int     `gorm:"column:orders"`
+	Revenue   float64 `gorm:"column:revenue"`
+}
+
+type CustomerRetentionPeriod struct {
+	Period             string
+	NewCustomers       int
+	ReturningCustomers int
+	NewOrders          int
+	ReturningOrders    int
+	NewRevenue         float64
+	ReturningRevenue   float64
+	RepeatPurchaseRate float64
+}
+
+type ProductTrendResult struct {
+	ProductID        string  `gorm:"column:product_id"`
+	ProductName      string  `gorm:"column:product_name"`
+	Category         string  `gorm:"column:category"`
+	UnitPrice        float64 `gorm:"column:unit_price"`
+	RecentQuantity   int     `gorm:"column:recent_quantity"`
+	BaselineQuantity int     `gorm:"column:baseline_quantity"`
+}
+
+type ProductTrend struct {
+	Product
+	RecentQuantity   int
+	BaselineQuantity int
+	GrowthRate       *float64 // nil when the product had no baseline sales
+}
+
+type TrendingProducts struct {
+	BaselineStart string
+	BaselineEnd   string
+	RecentStart   string
+	RecentEnd     string
+	MinVolume     int
+	Trending      []ProductTrend
+	Decliners     []ProductTrend
+}
+
+type PivotCell struct {
+	RowKey      string  `gorm:"column:row_key"`
+	ColumnKey   string  `gorm:"column:column_key"`
+	RowLabel    string  `gorm:"column:row_label"`    // product name of a product row key
+	ColumnLabel string  `gorm:"column:column_label"` // product name of a product column key
+	Value       float64 `gorm:"column:value"`
+	RowTotal    bool    `gorm:"column:row_total"`    // aggregated across rows
+	ColumnTotal bool    `gorm:"column:column_total"` // aggregated across columns
+}
+
+type PivotRow struct {
+	Key    string
+	Label  string `json:",omitempty"` // product name when rows are products, keyed by id
+	Values []float64
+	Total  float64
+}
+
+type PivotTable struct {
+	RowDimension    string
+	ColumnDimension string
+	Metric          string
+	Percentage      string `json:",omitempty"`
+	Columns         []string
+	ColumnLabels    []string `json:",omitempty"` // product names when columns are products, keyed by id
+	Rows            []PivotRow
+	ColumnTotals    []float64
+	GrandTotal      float64
+}
+
+type GroupedValue struct {
+	GroupKey string  `gorm:"column:group_key"`
+	Value    float64 `gorm:"column:value"`
+}
+
+type DistributionGroup struct {
+	Key             string
+	OrderValue      stats.Summary
+	QuantityPerLine stats.Summary
+}
+
+type DistributionResult struct {
+	GroupBy string `json:",omitempty"`
+	Groups  []DistributionGroup
+}
+
+type SortField struct {
+	Field  string
+	Column string
+	Desc   bool
+}
+
+// PageRequest is a parsed pagination request; cursors and pages both resolve to an offset.
+type PageRequest struct {
+	Limit     int
+	Offset    int
+	Sort      []SortField
+	UseCursor bool
+}
+
+type PageMeta struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Page       int    `json:"page,omitempty"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Sort       string `json:"sort"`
+}
+
+type Page[T any] struct {
+	Data []T      `json:"data"`
+	Meta PageMeta `json:"meta"`
+}
+
+// OrderDetails is an order with its customer and line items.
+type OrderDetails struct {
+	Order
+	Items []OrderItem
+}
+
+// ProductInput is the request body for creating or replacing a product. Numbers may be sent as
+// JSON numbers or strings and are validated with the same rules as the CSV import.
+type ProductInput struct {
+	ProductID   string
+	ProductName string
+	Category    string
+	UnitPrice   json.Number
+}
+
+// CustomerInput is the request body for creating or replacing a customer.
+type CustomerInput struct {
+	CustomerID      string
+	CustomerName    string
+	CustomerEmail   string
+	CustomerAddress string
+}
+
+// OrderInput is the request body for creating or replacing an order. Items are only accepted on create.
+type OrderInput struct {
+	OrderID       string
+	CustomerID    string
+	DateOfSale    string
+	ShippingCost  json.Number
+	PaymentMethod string
+	Region        string
+	Items         []OrderItemInput
+}
+
+// OrderItemInput is the request body for creating or replacing an order line item.
+type OrderItemInput struct {
+	ProductID    string
+	QuantitySold json.Number
+	Discount     json.Number
+}
+
+// GraphQLRequest is the request body of the GraphQL endpoint.
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+// TimestampFormat is the UTC text layout sale instants are stored in; it sorts chronologically as text.
+const TimestampFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+func init() {
+	schema.RegisterSerializer("timestamp", TimestampSerializer{})
+}
+
+// TimestampSerializer stores time.Time fields as UTC text and parses them back, since the SQLite
+// driver only converts columns declared as DATE/DATETIME/TIMESTAMP.
+type TimestampSerializer struct{}
+
+// Scan implements serializer interface
+func (TimestampSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var t time.Time
+	switch v := dbValue.(type) {
+	case nil:
+	case time.Time:
+		t = v
+	case string:
+		parsed, err := parseTimestamp(v)
+		if err != nil {
+			return err
+		}
+		t = parsed
+	case []byte:
+		parsed, err := parseTimestamp(string(v))
+		if err != nil {
+			return err
+		}
+		t = parsed
+	default:
+		return fmt.Errorf("unsupported timestamp value %T", dbValue)
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(reflect.ValueOf(t.UTC()))
+	return nil
+}
+
+// Value implements serializer interface
+func (TimestampSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	t, ok := fieldValue.(time.Time)
+	if !ok {
+		return nil, fmt.Errorf("unsupported timestamp field %T", fieldValue)
+	}
+	return t.UTC().Format(TimestampFormat), nil
+}
+
+func parseTimestamp(value string) (time.Time, error) {
+	for _, layout := range []string{TimestampFormat, time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
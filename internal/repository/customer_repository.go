@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sales/internal/constants"
+	"sales/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// firstOrdersQuery derives each customer's first order date from all orders, regardless of filters.
+// It spans every tenant, so it is joined on tenant_id.
+const firstOrdersQuery = "(SELECT tenant_id, customer_id, MIN(date_of_sale) AS first_date FROM orders GROUP BY tenant_id, customer_id) AS first_orders"
+
+// GetCustomerSegmentsByPeriod retrieves customers, orders and revenue per period split into new and returning customers.
+// A customer counts as new in the period containing their first ever order and as returning in every later period.
+func (s *SQLStore) GetCustomerSegmentsByPeriod(ctx context.Context, interval string, dateRange models.DateRange, filter models.SalesFilter) ([]models.CustomerSegmentRow, error) {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing GetCustomerSegmentsByPeriod: interval=%s, dateRange=%s, filter=%+v", interval, dateRange, filter)
+	offsets := segmentBucketRange(dateRange).ZoneOffsets()
+	orderPeriod := periodExpr(db, interval, "orders.date_of_sale", offsets)
+	firstPeriod := periodExpr(db, interval, "first_orders.first_date", offsets)
+
+	var rows []models.CustomerSegmentRow
+	query := db.Model(&models.OrderItem{}).
+		Select(orderPeriod+" as period, "+
+			"CASE WHEN "+firstPeriod+" = "+orderPeriod+" THEN 'new' ELSE 'returning' END as segment, "+
+			"COUNT(DISTINCT orders.customer_id) as customers, COUNT(DISTINCT orders.order_id) as orders, "+
+			"SUM("+revenueExpr+") as revenue").
+		Joins(joinOrders).
+		Joins(joinProducts).
+		Joins("JOIN "+firstOrdersQuery+" ON orders.tenant_id = first_orders.tenant_id AND orders.customer_id = first_orders.customer_id").
+		Scopes(rawSales.inDateRange(dateRange), rawSales.withSalesFilter(filter))
+
+	query = query.Group("period, segment").
+		Order("period ASC, segment ASC").
+		Find(&rows)
+
+	if query.Error != nil {
+		log.Printf("Query failed: %v", query.Error)
+		return nil, query.Error
+	}
+
+	return rows, nil
+}
+
+// segmentBucketRange widens a range by a month and a week at the start. A first order before the range only
+// matters when it shares a period with an order inside it, so its offset must be exact that far back.
+func segmentBucketRange(dateRange models.DateRange) models.DateRange {
+	if !dateRange.From.IsZero() {
+		dateRange.From = dateRange.From.AddDate(0, -1, -7)
+	}
+	return dateRange
+}
+
+// ListCustomers retrieves a page of customers along with the total number of customers.
+func (s *SQLStore) ListCustomers(ctx context.Context, page models.PageRequest) ([]models.Customer, int64, error) {
+	db := s.db.WithContext(ctx)
+	var total int64
+	if err := db.Model(&models.Customer{}).Count(&total).Error; err != nil {
+		log.Printf("Query failed: %v", err)
+		return nil, 0, err
+	}
+
+	var customers []models.Customer
+	query := db.Model(&models.Customer{}).
+		Scopes(paginate(page, "customers.customer_id")).
+		Find(&customers)
+
+	if query.Error != nil {
+		log.Printf("Query failed: %v", query.Error)
+		return nil, 0, query.Error
+	}
+
+	return customers, total, nil
+}
+
+// GetCustomer retrieves a customer by id.
+func (s *SQLStore) GetCustomer(ctx context.Context, customerID string) (models.Customer, error) {
+	db := s.db.WithContext(ctx)
+	var customer models.Customer
+	err := db.Where("customer_id = ?", customerID).Take(&customer).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Customer{}, constants.ErrCustomerNotFound
+	}
+	if err != nil {
+		log.Printf("Query failed: %v", err)
+		return models.Customer{}, err
+	}
+	return customer, nil
+}
+
+// CreateCustomer inserts a customer, failing when the id is taken.
+func (s *SQLStore) CreateCustomer(ctx context.Context, customer models.Customer) error {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing CreateCustomer: customerID=%s", customer.CustomerID)
+	return db.Transaction(func(tx *gorm.DB) error {
+		taken, err := exists(tx, &models.Customer{}, "customer_id", customer.CustomerID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return constants.ErrCustomerExists
+		}
+		return tx.Create(&customer).Error
+	})
+}
+
+// UpdateCustomer replaces every field of an existing customer.
+func (s *SQLStore) UpdateCustomer(ctx context.Context, customer models.Customer) error {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing UpdateCustomer: customerID=%s", customer.CustomerID)
+	result := db.Model(&models.Customer{}).
+		Where("customer_id = ?", customer.CustomerID).
+		Select("*").
+		Updates(&customer)
+	if result.Error != nil {
+		log.Printf("Query failed: %v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return constants.ErrCustomerNotFound
+	}
+	return nil
+}
+
+// DeleteCustomer removes a customer without orders.
+func (s *SQLStore) DeleteCustomer(ctx context.Context, customerID string) error {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing DeleteCustomer: customerID=%s", customerID)
+	return db.Transaction(func(tx *gorm.DB) error {
+		inUse, err := exists(tx, &models.Order{}, "customer_id", customerID)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return constants.ErrCustomerInUse
+		}
+
+		result := tx.Where("customer_id = ?", customerID).Delete(&models.Customer{})
+		if result.Error != nil {
+			log.Printf("Query failed: %v", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return constants.ErrCustomerNotFound
+		}
+		return nil
+	})
+}
+
+// GetCustomersByIDs retrieves the customers with the given ids; unknown ids are skipped.
+func (s *SQLStore) GetCustomersByIDs(ctx context.Context, customerIDs []string) ([]models.Customer, error) {
+	db := s.db.WithContext(ctx)
+	var customers []models.Customer
+	if err := db.Where("customer_id IN ?", customerIDs).Find(&customers).Error; err != nil {
+		log.Printf("Query failed: %v", err)
+		return nil, err
+	}
+	return customers, nil
+}
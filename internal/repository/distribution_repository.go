@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"sales/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// GetOrderValues retrieves the net value of every order within a date range, split by an optional dimension.
+// When grouped by category an order contributes one value per category it contains.
+func (s *SQLStore) GetOrderValues(ctx context.Context, groupBy string, dateRange models.DateRange, filter models.SalesFilter) ([]models.GroupedValue, error) {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing GetOrderValues: groupBy=%s, dateRange=%s, filter=%+v", groupBy, dateRange, filter)
+	groupExpr := groupKeyExpr(db, groupBy, dateRange)
+
+	var values []models.GroupedValue
+	query := db.Model(&models.OrderItem{}).
+		Select(groupExpr+" as group_key, SUM("+revenueExpr+") as value").
+		Joins(joinOrders).
+		Joins(joinProducts).
+		Scopes(rawSales.inDateRange(dateRange), rawSales.withSalesFilter(filter)).
+		Group("group_key, orders.order_id").
+		Find(&values)
+
+	if query.Error != nil {
+		log.Printf("Query failed: %v", query.Error)
+		return nil, query.Error
+	}
+
+	return values, nil
+}
+
+// GetLineQuantities retrieves the quantity of every order item within a date range, split by an optional dimension.
+func (s *SQLStore) GetLineQuantities(ctx context.Context, groupBy string, dateRange models.DateRange, filter models.SalesFilter) ([]models.GroupedValue, error) {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing GetLineQuantities: groupBy=%s, dateRange=%s, filter=%+v", groupBy, dateRange, filter)
+	groupExpr := groupKeyExpr(db, groupBy, dateRange)
+
+	var values []models.GroupedValue
+	query := db.Model(&models.OrderItem{}).
+		Select(groupExpr+" as group_key, order_items.quantity_sold as value").
+		Joins(joinOrders).
+		Joins(joinProducts).
+		Scopes(rawSales.inDateRange(dateRange), rawSales.withSalesFilter(filter)).
+		Find(&values)
+
+	if query.Error != nil {
+		log.Printf("Query failed: %v", query.Error)
+		return nil, query.Error
+	}
+
+	return values, nil
+}
+
+// groupKeyExpr resolves a whitelisted dimension to its column, or a single "all" group when empty.
+func groupKeyExpr(db *gorm.DB, groupBy string, dateRange models.DateRange) string {
+	if groupBy == "" {
+		return "'all'"
+	}
+	return dimensionExpr(db, rawSales, groupBy, dateRange)
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"sales/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ImportSales stores the sales of a CSV import in one transaction: customers and products are
+// created unless they exist, orders and their items are inserted, and the rollup of every sale date
+// imported is refreshed.
+func (s *SQLStore) ImportSales(ctx context.Context, customers []models.Customer, products []models.Product, orders []models.Order, items []models.OrderItem) error {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing ImportSales: customers=%d, products=%d, orders=%d, items=%d", len(customers), len(products), len(orders), len(items))
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, customer := range customers {
+			if err := tx.FirstOrCreate(&customer, models.Customer{CustomerID: customer.CustomerID}).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, product := range products {
+			if err := tx.FirstOrCreate(&product, models.Product{ProductID: product.ProductID}).Error; err != nil {
+				return err
+			}
+		}
+
+		// Create orders and order items, noting the sale dates whose rollup changes
+		var saleDates []string
+		seen := make(map[string]bool)
+		for _, order := range orders {
+			if date := saleDate(order.DateOfSale); !seen[date] {
+				seen[date] = true
+				saleDates = append(saleDates, date)
+			}
+
+			if err := tx.Create(&order).Error; err != nil {
+				return err
+			}
+
+			// Find associated order item
+			for _, item := range items {
+				if item.OrderID == order.OrderID {
+					if err := tx.Create(&item).Error; err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		return refreshDailySales(tx, saleDates)
+	})
+}
@@ -0,0 +1,395 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"slices"
+	"time"
+)
+
+// sale is an order item joined with its order and product, the row analytics aggregate.
+type sale struct {
+	item    models.OrderItem
+	order   models.Order
+	product models.Product
+}
+
+// revenue is the net revenue of the item after discount, excluding shipping.
+func (s sale) revenue() float64 {
+	return float64(s.item.QuantitySold) * s.product.UnitPrice * (1 - s.item.Discount)
+}
+
+// sales returns the sales within the half-open range that match the filter, by item id.
+func (data *tenantData) sales(dateRange models.DateRange, filter models.SalesFilter) []sale {
+	var sales []sale
+	for _, item := range sortedValues(data.items) {
+		order, ok := data.orders[item.OrderID]
+		if !ok {
+			continue
+		}
+		product, ok := data.products[item.ProductID]
+		if !ok {
+			continue
+		}
+		s := sale{item: item, order: order, product: product}
+		if inDateRange(s.order.DateOfSale, dateRange) && matches(s, filter) {
+			sales = append(sales, s)
+		}
+	}
+	return sales
+}
+
+func inDateRange(t time.Time, dateRange models.DateRange) bool {
+	return (dateRange.From.IsZero() || !t.Before(dateRange.From)) && (dateRange.To.IsZero() || t.Before(dateRange.To))
+}
+
+// matches applies the optional analytics filters.
+func matches(s sale, filter models.SalesFilter) bool {
+	switch {
+	case len(filter.Categories) > 0 && !slices.Contains(filter.Categories, s.product.Category):
+		return false
+	case len(filter.Regions) > 0 && !slices.Contains(filter.Regions, s.order.Region):
+		return false
+	case len(filter.PaymentMethods) > 0 && !slices.Contains(filter.PaymentMethods, s.order.PaymentMethod):
+		return false
+	case len(filter.CustomerIDs) > 0 && !slices.Contains(filter.CustomerIDs, s.order.CustomerID):
+		return false
+	case filter.MinPrice != nil && s.product.UnitPrice < *filter.MinPrice:
+		return false
+	case filter.MaxPrice != nil && s.product.UnitPrice > *filter.MaxPrice:
+		return false
+	}
+	return true
+}
+
+// period buckets a sale instant to the first day of its interval (YYYY-MM-DD) in the caller's timezone,
+// like the SQL store. Weeks start on Monday.
+func period(interval string, t time.Time, location *time.Location) string {
+	if location == nil {
+		location = time.UTC
+	}
+	year, month, day := t.In(location).Date()
+	t = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case constants.IntervalDay:
+	case constants.IntervalMonth:
+		t = t.AddDate(0, 0, 1-t.Day())
+	default:
+		t = t.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+	}
+	return t.Format(constants.DateFormat)
+}
+
+// dimension resolves a whitelisted dimension of a sale; months follow the range's timezone.
+func dimension(s sale, name string, dateRange models.DateRange) string {
+	switch name {
+	case constants.DimensionMonth:
+		return period(constants.IntervalMonth, s.order.DateOfSale, dateRange.Location)
+	case constants.DimensionRegion:
+		return s.order.Region
+	case constants.DimensionCategory:
+		return s.product.Category
+	case constants.DimensionPaymentMethod:
+		return s.order.PaymentMethod
+	default: // constants.DimensionProduct
+		return s.product.ProductID
+	}
+}
+
+// dimensionLabel is the display name of a sale's dimension key, empty when the key is its own name.
+func dimensionLabel(s sale, name string) string {
+	if name == constants.DimensionProduct {
+		return s.product.ProductName
+	}
+	return ""
+}
+
+// productQuantity is the quantity sold of a product within a group.
+type productQuantity struct {
+	group    string
+	product  models.Product
+	quantity int
+}
+
+// quantitiesByProduct sums the quantity sold per group and product, ordered by group, then by
+// quantity descending, then by product id.
+func quantitiesByProduct(sales []sale, group func(sale) string) []productQuantity {
+	index := map[[2]string]int{}
+	var quantities []productQuantity
+	for _, s := range sales {
+		key := [2]string{group(s), s.product.ProductID}
+		i, ok := index[key]
+		if !ok {
+			i = len(quantities)
+			index[key] = i
+			quantities = append(quantities, productQuantity{group: key[0], product: s.product})
+		}
+		quantities[i].quantity += s.item.QuantitySold
+	}
+	slices.SortFunc(quantities, func(a, b productQuantity) int {
+		return cmp.Or(cmp.Compare(a.group, b.group), cmp.Compare(b.quantity, a.quantity), cmp.Compare(a.product.ProductID, b.product.ProductID))
+	})
+	return quantities
+}
+
+// topProductsBy groups the top n products of every group.
+func topProductsBy(sales []sale, n int, group func(sale) string) map[string][]models.Product {
+	top := map[string][]models.Product{}
+	for _, quantity := range quantitiesByProduct(sales, group) {
+		if len(top[quantity.group]) < n {
+			top[quantity.group] = append(top[quantity.group], quantity.product)
+		}
+	}
+	return top
+}
+
+func (s *Store) GetTopProductsOverall(ctx context.Context, n int, dateRange models.DateRange, filter models.SalesFilter) ([]models.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	top := topProductsBy(data.sales(dateRange, filter), n, func(sale) string { return "" })[""]
+	if top == nil {
+		return []models.Product{}, nil
+	}
+	return top, nil
+}
+
+func (s *Store) GetTopProductsByCategory(ctx context.Context, n int, dateRange models.DateRange, filter models.SalesFilter) (map[string][]models.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return topProductsBy(data.sales(dateRange, filter), n, func(line sale) string { return line.product.Category }), nil
+}
+
+func (s *Store) GetTopProductsByRegion(ctx context.Context, n int, dateRange models.DateRange, filter models.SalesFilter) (map[string][]models.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return topProductsBy(data.sales(dateRange, filter), n, func(line sale) string { return line.order.Region }), nil
+}
+
+func (s *Store) GetProductSalesComparison(ctx context.Context, baseline models.DateRange, recent models.DateRange, filter models.SalesFilter) ([]models.ProductTrendResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index := map[string]int{}
+	results := []models.ProductTrendResult{}
+	for _, line := range data.sales(models.DateRange{From: baseline.From, To: recent.To}, filter) {
+		i, ok := index[line.product.ProductID]
+		if !ok {
+			i = len(results)
+			index[line.product.ProductID] = i
+			results = append(results, models.ProductTrendResult{
+				ProductID:   line.product.ProductID,
+				ProductName: line.product.ProductName,
+				Category:    line.product.Category,
+				UnitPrice:   line.product.UnitPrice,
+			})
+		}
+		if inDateRange(line.order.DateOfSale, recent) {
+			results[i].RecentQuantity += line.item.QuantitySold
+		}
+		if inDateRange(line.order.DateOfSale, baseline) {
+			results[i].BaselineQuantity += line.item.QuantitySold
+		}
+	}
+	return results, nil
+}
+
+func (s *Store) GetSalesByPeriod(ctx context.Context, interval string, productID string, dateRange models.DateRange, filter models.SalesFilter) ([]models.SalesBucket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	buckets := map[string]models.SalesBucket{}
+	for _, line := range data.sales(dateRange, filter) {
+		if productID != "" && line.product.ProductID != productID {
+			continue
+		}
+		key := period(interval, line.order.DateOfSale, dateRange.Location)
+		bucket := buckets[key]
+		bucket.Period = key
+		bucket.QuantitySold += line.item.QuantitySold
+		bucket.Revenue += line.revenue()
+		buckets[key] = bucket
+	}
+	return sortedValues(buckets), nil
+}
+
+func (s *Store) GetProductSalesTotals(ctx context.Context, productIDs []string, dateRange models.DateRange, filter models.SalesFilter) ([]models.ProductSalesTotal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	totals := map[string]models.ProductSalesTotal{}
+	orders := map[string]map[string]bool{}
+	for _, line := range data.sales(dateRange, filter) {
+		id := line.product.ProductID
+		if !slices.Contains(productIDs, id) {
+			continue
+		}
+		total := totals[id]
+		total.ProductID = id
+		total.QuantitySold += line.item.QuantitySold
+		total.Revenue += line.revenue()
+		if orders[id] == nil {
+			orders[id] = map[string]bool{}
+		}
+		orders[id][line.order.OrderID] = true
+		total.Orders = len(orders[id])
+		totals[id] = total
+	}
+	return sortedValues(totals), nil
+}
+
+func (s *Store) GetCustomerSegmentsByPeriod(ctx context.Context, interval string, dateRange models.DateRange, filter models.SalesFilter) ([]models.CustomerSegmentRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// a customer's first order counts regardless of filters
+	firstOrders := map[string]time.Time{}
+	for _, order := range data.orders {
+		if first, ok := firstOrders[order.CustomerID]; !ok || order.DateOfSale.Before(first) {
+			firstOrders[order.CustomerID] = order.DateOfSale
+		}
+	}
+
+	rows := map[string]models.CustomerSegmentRow{}
+	customers := map[string]map[string]bool{}
+	orders := map[string]map[string]bool{}
+	for _, line := range data.sales(dateRange, filter) {
+		orderPeriod := period(interval, line.order.DateOfSale, dateRange.Location)
+		segment := "returning"
+		if period(interval, firstOrders[line.order.CustomerID], dateRange.Location) == orderPeriod {
+			segment = "new"
+		}
+		key := orderPeriod + " " + segment
+		if customers[key] == nil {
+			customers[key], orders[key] = map[string]bool{}, map[string]bool{}
+		}
+		customers[key][line.order.CustomerID] = true
+		orders[key][line.order.OrderID] = true
+
+		row := rows[key]
+		row.Period, row.Segment = orderPeriod, segment
+		row.Customers, row.Orders = len(customers[key]), len(orders[key])
+		row.Revenue += line.revenue()
+		rows[key] = row
+	}
+	return sortedValues(rows), nil
+}
+
+// GetPivotCells aggregates every combination of row and column value, each row and column across
+// the other axis, and the grand total, which is returned even without sales like in SQL.
+func (s *Store) GetPivotCells(ctx context.Context, rowDimension string, columnDimension string, metric string, dateRange models.DateRange, filter models.SalesFilter) ([]models.PivotCell, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	type aggregate struct {
+		cell   models.PivotCell
+		orders map[string]bool
+	}
+	aggregates := map[models.PivotCell]*aggregate{}
+	add := func(cell models.PivotCell, line sale) {
+		a, ok := aggregates[cell]
+		if !ok {
+			a = &aggregate{cell: cell, orders: map[string]bool{}}
+			aggregates[cell] = a
+		}
+		switch metric {
+		case constants.MetricRevenue:
+			a.cell.Value += line.revenue()
+		case constants.MetricOrders:
+			a.orders[line.order.OrderID] = true
+			a.cell.Value = float64(len(a.orders))
+		default: // constants.MetricQuantity
+			a.cell.Value += float64(line.item.QuantitySold)
+		}
+	}
+
+	grandTotal := models.PivotCell{RowTotal: true, ColumnTotal: true}
+	aggregates[grandTotal] = &aggregate{cell: grandTotal, orders: map[string]bool{}}
+	for _, line := range data.sales(dateRange, filter) {
+		row, column := dimension(line, rowDimension, dateRange), dimension(line, columnDimension, dateRange)
+		rowLabel, columnLabel := dimensionLabel(line, rowDimension), dimensionLabel(line, columnDimension)
+		add(models.PivotCell{RowKey: row, ColumnKey: column, RowLabel: rowLabel, ColumnLabel: columnLabel}, line)
+		add(models.PivotCell{RowKey: row, RowLabel: rowLabel, ColumnTotal: true}, line)
+		add(models.PivotCell{ColumnKey: column, ColumnLabel: columnLabel, RowTotal: true}, line)
+		add(grandTotal, line)
+	}
+
+	cells := make([]models.PivotCell, 0, len(aggregates))
+	for _, a := range aggregates {
+		cells = append(cells, a.cell)
+	}
+	return cells, nil
+}
+
+func (s *Store) GetOrderValues(ctx context.Context, groupBy string, dateRange models.DateRange, filter models.SalesFilter) ([]models.GroupedValue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// an order contributes one value per group it has items in
+	index := map[[2]string]int{}
+	values := []models.GroupedValue{}
+	for _, line := range data.sales(dateRange, filter) {
+		key := [2]string{groupKey(line, groupBy, dateRange), line.order.OrderID}
+		i, ok := index[key]
+		if !ok {
+			i = len(values)
+			index[key] = i
+			values = append(values, models.GroupedValue{GroupKey: key[0]})
+		}
+		values[i].Value += line.revenue()
+	}
+	return values, nil
+}
+
+func (s *Store) GetLineQuantities(ctx context.Context, groupBy string, dateRange models.DateRange, filter models.SalesFilter) ([]models.GroupedValue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values := []models.GroupedValue{}
+	for _, line := range data.sales(dateRange, filter) {
+		values = append(values, models.GroupedValue{GroupKey: groupKey(line, groupBy, dateRange), Value: float64(line.item.QuantitySold)})
+	}
+	return values, nil
+}
+
+// groupKey resolves a whitelisted dimension of a sale, or a single "all" group when empty.
+func groupKey(s sale, groupBy string, dateRange models.DateRange) string {
+	if groupBy == "" {
+		return "all"
+	}
+	return dimension(s, groupBy, dateRange)
+}
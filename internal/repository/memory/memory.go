@@ -0,0 +1,592 @@
+// Package memory implements the repositories in process memory. It answers like the SQL store,
+// raw tables only, so services and handlers can be exercised without a database, and serves the
+// application when SALES_DB_DRIVER is memory. Its data is lost on exit.
+package memory
+
+import (
+	"context"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"sales/internal/tenant"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store holds the data of every tenant. Writes check everything they need before changing
+// anything, so a failed write leaves the data as it was, like a rolled back transaction.
+type Store struct {
+	mu      sync.Mutex
+	tenants map[string]*tenantData
+	// lastItemID numbers order items across tenants, like the autoincrement column
+	lastItemID uint
+}
+
+var _ repository.Store = (*Store)(nil)
+
+// tenantData are the rows of one tenant. Orders and items are stored without their associations,
+// which are attached when read.
+type tenantData struct {
+	products  map[string]models.Product
+	customers map[string]models.Customer
+	orders    map[string]models.Order
+	items     map[uint]models.OrderItem
+}
+
+// NewStore returns an empty store.
+func NewStore() *Store {
+	return &Store{tenants: map[string]*tenantData{}}
+}
+
+// tenantData returns the data of the tenant in ctx, or tenant.ErrNoTenant. The caller holds mu.
+func (s *Store) tenantData(ctx context.Context) (*tenantData, error) {
+	t, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, tenant.ErrNoTenant
+	}
+	data, ok := s.tenants[t.ID]
+	if !ok {
+		data = &tenantData{
+			products:  map[string]models.Product{},
+			customers: map[string]models.Customer{},
+			orders:    map[string]models.Order{},
+			items:     map[uint]models.OrderItem{},
+		}
+		s.tenants[t.ID] = data
+	}
+	return data, nil
+}
+
+// sortFields returns the value of every sortable field of a row, keyed by API field name.
+type sortFields[T any] func(row T) map[string]any
+
+func productFields(p models.Product) map[string]any {
+	return map[string]any{"product_id": p.ProductID, "product_name": p.ProductName, "category": p.Category, "unit_price": p.UnitPrice}
+}
+
+func customerFields(c models.Customer) map[string]any {
+	return map[string]any{"customer_id": c.CustomerID, "customer_name": c.CustomerName, "customer_email": c.CustomerEmail}
+}
+
+func orderFields(o models.Order) map[string]any {
+	return map[string]any{"order_id": o.OrderID, "customer_id": o.CustomerID, "date_of_sale": o.DateOfSale, "region": o.Region,
+		"payment_method": o.PaymentMethod, "shipping_cost": o.ShippingCost}
+}
+
+// paginate orders rows by the requested fields, then by primaryKey, and returns the requested page.
+func paginate[T any](rows []T, page models.PageRequest, fields sortFields[T], primaryKey string) []T {
+	slices.SortStableFunc(rows, func(a, b T) int {
+		aFields, bFields := fields(a), fields(b)
+		for _, field := range page.Sort {
+			if c := compare(aFields[field.Field], bFields[field.Field]); c != 0 {
+				if field.Desc {
+					return -c
+				}
+				return c
+			}
+		}
+		return compare(aFields[primaryKey], bFields[primaryKey])
+	})
+	start := min(page.Offset, len(rows))
+	end := min(start+max(page.Limit, 0), len(rows))
+	return rows[start:end]
+}
+
+// compare orders two values of a sortable field.
+func compare(a, b any) int {
+	switch a := a.(type) {
+	case string:
+		return strings.Compare(a, b.(string))
+	case float64:
+		switch other := b.(float64); {
+		case a < other:
+			return -1
+		case a > other:
+			return 1
+		}
+		return 0
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	default:
+		return 0
+	}
+}
+
+// sortedValues returns the values of m ordered by key.
+func sortedValues[K string | uint, V any](m map[K]V) []V {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	values := make([]V, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, m[key])
+	}
+	return values
+}
+
+func (s *Store) ListProducts(ctx context.Context, page models.PageRequest) ([]models.Product, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	products := sortedValues(data.products)
+	return paginate(products, page, productFields, "product_id"), int64(len(products)), nil
+}
+
+func (s *Store) GetProduct(ctx context.Context, productID string) (models.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return models.Product{}, err
+	}
+	product, ok := data.products[productID]
+	if !ok {
+		return models.Product{}, constants.ErrProductNotFound
+	}
+	return product, nil
+}
+
+func (s *Store) CreateProduct(ctx context.Context, product models.Product) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return err
+	}
+	if _, taken := data.products[product.ProductID]; taken {
+		return constants.ErrProductExists
+	}
+	data.products[product.ProductID] = product
+	return nil
+}
+
+func (s *Store) UpdateProduct(ctx context.Context, product models.Product) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := data.products[product.ProductID]; !ok {
+		return constants.ErrProductNotFound
+	}
+	data.products[product.ProductID] = product
+	return nil
+}
+
+func (s *Store) DeleteProduct(ctx context.Context, productID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return err
+	}
+	for _, item := range data.items {
+		if item.ProductID == productID {
+			return constants.ErrProductInUse
+		}
+	}
+	if _, ok := data.products[productID]; !ok {
+		return constants.ErrProductNotFound
+	}
+	delete(data.products, productID)
+	return nil
+}
+
+func (s *Store) GetProductsByIDs(ctx context.Context, productIDs []string) ([]models.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	products := []models.Product{}
+	for _, product := range sortedValues(data.products) {
+		if slices.Contains(productIDs, product.ProductID) {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func (s *Store) ListCustomers(ctx context.Context, page models.PageRequest) ([]models.Customer, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	customers := sortedValues(data.customers)
+	return paginate(customers, page, customerFields, "customer_id"), int64(len(customers)), nil
+}
+
+func (s *Store) GetCustomer(ctx context.Context, customerID string) (models.Customer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return models.Customer{}, err
+	}
+	customer, ok := data.customers[customerID]
+	if !ok {
+		return models.Customer{}, constants.ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
+func (s *Store) CreateCustomer(ctx context.Context, customer models.Customer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return err
+	}
+	if _, taken := data.customers[customer.CustomerID]; taken {
+		return constants.ErrCustomerExists
+	}
+	data.customers[customer.CustomerID] = customer
+	return nil
+}
+
+func (s *Store) UpdateCustomer(ctx context.Context, customer models.Customer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := data.customers[customer.CustomerID]; !ok {
+		return constants.ErrCustomerNotFound
+	}
+	data.customers[customer.CustomerID] = customer
+	return nil
+}
+
+func (s *Store) DeleteCustomer(ctx context.Context, customerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return err
+	}
+	for _, order := range data.orders {
+		if order.CustomerID == customerID {
+			return constants.ErrCustomerInUse
+		}
+	}
+	if _, ok := data.customers[customerID]; !ok {
+		return constants.ErrCustomerNotFound
+	}
+	delete(data.customers, customerID)
+	return nil
+}
+
+func (s *Store) GetCustomersByIDs(ctx context.Context, customerIDs []string) ([]models.Customer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	customers := []models.Customer{}
+	for _, customer := range sortedValues(data.customers) {
+		if slices.Contains(customerIDs, customer.CustomerID) {
+			customers = append(customers, customer)
+		}
+	}
+	return customers, nil
+}
+
+func (s *Store) ListOrders(ctx context.Context, page models.PageRequest) ([]models.Order, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	orders := paginate(sortedValues(data.orders), page, orderFields, "order_id")
+	for i := range orders {
+		orders[i].Customer = data.customers[orders[i].CustomerID]
+	}
+	return orders, int64(len(data.orders)), nil
+}
+
+func (s *Store) GetOrder(ctx context.Context, orderID string) (models.OrderDetails, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return models.OrderDetails{}, err
+	}
+	order, ok := data.orders[orderID]
+	if !ok {
+		return models.OrderDetails{}, constants.ErrOrderNotFound
+	}
+	order.Customer = data.customers[order.CustomerID]
+	return models.OrderDetails{Order: order, Items: data.orderItems(orderID)}, nil
+}
+
+func (s *Store) CreateOrder(ctx context.Context, order models.Order, items []models.OrderItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return err
+	}
+	if _, taken := data.orders[order.OrderID]; taken {
+		return constants.ErrOrderExists
+	}
+	if _, ok := data.customers[order.CustomerID]; !ok {
+		return constants.ErrUnknownCustomer
+	}
+	for _, item := range items {
+		if _, ok := data.products[item.ProductID]; !ok {
+			return constants.ErrUnknownProduct
+		}
+	}
+
+	data.orders[order.OrderID] = withoutAssociations(order)
+	for _, item := range items {
+		s.insertItem(data, &item)
+	}
+	return nil
+}
+
+func (s *Store) UpdateOrder(ctx context.Context, order models.Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := data.customers[order.CustomerID]; !ok {
+		return constants.ErrUnknownCustomer
+	}
+	if _, ok := data.orders[order.OrderID]; !ok {
+		return constants.ErrOrderNotFound
+	}
+	data.orders[order.OrderID] = withoutAssociations(order)
+	return nil
+}
+
+func (s *Store) DeleteOrder(ctx context.Context, orderID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := data.orders[orderID]; !ok {
+		return constants.ErrOrderNotFound
+	}
+	for id, item := range data.items {
+		if item.OrderID == orderID {
+			delete(data.items, id)
+		}
+	}
+	delete(data.orders, orderID)
+	return nil
+}
+
+func (s *Store) ListOrderItems(ctx context.Context, orderID string) ([]models.OrderItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := data.orders[orderID]; !ok {
+		return nil, constants.ErrOrderNotFound
+	}
+	return data.orderItems(orderID), nil
+}
+
+func (s *Store) GetOrderItem(ctx context.Context, orderID string, orderItemID uint) (models.OrderItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return models.OrderItem{}, err
+	}
+	item, ok := data.items[orderItemID]
+	if !ok || item.OrderID != orderID {
+		return models.OrderItem{}, constants.ErrOrderItemNotFound
+	}
+	item.Product = data.products[item.ProductID]
+	return item, nil
+}
+
+func (s *Store) CreateOrderItem(ctx context.Context, item *models.OrderItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := data.orders[item.OrderID]; !ok {
+		return constants.ErrOrderNotFound
+	}
+	if _, ok := data.products[item.ProductID]; !ok {
+		return constants.ErrUnknownProduct
+	}
+	s.insertItem(data, item)
+	return nil
+}
+
+func (s *Store) UpdateOrderItem(ctx context.Context, item models.OrderItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := data.orders[item.OrderID]; !ok {
+		return constants.ErrOrderNotFound
+	}
+	if _, ok := data.products[item.ProductID]; !ok {
+		return constants.ErrUnknownProduct
+	}
+	if stored, ok := data.items[item.OrderItemID]; !ok || stored.OrderID != item.OrderID {
+		return constants.ErrOrderItemNotFound
+	}
+	item.Order, item.Product = models.Order{}, models.Product{}
+	data.items[item.OrderItemID] = item
+	return nil
+}
+
+func (s *Store) DeleteOrderItem(ctx context.Context, orderID string, orderItemID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := data.orders[orderID]; !ok {
+		return constants.ErrOrderNotFound
+	}
+	if stored, ok := data.items[orderItemID]; !ok || stored.OrderID != orderID {
+		return constants.ErrOrderItemNotFound
+	}
+	delete(data.items, orderItemID)
+	return nil
+}
+
+func (s *Store) GetOrdersByIDs(ctx context.Context, orderIDs []string) ([]models.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orders := []models.Order{}
+	for _, order := range sortedValues(data.orders) {
+		if slices.Contains(orderIDs, order.OrderID) {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (s *Store) ListOrdersByCustomers(ctx context.Context, customerIDs []string) ([]models.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orders := []models.Order{}
+	for _, order := range sortedValues(data.orders) {
+		if slices.Contains(customerIDs, order.CustomerID) {
+			orders = append(orders, order)
+		}
+	}
+	slices.SortStableFunc(orders, func(a, b models.Order) int { return b.DateOfSale.Compare(a.DateOfSale) })
+	return orders, nil
+}
+
+func (s *Store) ListOrderItemsByOrders(ctx context.Context, orderIDs []string) ([]models.OrderItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := []models.OrderItem{}
+	for _, item := range sortedValues(data.items) {
+		if slices.Contains(orderIDs, item.OrderID) {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// ImportSales keeps existing customers and products and adds the imported orders with their
+// items. An order id that is taken, or repeated in the import, fails the whole import.
+func (s *Store) ImportSales(ctx context.Context, customers []models.Customer, products []models.Product, orders []models.Order, items []models.OrderItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.tenantData(ctx)
+	if err != nil {
+		return err
+	}
+	imported := make(map[string]bool, len(orders))
+	for _, order := range orders {
+		if _, taken := data.orders[order.OrderID]; taken || imported[order.OrderID] {
+			return constants.ErrOrderExists
+		}
+		imported[order.OrderID] = true
+	}
+
+	for _, customer := range customers {
+		if _, ok := data.customers[customer.CustomerID]; !ok {
+			data.customers[customer.CustomerID] = customer
+		}
+	}
+	for _, product := range products {
+		if _, ok := data.products[product.ProductID]; !ok {
+			data.products[product.ProductID] = product
+		}
+	}
+	for _, order := range orders {
+		data.orders[order.OrderID] = withoutAssociations(order)
+		for _, item := range items {
+			if item.OrderID == order.OrderID {
+				s.insertItem(data, &item)
+			}
+		}
+	}
+	return nil
+}
+
+// insertItem stores an item under the next id and sets it on item.
+func (s *Store) insertItem(data *tenantData, item *models.OrderItem) {
+	s.lastItemID++
+	item.OrderItemID = s.lastItemID
+	stored := *item
+	stored.Order, stored.Product = models.Order{}, models.Product{}
+	data.items[stored.OrderItemID] = stored
+}
+
+// orderItems returns the items of an order with their product, by id.
+func (data *tenantData) orderItems(orderID string) []models.OrderItem {
+	items := []models.OrderItem{}
+	for _, item := range sortedValues(data.items) {
+		if item.OrderID == orderID {
+			item.Product = data.products[item.ProductID]
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func withoutAssociations(order models.Order) models.Order {
+	order.Customer = models.Customer{}
+	return order
+}
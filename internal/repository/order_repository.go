@@ -0,0 +1,311 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sales/internal/constants"
+	"sales/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListOrders retrieves a page of orders with their customer along with the total number of orders.
+func (s *SQLStore) ListOrders(ctx context.Context, page models.PageRequest) ([]models.Order, int64, error) {
+	db := s.db.WithContext(ctx)
+	var total int64
+	if err := db.Model(&models.Order{}).Count(&total).Error; err != nil {
+		log.Printf("Query failed: %v", err)
+		return nil, 0, err
+	}
+
+	var orders []models.Order
+	query := db.Model(&models.Order{}).
+		Preload("Customer").
+		Scopes(paginate(page, "orders.order_id")).
+		Find(&orders)
+
+	if query.Error != nil {
+		log.Printf("Query failed: %v", query.Error)
+		return nil, 0, query.Error
+	}
+
+	return orders, total, nil
+}
+
+// GetOrder retrieves an order with its customer and line items.
+func (s *SQLStore) GetOrder(ctx context.Context, orderID string) (models.OrderDetails, error) {
+	db := s.db.WithContext(ctx)
+	var order models.Order
+	err := db.Preload("Customer").Where("order_id = ?", orderID).Take(&order).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.OrderDetails{}, constants.ErrOrderNotFound
+	}
+	if err != nil {
+		log.Printf("Query failed: %v", err)
+		return models.OrderDetails{}, err
+	}
+
+	items, err := s.ListOrderItems(ctx, orderID)
+	if err != nil {
+		return models.OrderDetails{}, err
+	}
+	return models.OrderDetails{Order: order, Items: items}, nil
+}
+
+// CreateOrder inserts an order with its items, failing when the id is taken or a referenced
+// customer or product does not exist.
+func (s *SQLStore) CreateOrder(ctx context.Context, order models.Order, items []models.OrderItem) error {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing CreateOrder: orderID=%s, items=%d", order.OrderID, len(items))
+	return db.Transaction(func(tx *gorm.DB) error {
+		taken, err := exists(tx, &models.Order{}, "order_id", order.OrderID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return constants.ErrOrderExists
+		}
+		if err := checkCustomerExists(tx, order.CustomerID); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Create(&order).Error; err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if err := createOrderItem(tx, &item); err != nil {
+				return err
+			}
+		}
+		return refreshDailySales(tx, []string{saleDate(order.DateOfSale)})
+	})
+}
+
+// UpdateOrder replaces every field of an existing order, leaving its items untouched. The rollup
+// of both its old and new sale date is refreshed.
+func (s *SQLStore) UpdateOrder(ctx context.Context, order models.Order) error {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing UpdateOrder: orderID=%s", order.OrderID)
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := checkCustomerExists(tx, order.CustomerID); err != nil {
+			return err
+		}
+		oldDate, err := orderSaleDate(tx, order.OrderID)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Order{}).
+			Omit(clause.Associations).
+			Where("order_id = ?", order.OrderID).
+			Select("*").
+			Updates(&order)
+		if result.Error != nil {
+			log.Printf("Query failed: %v", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return constants.ErrOrderNotFound
+		}
+		return refreshDailySales(tx, []string{oldDate, saleDate(order.DateOfSale)})
+	})
+}
+
+// DeleteOrder removes an order along with its items.
+func (s *SQLStore) DeleteOrder(ctx context.Context, orderID string) error {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing DeleteOrder: orderID=%s", orderID)
+	return db.Transaction(func(tx *gorm.DB) error {
+		date, err := orderSaleDate(tx, orderID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("order_id = ?", orderID).Delete(&models.OrderItem{}).Error; err != nil {
+			log.Printf("Query failed: %v", err)
+			return err
+		}
+
+		if err := tx.Where("order_id = ?", orderID).Delete(&models.Order{}).Error; err != nil {
+			log.Printf("Query failed: %v", err)
+			return err
+		}
+		return refreshDailySales(tx, []string{date})
+	})
+}
+
+// ListOrderItems retrieves the items of an existing order with their product.
+func (s *SQLStore) ListOrderItems(ctx context.Context, orderID string) ([]models.OrderItem, error) {
+	db := s.db.WithContext(ctx)
+	if err := checkOrderExists(db, orderID); err != nil {
+		return nil, err
+	}
+
+	items := []models.OrderItem{}
+	query := db.Preload("Product").
+		Where("order_id = ?", orderID).
+		Order("order_item_id ASC").
+		Find(&items)
+
+	if query.Error != nil {
+		log.Printf("Query failed: %v", query.Error)
+		return nil, query.Error
+	}
+
+	return items, nil
+}
+
+// GetOrderItem retrieves an item of an order with its product.
+func (s *SQLStore) GetOrderItem(ctx context.Context, orderID string, orderItemID uint) (models.OrderItem, error) {
+	db := s.db.WithContext(ctx)
+	var item models.OrderItem
+	err := db.Preload("Product").
+		Where("order_item_id = ? AND order_id = ?", orderItemID, orderID).
+		Take(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.OrderItem{}, constants.ErrOrderItemNotFound
+	}
+	if err != nil {
+		log.Printf("Query failed: %v", err)
+		return models.OrderItem{}, err
+	}
+	return item, nil
+}
+
+// CreateOrderItem adds an item to an existing order and sets its generated id.
+func (s *SQLStore) CreateOrderItem(ctx context.Context, item *models.OrderItem) error {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing CreateOrderItem: orderID=%s, productID=%s", item.OrderID, item.ProductID)
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := checkOrderExists(tx, item.OrderID); err != nil {
+			return err
+		}
+		if err := createOrderItem(tx, item); err != nil {
+			return err
+		}
+		return refreshOrderDailySales(tx, item.OrderID)
+	})
+}
+
+// UpdateOrderItem replaces every field of an existing item of an order.
+func (s *SQLStore) UpdateOrderItem(ctx context.Context, item models.OrderItem) error {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing UpdateOrderItem: orderID=%s, orderItemID=%d", item.OrderID, item.OrderItemID)
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := checkOrderExists(tx, item.OrderID); err != nil {
+			return err
+		}
+		if err := checkProductExists(tx, item.ProductID); err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.OrderItem{}).
+			Omit(clause.Associations).
+			Where("order_item_id = ? AND order_id = ?", item.OrderItemID, item.OrderID).
+			Select("*").
+			Updates(&item)
+		if result.Error != nil {
+			log.Printf("Query failed: %v", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return constants.ErrOrderItemNotFound
+		}
+		return refreshOrderDailySales(tx, item.OrderID)
+	})
+}
+
+// DeleteOrderItem removes an item from an existing order.
+func (s *SQLStore) DeleteOrderItem(ctx context.Context, orderID string, orderItemID uint) error {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing DeleteOrderItem: orderID=%s, orderItemID=%d", orderID, orderItemID)
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := checkOrderExists(tx, orderID); err != nil {
+			return err
+		}
+
+		result := tx.Where("order_item_id = ? AND order_id = ?", orderItemID, orderID).Delete(&models.OrderItem{})
+		if result.Error != nil {
+			log.Printf("Query failed: %v", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return constants.ErrOrderItemNotFound
+		}
+		return refreshOrderDailySales(tx, orderID)
+	})
+}
+
+// createOrderItem inserts an item after checking its product exists.
+func createOrderItem(tx *gorm.DB, item *models.OrderItem) error {
+	if err := checkProductExists(tx, item.ProductID); err != nil {
+		return err
+	}
+	return tx.Omit(clause.Associations).Create(item).Error
+}
+
+func checkOrderExists(db *gorm.DB, orderID string) error {
+	found, err := exists(db, &models.Order{}, "order_id", orderID)
+	if err == nil && !found {
+		return constants.ErrOrderNotFound
+	}
+	return err
+}
+
+func checkCustomerExists(db *gorm.DB, customerID string) error {
+	found, err := exists(db, &models.Customer{}, "customer_id", customerID)
+	if err == nil && !found {
+		return constants.ErrUnknownCustomer
+	}
+	return err
+}
+
+func checkProductExists(db *gorm.DB, productID string) error {
+	found, err := exists(db, &models.Product{}, "product_id", productID)
+	if err == nil && !found {
+		return constants.ErrUnknownProduct
+	}
+	return err
+}
+
+// GetOrdersByIDs retrieves the orders with the given ids; unknown ids are skipped.
+func (s *SQLStore) GetOrdersByIDs(ctx context.Context, orderIDs []string) ([]models.Order, error) {
+	db := s.db.WithContext(ctx)
+	var orders []models.Order
+	if err := db.Where("order_id IN ?", orderIDs).Find(&orders).Error; err != nil {
+		log.Printf("Query failed: %v", err)
+		return nil, err
+	}
+	return orders, nil
+}
+
+// ListOrdersByCustomers retrieves the orders of the given customers, newest first.
+func (s *SQLStore) ListOrdersByCustomers(ctx context.Context, customerIDs []string) ([]models.Order, error) {
+	db := s.db.WithContext(ctx)
+	var orders []models.Order
+	query := db.Where("customer_id IN ?", customerIDs).
+		Order("date_of_sale DESC, order_id ASC").
+		Find(&orders)
+
+	if query.Error != nil {
+		log.Printf("Query failed: %v", query.Error)
+		return nil, query.Error
+	}
+	return orders, nil
+}
+
+// ListOrderItemsByOrders retrieves the items of the given orders.
+func (s *SQLStore) ListOrderItemsByOrders(ctx context.Context, orderIDs []string) ([]models.OrderItem, error) {
+	db := s.db.WithContext(ctx)
+	var items []models.OrderItem
+	query := db.Where("order_id IN ?", orderIDs).
+		Order("order_item_id ASC").
+		Find(&items)
+
+	if query.Error != nil {
+		log.Printf("Query failed: %v", query.Error)
+		return nil, query.Error
+	}
+	return items, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"sales/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// sort columns accepted by the list endpoints, keyed by API field name
+var (
+	ProductSortColumns = map[string]string{
+		"product_id":   "products.product_id",
+		"product_name": "products.product_name",
+		"category":     "products.category",
+		"unit_price":   "products.unit_price",
+	}
+	CustomerSortColumns = map[string]string{
+		"customer_id":    "customers.customer_id",
+		"customer_name":  "customers.customer_name",
+		"customer_email": "customers.customer_email",
+	}
+	OrderSortColumns = map[string]string{
+		"order_id":       "orders.order_id",
+		"customer_id":    "orders.customer_id",
+		"date_of_sale":   "orders.date_of_sale",
+		"region":         "orders.region",
+		"payment_method": "orders.payment_method",
+		"shipping_cost":  "orders.shipping_cost",
+	}
+)
+
+// paginate applies the requested ordering, limit and offset. The primary key is always the last
+// ordering column so that pages are stable when sort values tie.
+func paginate(page models.PageRequest, primaryKey string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, field := range page.Sort {
+			direction := " ASC"
+			if field.Desc {
+				direction = " DESC"
+			}
+			db = db.Order(field.Column + direction)
+		}
+		return db.Order(primaryKey + " ASC").Limit(page.Limit).Offset(page.Offset)
+	}
+}
@@ -1,22 +1,25 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"log"
+	"sales/internal/constants"
 	"sales/internal/models"
 
 	"gorm.io/gorm"
 )
 
 // GetTopProductsOverall retrieves the top N products overall based on quantity sold within a date range.
-func GetTopProductsOverall(db *gorm.DB, n int, startDate string, endDate string) ([]models.Product, error) {
+func (s *SQLStore) GetTopProductsOverall(ctx context.Context, n int, dateRange models.DateRange, filter models.SalesFilter) ([]models.Product, error) {
+	db := s.db.WithContext(ctx)
 	var topProducts []models.Product
-	log.Printf("Executing GetTopProductsOverall: startDate=%s, endDate=%s, limit=%d", startDate, endDate, n)
+	source := salesSourceFor(filter, false, dateRange)
+	log.Printf("Executing GetTopProductsOverall: dateRange=%s, limit=%d, filter=%+v, source=%s", dateRange, n, filter, source.name)
 	// Single query with JOIN to get full product details
-	query := db.Model(&models.OrderItem{}).
-		Select("products.product_id, products.product_name, products.category, products.unit_price, SUM(order_items.quantity_sold) as quantity_sold").
-		Joins("JOIN orders ON order_items.order_id = orders.order_id").
-		Joins("JOIN products ON order_items.product_id = products.product_id").
-		Where("orders.date_of_sale BETWEEN ? AND ?", startDate, endDate).
+	query := source.rows(db).
+		Select("products.product_id, products.product_name, products.category, products.unit_price, SUM("+source.quantity+") as quantity_sold").
+		Scopes(source.inDateRange(dateRange), source.withSalesFilter(filter)).
 		Group("products.product_id, products.product_name, products.category, products.unit_price").
 		Order("quantity_sold DESC").
 		Limit(n).
@@ -31,14 +34,14 @@ func GetTopProductsOverall(db *gorm.DB, n int, startDate string, endDate string)
 }
 
 // GetTopProductsByCategory retrieves the top N products by category based on quantity sold within a date range.
-func GetTopProductsByCategory(db *gorm.DB, n int, startDate string, endDate string) (map[string][]models.Product, error) {
-	log.Printf("Executing GetTopProductsByCategory: startDate=%s, endDate=%s, limit=%d", startDate, endDate, n)
+func (s *SQLStore) GetTopProductsByCategory(ctx context.Context, n int, dateRange models.DateRange, filter models.SalesFilter) (map[string][]models.Product, error) {
+	db := s.db.WithContext(ctx)
+	source := salesSourceFor(filter, false, dateRange)
+	log.Printf("Executing GetTopProductsByCategory: dateRange=%s, limit=%d, filter=%+v, source=%s", dateRange, n, filter, source.name)
 	var results []models.ProductResult
-	query := db.Model(&models.OrderItem{}).
-		Select("products.category, products.product_id, products.product_name, products.unit_price, SUM(order_items.quantity_sold) as quantity_sold").
-		Joins("JOIN orders ON order_items.order_id = orders.order_id").
-		Joins("JOIN products ON order_items.product_id = products.product_id").
-		Where("orders.date_of_sale BETWEEN ? AND ?", startDate, endDate).
+	query := source.rows(db).
+		Select("products.category, products.product_id, products.product_name, products.unit_price, SUM("+source.quantity+") as quantity_sold").
+		Scopes(source.inDateRange(dateRange), source.withSalesFilter(filter)).
 		Group("products.category, products.product_id, products.product_name, products.unit_price").
 		Order("products.category ASC, quantity_sold DESC").
 		Find(&results)
@@ -70,7 +73,8 @@ func GetTopProductsByCategory(db *gorm.DB, n int, startDate string, endDate stri
 }
 
 // GetTopProductsByRegion retrieves the top N products by region based on quantity sold within a date range.
-func GetTopProductsByRegion(db *gorm.DB, n int, startDate string, endDate string) (map[string][]models.Product, error) {
+func (s *SQLStore) GetTopProductsByRegion(ctx context.Context, n int, dateRange models.DateRange, filter models.SalesFilter) (map[string][]models.Product, error) {
+	db := s.db.WithContext(ctx)
 	//type productResult struct {
 	//	Region       string
 	//	ProductID    string  `gorm:"column:product_id"`
@@ -79,15 +83,14 @@ func GetTopProductsByRegion(db *gorm.DB, n int, startDate string, endDate string
 	//	UnitPrice    float64 `gorm:"column:unit_price"`
 	//	QuantitySold int     `gorm:"column:quantity_sold"`
 	//}
-	log.Printf("Executing GetTopProductsByRegion: startDate=%s, endDate=%s, limit=%d", startDate, endDate, n)
+	source := salesSourceFor(filter, false, dateRange)
+	log.Printf("Executing GetTopProductsByRegion: dateRange=%s, limit=%d, filter=%+v, source=%s", dateRange, n, filter, source.name)
 	var results []models.ProductResult
-	query := db.Model(&models.OrderItem{}).
-		Select("orders.region, products.product_id, products.product_name, products.category, products.unit_price, SUM(order_items.quantity_sold) as quantity_sold").
-		Joins("JOIN orders ON order_items.order_id = orders.order_id").
-		Joins("JOIN products ON order_items.product_id = products.product_id").
-		Where("orders.date_of_sale BETWEEN ? AND ?", startDate, endDate).
-		Group("orders.region, products.product_id, products.product_name, products.category, products.unit_price").
-		Order("orders.region ASC, quantity_sold DESC").
+	query := source.rows(db).
+		Select(source.region+" as region, products.product_id, products.product_name, products.category, products.unit_price, SUM("+source.quantity+") as quantity_sold").
+		Scopes(source.inDateRange(dateRange), source.withSalesFilter(filter)).
+		Group(source.region + ", products.product_id, products.product_name, products.category, products.unit_price").
+		Order(source.region + " ASC, quantity_sold DESC").
 		Find(&results)
 
 	if query.Error != nil {
@@ -115,3 +118,136 @@ func GetTopProductsByRegion(db *gorm.DB, n int, startDate string, endDate string
 
 	return topProductsByRegion, nil
 }
+
+// GetProductSalesComparison retrieves quantity sold per product in a baseline and a recent date range.
+func (s *SQLStore) GetProductSalesComparison(ctx context.Context, baseline models.DateRange, recent models.DateRange, filter models.SalesFilter) ([]models.ProductTrendResult, error) {
+	db := s.db.WithContext(ctx)
+	source := salesSourceFor(filter, false, baseline, recent)
+	log.Printf("Executing GetProductSalesComparison: baseline=%s, recent=%s, filter=%+v, source=%s", baseline, recent, filter, source.name)
+	var results []models.ProductTrendResult
+	query := source.rows(db).
+		Select("products.product_id, products.product_name, products.category, products.unit_price, "+
+			"SUM(CASE WHEN "+source.saleTime+" >= ? AND "+source.saleTime+" < ? THEN "+source.quantity+" ELSE 0 END) as recent_quantity, "+
+			"SUM(CASE WHEN "+source.saleTime+" >= ? AND "+source.saleTime+" < ? THEN "+source.quantity+" ELSE 0 END) as baseline_quantity",
+			source.bound(recent.From), source.bound(recent.To), source.bound(baseline.From), source.bound(baseline.To)).
+		Scopes(source.inDateRange(models.DateRange{From: baseline.From, To: recent.To}), source.withSalesFilter(filter)).
+		Group("products.product_id, products.product_name, products.category, products.unit_price").
+		Find(&results)
+
+	if query.Error != nil {
+		log.Printf("Query failed: %v", query.Error)
+		return nil, query.Error
+	}
+
+	return results, nil
+}
+
+// ListProducts retrieves a page of products along with the total number of products.
+func (s *SQLStore) ListProducts(ctx context.Context, page models.PageRequest) ([]models.Product, int64, error) {
+	db := s.db.WithContext(ctx)
+	var total int64
+	if err := db.Model(&models.Product{}).Count(&total).Error; err != nil {
+		log.Printf("Query failed: %v", err)
+		return nil, 0, err
+	}
+
+	var products []models.Product
+	query := db.Model(&models.Product{}).
+		Scopes(paginate(page, "products.product_id")).
+		Find(&products)
+
+	if query.Error != nil {
+		log.Printf("Query failed: %v", query.Error)
+		return nil, 0, query.Error
+	}
+
+	return products, total, nil
+}
+
+// GetProduct retrieves a product by id.
+func (s *SQLStore) GetProduct(ctx context.Context, productID string) (models.Product, error) {
+	db := s.db.WithContext(ctx)
+	var product models.Product
+	err := db.Where("product_id = ?", productID).Take(&product).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Product{}, constants.ErrProductNotFound
+	}
+	if err != nil {
+		log.Printf("Query failed: %v", err)
+		return models.Product{}, err
+	}
+	return product, nil
+}
+
+// CreateProduct inserts a product, failing when the id is taken.
+func (s *SQLStore) CreateProduct(ctx context.Context, product models.Product) error {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing CreateProduct: productID=%s", product.ProductID)
+	return db.Transaction(func(tx *gorm.DB) error {
+		taken, err := exists(tx, &models.Product{}, "product_id", product.ProductID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return constants.ErrProductExists
+		}
+		return tx.Create(&product).Error
+	})
+}
+
+// UpdateProduct replaces every field of an existing product.
+// Its price and category are rolled up, so the rollup of every date it sold on is refreshed.
+func (s *SQLStore) UpdateProduct(ctx context.Context, product models.Product) error {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing UpdateProduct: productID=%s", product.ProductID)
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Product{}).
+			Where("product_id = ?", product.ProductID).
+			Select("*").
+			Updates(&product)
+		if result.Error != nil {
+			log.Printf("Query failed: %v", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return constants.ErrProductNotFound
+		}
+		return refreshProductDailySales(tx, product.ProductID)
+	})
+}
+
+// DeleteProduct removes a product that no order item references.
+func (s *SQLStore) DeleteProduct(ctx context.Context, productID string) error {
+	db := s.db.WithContext(ctx)
+	log.Printf("Executing DeleteProduct: productID=%s", productID)
+	return db.Transaction(func(tx *gorm.DB) error {
+		inUse, err := exists(tx, &models.OrderItem{}, "product_id", productID)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return constants.ErrProductInUse
+		}
+
+		result := tx.Where("product_id = ?", productID).Delete(&models.Product{})
+		if result.Error != nil {
+			log.Printf("Query failed: %v", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return constants.ErrProductNotFound
+		}
+		return nil
+	})
+}
+
+// GetProductsByIDs retrieves the products with the given ids; unknown ids are skipped.
+func (s *SQLStore) GetProductsByIDs(ctx context.Context, productIDs []string) ([]models.Product, error) {
+	db := s.db.WithContext(ctx)
+	var products []models.Product
+	if err := db.Where("product_id IN ?", productIDs).Find(&products).Error; err != nil {
+		log.Printf("Query failed: %v", err)
+		return nil, err
+	}
+	return products, nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"sales/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// The repositories read and write the data of the tenant in their context, failing with
+// tenant.ErrNoTenant without one. SQLStore implements them on the configured database and
+// memory.Store in process memory.
+
+// ProductRepository stores the product catalog.
+type ProductRepository interface {
+	ListProducts(ctx context.Context, page models.PageRequest) ([]models.Product, int64, error)
+	GetProduct(ctx context.Context, productID string) (models.Product, error)
+	CreateProduct(ctx context.Context, product models.Product) error
+	UpdateProduct(ctx context.Context, product models.Product) error
+	DeleteProduct(ctx context.Context, productID string) error
+	GetProductsByIDs(ctx context.Context, productIDs []string) ([]models.Product, error)
+}
+
+// CustomerRepository stores customers.
+type CustomerRepository interface {
+	ListCustomers(ctx context.Context, page models.PageRequest) ([]models.Customer, int64, error)
+	GetCustomer(ctx context.Context, customerID string) (models.Customer, error)
+	CreateCustomer(ctx context.Context, customer models.Customer) error
+	UpdateCustomer(ctx context.Context, customer models.Customer) error
+	DeleteCustomer(ctx context.Context, customerID string) error
+	GetCustomersByIDs(ctx context.Context, customerIDs []string) ([]models.Customer, error)
+}
+
+// OrderRepository stores orders with their line items, and imports sales in bulk.
+type OrderRepository interface {
+	ListOrders(ctx context.Context, page models.PageRequest) ([]models.Order, int64, error)
+	GetOrder(ctx context.Context, orderID string) (models.OrderDetails, error)
+	CreateOrder(ctx context.Context, order models.Order, items []models.OrderItem) error
+	UpdateOrder(ctx context.Context, order models.Order) error
+	DeleteOrder(ctx context.Context, orderID string) error
+	ListOrderItems(ctx context.Context, orderID string) ([]models.OrderItem, error)
+	GetOrderItem(ctx context.Context, orderID string, orderItemID uint) (models.OrderItem, error)
+	CreateOrderItem(ctx context.Context, item *models.OrderItem) error
+	UpdateOrderItem(ctx context.Context, item models.OrderItem) error
+	DeleteOrderItem(ctx context.Context, orderID string, orderItemID uint) error
+	GetOrdersByIDs(ctx context.Context, orderIDs []string) ([]models.Order, error)
+	ListOrdersByCustomers(ctx context.Context, customerIDs []string) ([]models.Order, error)
+	ListOrderItemsByOrders(ctx context.Context, orderIDs []string) ([]models.OrderItem, error)
+	ImportSales(ctx context.Context, customers []models.Customer, products []models.Product, orders []models.Order, items []models.OrderItem) error
+}
+
+// AnalyticsRepository aggregates sales.
+type AnalyticsRepository interface {
+	GetTopProductsOverall(ctx context.Context, n int, dateRange models.DateRange, filter models.SalesFilter) ([]models.Product, error)
+	GetTopProductsByCategory(ctx context.Context, n int, dateRange models.DateRange, filter models.SalesFilter) (map[string][]models.Product, error)
+	GetTopProductsByRegion(ctx context.Context, n int, dateRange models.DateRange, filter models.SalesFilter) (map[string][]models.Product, error)
+	GetProductSalesComparison(ctx context.Context, baseline models.DateRange, recent models.DateRange, filter models.SalesFilter) ([]models.ProductTrendResult, error)
+	GetSalesByPeriod(ctx context.Context, interval string, productID string, dateRange models.DateRange, filter models.SalesFilter) ([]models.SalesBucket, error)
+	GetProductSalesTotals(ctx context.Context, productIDs []string, dateRange models.DateRange, filter models.SalesFilter) ([]models.ProductSalesTotal, error)
+	GetCustomerSegmentsByPeriod(ctx context.Context, interval string, dateRange models.DateRange, filter models.SalesFilter) ([]models.CustomerSegmentRow, error)
+	GetPivotCells(ctx context.Context, rowDimension string, columnDimension string, metric string, dateRange models.DateRange, filter models.SalesFilter) ([]models.PivotCell, error)
+	GetOrderValues(ctx context.Context, groupBy string, dateRange models.DateRange, filter models.SalesFilter) ([]models.GroupedValue, error)
+	GetLineQuantities(ctx context.Context, groupBy string, dateRange models.DateRange, filter models.SalesFilter) ([]models.GroupedValue, error)
+}
+
+// Store implements every repository.
+type Store interface {
+	ProductRepository
+	CustomerRepository
+	OrderRepository
+	AnalyticsRepository
+}
+
+// SQLStore implements the repositories on a database migrated and tenant-scoped by the database
+// package.
+type SQLStore struct {
+	db *gorm.DB
+}
+
+var _ Store = (*SQLStore)(nil)
+
+// NewSQLStore returns the repositories of db.
+func NewSQLStore(db *gorm.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
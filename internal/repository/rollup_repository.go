@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"errors"
+	"log"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// saleDateExpr is the UTC date of a stored sale timestamp, the day rollup rows are kept per.
+const saleDateExpr = "substr(orders.date_of_sale, 1, 10)"
+
+// rollupColumns are the columns of daily_product_sales filled from the raw tables, in rollupRows order.
+const rollupColumns = "tenant_id, sale_date, product_id, region, category, payment_method, quantity, gross_revenue, net_revenue, orders"
+
+// rollupRows aggregates order items into daily_product_sales rows. Statements on tenant data only
+// aggregate the tenant of db's context.
+func rollupRows(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.OrderItem{}).
+		Select("order_items.tenant_id, " + saleDateExpr + ", order_items.product_id, orders.region, products.category, orders.payment_method, " +
+			"SUM(order_items.quantity_sold), SUM(order_items.quantity_sold * products.unit_price), SUM(" + revenueExpr + "), " +
+			"COUNT(DISTINCT order_items.order_id)").
+		Joins(joinOrders).
+		Joins(joinProducts).
+		Group("order_items.tenant_id, " + saleDateExpr + ", order_items.product_id, orders.region, products.category, orders.payment_method")
+}
+
+// refreshDailySales recomputes the rollup rows of the given UTC sale dates (YYYY-MM-DD) for the
+// tenant of db's context. Writes to orders, items or products call it within their transaction.
+func refreshDailySales(db *gorm.DB, dates []string) error {
+	if len(dates) == 0 {
+		return nil
+	}
+	log.Printf("Executing RefreshDailySales: dates=%d", len(dates))
+	if err := db.Where("sale_date IN ?", dates).Delete(&models.DailyProductSales{}).Error; err != nil {
+		log.Printf("Query failed: %v", err)
+		return err
+	}
+	rows := rollupRows(db).Where(saleDateExpr+" IN ?", dates)
+	if err := db.Exec("INSERT INTO daily_product_sales ("+rollupColumns+") ?", rows).Error; err != nil {
+		log.Printf("Query failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// refreshProductDailySales recomputes the rollup rows of every date a product sold on, after its
+// price or category changed.
+func refreshProductDailySales(db *gorm.DB, productID string) error {
+	var dates []string
+	err := db.Model(&models.DailyProductSales{}).
+		Where("product_id = ?", productID).
+		Distinct().
+		Pluck("sale_date", &dates).Error
+	if err != nil {
+		log.Printf("Query failed: %v", err)
+		return err
+	}
+	return refreshDailySales(db, dates)
+}
+
+// refreshOrderDailySales recomputes the rollup rows of the date an order was sold on.
+func refreshOrderDailySales(db *gorm.DB, orderID string) error {
+	date, err := orderSaleDate(db, orderID)
+	if err != nil {
+		return err
+	}
+	return refreshDailySales(db, []string{date})
+}
+
+// orderSaleDate returns the UTC sale date of an existing order.
+func orderSaleDate(db *gorm.DB, orderID string) (string, error) {
+	var order models.Order
+	err := db.Select("date_of_sale").Where("order_id = ?", orderID).Take(&order).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", constants.ErrOrderNotFound
+	}
+	if err != nil {
+		log.Printf("Query failed: %v", err)
+		return "", err
+	}
+	return saleDate(order.DateOfSale), nil
+}
+
+// saleDate returns the UTC date a sale is rolled up on.
+func saleDate(t time.Time) string {
+	return t.UTC().Format(constants.DateFormat)
+}
+
+// salesSource is a table analytics aggregate sales from: the raw order items joined with their
+// order and product, or the daily rollup joined with its product. Both expose products.* columns.
+type salesSource struct {
+	name  string
+	model any
+	joins []string
+	// quantity and revenue are summed over the source's rows
+	quantity string
+	revenue  string
+	// productOrders counts the orders among rows of a single product
+	productOrders string
+	// saleTime is compared with range bounds formatted by bound
+	saleTime      string
+	bound         func(time.Time) string
+	productID     string
+	region        string
+	paymentMethod string
+	category      string
+}
+
+// rawSales aggregates order items, exact for any range, filter and timezone.
+var rawSales = salesSource{
+	name:          "raw",
+	model:         &models.OrderItem{},
+	joins:         []string{joinOrders, joinProducts},
+	quantity:      "order_items.quantity_sold",
+	revenue:       revenueExpr,
+	productOrders: "COUNT(DISTINCT order_items.order_id)",
+	saleTime:      "orders.date_of_sale",
+	bound:         timestampBound,
+	productID:     "order_items.product_id",
+	region:        "orders.region",
+	paymentMethod: "orders.payment_method",
+	category:      "products.category",
+}
+
+// rollupSales aggregates daily_product_sales, which only resolves whole UTC days.
+var rollupSales = salesSource{
+	name:  "rollup",
+	model: &models.DailyProductSales{},
+	joins: []string{"JOIN products ON products.tenant_id = daily_product_sales.tenant_id AND products.product_id = daily_product_sales.product_id"},
+	// quantities and revenues are already summed per row
+	quantity:      "daily_product_sales.quantity",
+	revenue:       "daily_product_sales.net_revenue",
+	productOrders: "SUM(daily_product_sales.orders)",
+	saleTime:      "daily_product_sales.sale_date",
+	bound:         saleDate,
+	productID:     "daily_product_sales.product_id",
+	region:        "daily_product_sales.region",
+	paymentMethod: "daily_product_sales.payment_method",
+	category:      "daily_product_sales.category",
+}
+
+// salesSourceFor picks the rollup when it answers a query exactly: every range starts and ends on a
+// UTC day boundary, sales are bucketed on UTC days if bucketed is set, and no filter needs a
+// column the rollup doesn't keep. Otherwise the raw tables are aggregated.
+func salesSourceFor(filter models.SalesFilter, bucketed bool, ranges ...models.DateRange) salesSource {
+	if len(filter.CustomerIDs) > 0 {
+		return rawSales
+	}
+	for _, dateRange := range ranges {
+		if !onUTCDay(dateRange.From) || !onUTCDay(dateRange.To) || (bucketed && !dateRange.OnUTCDays()) {
+			return rawSales
+		}
+	}
+	return rollupSales
+}
+
+// onUTCDay reports whether a range bound is open or falls on a UTC midnight.
+func onUTCDay(t time.Time) bool {
+	return t.IsZero() || t.UTC().Truncate(24*time.Hour).Equal(t)
+}
+
+// rows starts a query over the source's rows.
+func (s salesSource) rows(db *gorm.DB) *gorm.DB {
+	db = db.Model(s.model)
+	for _, join := range s.joins {
+		db = db.Joins(join)
+	}
+	return db
+}
+
+// inDateRange restricts the source to sales within the half-open range. Bounds are formatted like
+// the stored sale timestamps or dates, which sort chronologically as text.
+func (s salesSource) inDateRange(dateRange models.DateRange) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !dateRange.From.IsZero() {
+			db = db.Where(s.saleTime+" >= ?", s.bound(dateRange.From))
+		}
+		if !dateRange.To.IsZero() {
+			db = db.Where(s.saleTime+" < ?", s.bound(dateRange.To))
+		}
+		return db
+	}
+}
+
+// withSalesFilter applies the optional analytics filters as parameterized conditions.
+func (s salesSource) withSalesFilter(filter models.SalesFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(filter.Categories) > 0 {
+			db = db.Where(s.category+" IN ?", filter.Categories)
+		}
+		if len(filter.Regions) > 0 {
+			db = db.Where(s.region+" IN ?", filter.Regions)
+		}
+		if len(filter.PaymentMethods) > 0 {
+			db = db.Where(s.paymentMethod+" IN ?", filter.PaymentMethods)
+		}
+		if len(filter.CustomerIDs) > 0 {
+			db = db.Where("orders.customer_id IN ?", filter.CustomerIDs)
+		}
+		if filter.MinPrice != nil {
+			db = db.Where("products.unit_price >= ?", *filter.MinPrice)
+		}
+		if filter.MaxPrice != nil {
+			db = db.Where("products.unit_price <= ?", *filter.MaxPrice)
+		}
+		return db
+	}
+}
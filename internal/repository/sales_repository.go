@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// revenueExpr is the net revenue of an order item after discount, excluding shipping.
+const revenueExpr = "order_items.quantity_sold * products.unit_price * (1 - order_items.discount)"
+
+// joinOrders and joinProducts join the order and product of order items. Only the queried table is
+// scoped to the tenant automatically, so joins match on tenant_id as well.
+const (
+	joinOrders   = "JOIN orders ON orders.tenant_id = order_items.tenant_id AND orders.order_id = order_items.order_id"
+	joinProducts = "JOIN products ON products.tenant_id = order_items.tenant_id AND products.product_id = order_items.product_id"
+)
+
+// periodExpr returns an SQL expression bucketing a UTC timestamp or date column to the first day of its interval
+// (YYYY-MM-DD) after shifting it into the caller's timezone by the offset in effect at each row. Weeks start on Monday.
+func periodExpr(db *gorm.DB, interval string, column string, offsets []models.ZoneOffset) string {
+	if db.Dialector.Name() == constants.DriverPostgres {
+		return postgresPeriodExpr(interval, column, offsets)
+	}
+	ts := "substr(" + column + ", 1, 19)"
+	if shift := offsetExpr(ts, offsets, func(minutes int) string { return fmt.Sprintf("'%+d minutes'", minutes) }); shift != "" {
+		ts = "datetime(" + ts + ", " + shift + ")"
+	}
+	switch interval {
+	case constants.IntervalDay:
+		return "date(" + ts + ")"
+	case constants.IntervalMonth:
+		return "strftime('%Y-%m-01', " + ts + ")"
+	default:
+		return "date(" + ts + ", '-6 days', 'weekday 1')"
+	}
+}
+
+// postgresPeriodExpr is periodExpr in PostgreSQL, whose date_trunc weeks also start on Monday.
+func postgresPeriodExpr(interval string, column string, offsets []models.ZoneOffset) string {
+	raw := "substr(" + column + ", 1, 19)"
+	ts := "CAST(" + raw + " AS timestamp)"
+	if shift := offsetExpr(raw, offsets, func(minutes int) string { return fmt.Sprintf("interval '%d minutes'", minutes) }); shift != "" {
+		ts = "(" + ts + " + " + shift + ")"
+	}
+	switch interval {
+	case constants.IntervalDay:
+		return "to_char(" + ts + ", 'YYYY-MM-DD')"
+	case constants.IntervalMonth:
+		return "to_char(date_trunc('month', " + ts + "), 'YYYY-MM-DD')"
+	default:
+		return "to_char(date_trunc('week', " + ts + "), 'YYYY-MM-DD')"
+	}
+}
+
+// offsetExpr returns the shift of a UTC "YYYY-MM-DD HH:MM:SS" expression into the zone, picking the
+// offset in effect at each row when daylight saving changes inside the range. It is empty at UTC.
+func offsetExpr(ts string, offsets []models.ZoneOffset, format func(minutes int) string) string {
+	if len(offsets) == 1 {
+		if offsets[0].Minutes == 0 {
+			return ""
+		}
+		return format(offsets[0].Minutes)
+	}
+	expr := "CASE"
+	for i := len(offsets) - 1; i > 0; i-- {
+		expr += fmt.Sprintf(" WHEN %s >= '%s' THEN %s", ts, offsets[i].From.UTC().Format(time.DateTime), format(offsets[i].Minutes))
+	}
+	return expr + " ELSE " + format(offsets[0].Minutes) + " END"
+}
+
+// timestampBound formats a range bound in the stored sale timestamp format.
+func timestampBound(t time.Time) string {
+	return t.UTC().Format(models.TimestampFormat)
+}
+
+// GetSalesByPeriod retrieves quantity and revenue per day, week or month for an optional product and the
+// filtered sales. Periods without sales are not returned.
+func (s *SQLStore) GetSalesByPeriod(ctx context.Context, interval string, productID string, dateRange models.DateRange, filter models.SalesFilter) ([]models.SalesBucket, error) {
+	db := s.db.WithContext(ctx)
+	source := salesSourceFor(filter, true, dateRange)
+	log.Printf("Executing GetSalesByPeriod: interval=%s, productID=%s, dateRange=%s, filter=%+v, source=%s", interval, productID, dateRange, filter, source.name)
+	var buckets []models.SalesBucket
+	query := source.rows(db).
+		Select(periodExpr(db, interval, source.saleTime, dateRange.ZoneOffsets()) + " as period, SUM(" + source.quantity + ") as quantity_sold, " +
+			"SUM(" + source.revenue + ") as revenue")
+
+	if productID != "" {
+		query = query.Where("products.product_id = ?", productID)
+	}
+	query = query.Scopes(source.inDateRange(dateRange), source.withSalesFilter(filter))
+
+	query = query.Group("period").
+		Order("period ASC").
+		Find(&buckets)
+
+	if query.Error != nil {
+		log.Printf("Query failed: %v", query.Error)
+		return nil, query.Error
+	}
+
+	return buckets, nil
+}
+
+// GetProductSalesTotals retrieves quantity, revenue and order count per product for the given products
+// within the date range and filters. Products without sales are not returned.
+func (s *SQLStore) GetProductSalesTotals(ctx context.Context, productIDs []string, dateRange models.DateRange, filter models.SalesFilter) ([]models.ProductSalesTotal, error) {
+	db := s.db.WithContext(ctx)
+	source := salesSourceFor(filter, false, dateRange)
+	log.Printf("Executing GetProductSalesTotals: products=%d, dateRange=%s, filter=%+v, source=%s", len(productIDs), dateRange, filter, source.name)
+	var totals []models.ProductSalesTotal
+	query := source.rows(db).
+		Select(source.productID+" as product_id, SUM("+source.quantity+") as quantity_sold, "+
+			"SUM("+source.revenue+") as revenue, "+source.productOrders+" as orders").
+		Where(source.productID+" IN ?", productIDs).
+		Scopes(source.inDateRange(dateRange), source.withSalesFilter(filter)).
+		Group(source.productID).
+		Find(&totals)
+
+	if query.Error != nil {
+		log.Printf("Query failed: %v", query.Error)
+		return nil, query.Error
+	}
+
+	return totals, nil
+}
+
+// exists reports whether a row of model has column equal to value.
+func exists(db *gorm.DB, model any, column string, value any) (bool, error) {
+	var count int64
+	if err := db.Model(model).Where(column+" = ?", value).Count(&count).Error; err != nil {
+		log.Printf("Query failed: %v", err)
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"sales/internal/constants"
+	"sales/internal/database"
+	"sales/internal/models"
+	"testing"
+	"time"
+)
+
+// TestPeriodExprFollowsDaylightSaving buckets sales around both daylight saving changes of a year and
+// expects the calendar day, week and month of each sale in the zone, not at the offset of the range start.
+func TestPeriodExprFollowsDaylightSaving(t *testing.T) {
+	db, err := database.Open(database.Config{Driver: constants.DriverSQLite, DSN: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, zone := range []string{"America/New_York", "Europe/Berlin", "Asia/Kolkata", "UTC"} {
+		location, err := time.LoadLocation(zone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dateRange := models.DateRange{
+			From:     time.Date(2024, 1, 1, 0, 0, 0, 0, location),
+			To:       time.Date(2025, 1, 1, 0, 0, 0, 0, location),
+			Location: location,
+		}
+		for _, interval := range constants.Intervals {
+			expr := periodExpr(db, interval, "sales.date_of_sale", dateRange.ZoneOffsets())
+			// the first and last half hour of local days around both changes, months and weeks
+			var sales []time.Time
+			for _, day := range []time.Time{
+				time.Date(2024, 3, 10, 0, 0, 0, 0, location),
+				time.Date(2024, 3, 31, 0, 0, 0, 0, location),
+				time.Date(2024, 4, 1, 0, 0, 0, 0, location),
+				time.Date(2024, 7, 1, 0, 0, 0, 0, location),
+				time.Date(2024, 10, 28, 0, 0, 0, 0, location),
+				time.Date(2024, 11, 4, 0, 0, 0, 0, location),
+				time.Date(2024, 12, 31, 0, 0, 0, 0, location),
+			} {
+				sales = append(sales, day.Add(30*time.Minute), day.AddDate(0, 0, 1).Add(-30*time.Minute))
+			}
+			for _, local := range sales {
+				var got string
+				stored := local.UTC().Format(models.TimestampFormat)
+				if err := db.Raw("SELECT "+expr+" FROM (SELECT ? AS date_of_sale) AS sales", stored).Scan(&got).Error; err != nil {
+					t.Fatal(err)
+				}
+				if want := expectedPeriod(interval, local); got != want {
+					t.Errorf("%s %s bucket of %s = %s, want %s", zone, interval, local, got, want)
+				}
+			}
+		}
+	}
+}
+
+// expectedPeriod is the first day of the interval holding the local time's calendar day.
+func expectedPeriod(interval string, local time.Time) string {
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case constants.IntervalMonth:
+		day = day.AddDate(0, 0, 1-day.Day())
+	case constants.IntervalWeek:
+		day = day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return day.Format(constants.DateFormat)
+}
+
+// TestZoneOffsets expects one offset per daylight saving change inside the range.
+func TestZoneOffsets(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dateRange := models.DateRange{
+		From:     time.Date(2024, 1, 1, 0, 0, 0, 0, location),
+		To:       time.Date(2025, 1, 1, 0, 0, 0, 0, location),
+		Location: location,
+	}
+	offsets := dateRange.ZoneOffsets()
+	if len(offsets) != 3 || offsets[0].Minutes != -300 || offsets[1].Minutes != -240 || offsets[2].Minutes != -300 {
+		t.Fatalf("offsets = %+v, want -300, -240, -300", offsets)
+	}
+	if want := time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC); !offsets[1].From.Equal(want) {
+		t.Errorf("summer time starts at %s, want %s", offsets[1].From, want)
+	}
+	if dateRange.OnUTCDays() {
+		t.Error("a New York range buckets on UTC days")
+	}
+	if !(models.DateRange{From: dateRange.From, To: dateRange.To, Location: time.UTC}).OnUTCDays() {
+		t.Error("a UTC range doesn't bucket on UTC days")
+	}
+}
@@ -0,0 +1,309 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sales/internal/constants"
+	"sales/internal/database"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"sales/internal/services"
+	"sales/internal/tenant"
+	"sales/pkg/cache"
+	"testing"
+	"time"
+)
+
+const csvHeader = "Order ID,Product ID,Customer ID,Product Name,Category,Region,Date of Sale,Quantity Sold,Unit Price,Discount,Shipping Cost,Payment Method,Customer Name,Customer Email,Customer Address\n"
+
+// tenantCSVs are the sales of two tenants. They share order, product and customer ids with
+// different values and sell on the same dates, so a query or rollup refresh missing a tenant
+// condition mixes them up.
+var tenantCSVs = map[string]string{
+	"a": csvHeader +
+		`1001,P1,C1,Trail Shoes,Shoes,North America,2024-01-05,5,100.00,0.1,10.00,Credit Card,Ann Archer,ann@a.example,"1 A St"` + "\n" +
+		`1002,P2,C2,Rain Jacket,Clothing,Europe,2024-01-05,3,80.00,0.0,5.00,PayPal,Bob Archer,bob@a.example,"2 A St"` + "\n" +
+		`1003,P1,C2,Trail Shoes,Shoes,Europe,2024-02-10,2,100.00,0.0,5.00,PayPal,Bob Archer,bob@a.example,"2 A St"` + "\n" +
+		`1004,P4,C1,Wool Socks,Clothing,Asia,2024-03-31,8,12.00,0.0,3.00,Debit Card,Ann Archer,ann@a.example,"1 A St"` + "\n",
+	"b": csvHeader +
+		`1001,P1,C1,Phone X,Electronics,Asia,2024-01-05,1,900.00,0.05,15.00,PayPal,Cat Baker,cat@b.example,"1 B St"` + "\n" +
+		`1005,P3,C3,Laptop Y,Electronics,South America,2024-02-10,4,1500.00,0.1,25.00,Credit Card,Dan Baker,dan@b.example,"3 B St"` + "\n" +
+		`1006,P1,C3,Phone X,Electronics,South America,2024-03-15,6,900.00,0.0,15.00,Credit Card,Dan Baker,dan@b.example,"3 B St"` + "\n" +
+		`1007,P5,C1,Charger,Accessories,Asia,2024-01-05,9,20.00,0.0,2.00,PayPal,Cat Baker,cat@b.example,"1 B St"` + "\n",
+}
+
+// newSQLStore opens a migrated, tenant-scoped SQLite database in a temporary directory.
+func newSQLStore(t *testing.T) *repository.SQLStore {
+	t.Helper()
+	db, err := database.NewDatabase(database.Config{Driver: constants.DriverSQLite, DSN: filepath.Join(t.TempDir(), "sales.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		t.Cleanup(func() { _ = sqlDB.Close() })
+	}
+	return repository.NewSQLStore(db)
+}
+
+// tenantContext returns a context acting for the tenant id.
+func tenantContext(id string) context.Context {
+	return tenant.NewContext(context.Background(), tenant.Tenant{ID: id})
+}
+
+// refreshTenant imports the CSV of tenant id into store through the refresh service.
+func refreshTenant(t *testing.T, store repository.Store, id string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), id+".csv")
+	if err := os.WriteFile(path, []byte(tenantCSVs[id]), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	svc := services.New(store, services.NewResultCache(cache.NewLRU(constants.CacheEntries, constants.CacheTTL)))
+	if err := svc.Refresh.RefreshDatabase(tenantContext(id), path); err != nil {
+		t.Fatalf("refreshing tenant %s: %v", id, err)
+	}
+}
+
+// storeQuery is a read of a tenant's data, named for failure messages.
+type storeQuery struct {
+	name string
+	run  func(ctx context.Context, store repository.Store) (any, error)
+}
+
+// isolationQueries read every entity and run every analytics query on both the rollup and, with a
+// timezone or a customer filter, the raw tables.
+func isolationQueries(t *testing.T) []storeQuery {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rollup := models.DateRange{From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Location: time.UTC}
+	raw := models.DateRange{From: time.Date(2024, 1, 1, 0, 0, 0, 0, tokyo), To: time.Date(2025, 1, 1, 0, 0, 0, 0, tokyo), Location: tokyo}
+	baseline := models.DateRange{From: rollup.From, To: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Location: time.UTC}
+	recent := models.DateRange{From: baseline.To, To: rollup.To, Location: time.UTC}
+	page := models.PageRequest{Limit: 100}
+
+	queries := []storeQuery{
+		{"ListProducts", func(ctx context.Context, s repository.Store) (any, error) {
+			items, total, err := s.ListProducts(ctx, page)
+			return []any{items, total}, err
+		}},
+		{"ListCustomers", func(ctx context.Context, s repository.Store) (any, error) {
+			items, total, err := s.ListCustomers(ctx, page)
+			return []any{items, total}, err
+		}},
+		{"ListOrders", func(ctx context.Context, s repository.Store) (any, error) {
+			items, total, err := s.ListOrders(ctx, page)
+			return []any{items, total}, err
+		}},
+		{"GetProduct", func(ctx context.Context, s repository.Store) (any, error) { return s.GetProduct(ctx, "P1") }},
+		{"GetCustomer", func(ctx context.Context, s repository.Store) (any, error) { return s.GetCustomer(ctx, "C1") }},
+		{"GetOrder", func(ctx context.Context, s repository.Store) (any, error) { return s.GetOrder(ctx, "1001") }},
+		{"ListOrderItems", func(ctx context.Context, s repository.Store) (any, error) { return s.ListOrderItems(ctx, "1001") }},
+		{"GetProductsByIDs", func(ctx context.Context, s repository.Store) (any, error) {
+			return s.GetProductsByIDs(ctx, []string{"P1", "P2", "P3", "P4", "P5"})
+		}},
+		{"GetCustomersByIDs", func(ctx context.Context, s repository.Store) (any, error) {
+			return s.GetCustomersByIDs(ctx, []string{"C1", "C2", "C3"})
+		}},
+		{"ListOrdersByCustomers", func(ctx context.Context, s repository.Store) (any, error) {
+			return s.ListOrdersByCustomers(ctx, []string{"C1", "C2", "C3"})
+		}},
+	}
+	for source, dateRange := range map[string]models.DateRange{"rollup": rollup, "raw": raw} {
+		for _, filter := range []models.SalesFilter{{}, {CustomerIDs: []string{"C1", "C3"}}} {
+			name := func(query string) string {
+				if len(filter.CustomerIDs) > 0 {
+					return query + " (" + source + ", customer filter)"
+				}
+				return query + " (" + source + ")"
+			}
+			queries = append(queries,
+				storeQuery{name("GetTopProductsOverall"), func(ctx context.Context, s repository.Store) (any, error) {
+					return s.GetTopProductsOverall(ctx, 10, dateRange, filter)
+				}},
+				storeQuery{name("GetTopProductsByCategory"), func(ctx context.Context, s repository.Store) (any, error) {
+					return s.GetTopProductsByCategory(ctx, 10, dateRange, filter)
+				}},
+				storeQuery{name("GetTopProductsByRegion"), func(ctx context.Context, s repository.Store) (any, error) {
+					return s.GetTopProductsByRegion(ctx, 10, dateRange, filter)
+				}},
+				storeQuery{name("GetSalesByPeriod"), func(ctx context.Context, s repository.Store) (any, error) {
+					return s.GetSalesByPeriod(ctx, constants.IntervalMonth, "", dateRange, filter)
+				}},
+				storeQuery{name("GetSalesByPeriod of P1"), func(ctx context.Context, s repository.Store) (any, error) {
+					return s.GetSalesByPeriod(ctx, constants.IntervalDay, "P1", dateRange, filter)
+				}},
+				storeQuery{name("GetProductSalesTotals"), func(ctx context.Context, s repository.Store) (any, error) {
+					return s.GetProductSalesTotals(ctx, []string{"P1", "P2", "P3", "P4", "P5"}, dateRange, filter)
+				}},
+				storeQuery{name("GetCustomerSegmentsByPeriod"), func(ctx context.Context, s repository.Store) (any, error) {
+					return s.GetCustomerSegmentsByPeriod(ctx, constants.IntervalMonth, dateRange, filter)
+				}},
+				storeQuery{name("GetOrderValues"), func(ctx context.Context, s repository.Store) (any, error) {
+					return s.GetOrderValues(ctx, constants.DimensionRegion, dateRange, filter)
+				}},
+				storeQuery{name("GetLineQuantities"), func(ctx context.Context, s repository.Store) (any, error) {
+					return s.GetLineQuantities(ctx, constants.DimensionCategory, dateRange, filter)
+				}},
+			)
+			for _, metric := range []string{constants.MetricQuantity, constants.MetricRevenue, constants.MetricOrders} {
+				queries = append(queries,
+					storeQuery{name("GetPivotCells product/region " + metric), func(ctx context.Context, s repository.Store) (any, error) {
+						return s.GetPivotCells(ctx, constants.DimensionProduct, constants.DimensionRegion, metric, dateRange, filter)
+					}},
+					storeQuery{name("GetPivotCells month/category " + metric), func(ctx context.Context, s repository.Store) (any, error) {
+						return s.GetPivotCells(ctx, constants.DimensionMonth, constants.DimensionCategory, metric, dateRange, filter)
+					}},
+				)
+			}
+		}
+	}
+	// the trend compares two ranges, each read from the rollup
+	queries = append(queries, storeQuery{"GetProductSalesComparison", func(ctx context.Context, s repository.Store) (any, error) {
+		return s.GetProductSalesComparison(ctx, baseline, recent, models.SalesFilter{})
+	}})
+	return queries
+}
+
+// orderItemIDs blanks the generated order item ids, which differ between databases.
+var orderItemIDs = regexp.MustCompile(`"OrderItemID":\d+`)
+
+// assertSameResults expects every query to answer the same for tenant id in store as in control,
+// which holds only that tenant's data.
+func assertSameResults(t *testing.T, id string, store, control repository.Store) {
+	t.Helper()
+	ctx := tenantContext(id)
+	for _, query := range isolationQueries(t) {
+		got, err := query.run(ctx, store)
+		if err != nil {
+			t.Errorf("tenant %s %s: %v", id, query.name, err)
+			continue
+		}
+		want, err := query.run(ctx, control)
+		if err != nil {
+			t.Errorf("tenant %s %s on its own data: %v", id, query.name, err)
+			continue
+		}
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		if g, w := orderItemIDs.ReplaceAllString(string(gotJSON), ""), orderItemIDs.ReplaceAllString(string(wantJSON), ""); g != w {
+			t.Errorf("tenant %s %s sees other tenants' data:\n got %s\nwant %s", id, query.name, g, w)
+		}
+	}
+}
+
+// TestTenantIsolation imports two tenants into one database and expects each to read exactly what
+// it reads from a database holding only its own data, before and after the other tenant writes.
+func TestTenantIsolation(t *testing.T) {
+	store := newSQLStore(t)
+	refreshTenant(t, store, "a")
+	refreshTenant(t, store, "b")
+
+	controls := map[string]repository.Store{}
+	for _, id := range []string{"a", "b"} {
+		controls[id] = newSQLStore(t)
+		refreshTenant(t, controls[id], id)
+		assertSameResults(t, id, store, controls[id])
+	}
+
+	// tenant b cannot reach the entities only tenant a has
+	b := tenantContext("b")
+	if _, err := store.GetProduct(b, "P2"); !errors.Is(err, constants.ErrProductNotFound) {
+		t.Errorf("GetProduct of another tenant's product: %v", err)
+	}
+	if err := store.UpdateProduct(b, models.Product{ProductID: "P2", ProductName: "Taken", UnitPrice: 1}); !errors.Is(err, constants.ErrProductNotFound) {
+		t.Errorf("UpdateProduct of another tenant's product: %v", err)
+	}
+	if err := store.DeleteProduct(b, "P4"); !errors.Is(err, constants.ErrProductNotFound) {
+		t.Errorf("DeleteProduct of another tenant's product: %v", err)
+	}
+	if _, err := store.GetCustomer(b, "C2"); !errors.Is(err, constants.ErrCustomerNotFound) {
+		t.Errorf("GetCustomer of another tenant's customer: %v", err)
+	}
+	if err := store.UpdateCustomer(b, models.Customer{CustomerID: "C2", CustomerName: "Taken"}); !errors.Is(err, constants.ErrCustomerNotFound) {
+		t.Errorf("UpdateCustomer of another tenant's customer: %v", err)
+	}
+	if err := store.DeleteCustomer(b, "C2"); !errors.Is(err, constants.ErrCustomerNotFound) {
+		t.Errorf("DeleteCustomer of another tenant's customer: %v", err)
+	}
+	if _, err := store.GetOrder(b, "1002"); !errors.Is(err, constants.ErrOrderNotFound) {
+		t.Errorf("GetOrder of another tenant's order: %v", err)
+	}
+	if err := store.UpdateOrder(b, models.Order{OrderID: "1002", CustomerID: "C1", DateOfSale: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}); !errors.Is(err, constants.ErrOrderNotFound) {
+		t.Errorf("UpdateOrder of another tenant's order: %v", err)
+	}
+	if err := store.DeleteOrder(b, "1003"); !errors.Is(err, constants.ErrOrderNotFound) {
+		t.Errorf("DeleteOrder of another tenant's order: %v", err)
+	}
+
+	// nor the items of tenant a's order 1001 through its own order 1001
+	itemsA, err := store.ListOrderItems(tenantContext("a"), "1001")
+	if err != nil || len(itemsA) != 1 {
+		t.Fatalf("tenant a items of order 1001: %v, %v", itemsA, err)
+	}
+	itemA := itemsA[0]
+	if _, err := store.GetOrderItem(b, "1001", itemA.OrderItemID); !errors.Is(err, constants.ErrOrderItemNotFound) {
+		t.Errorf("GetOrderItem of another tenant's item: %v", err)
+	}
+	if err := store.UpdateOrderItem(b, models.OrderItem{OrderItemID: itemA.OrderItemID, OrderID: "1001", ProductID: "P1", QuantitySold: 99}); !errors.Is(err, constants.ErrOrderItemNotFound) {
+		t.Errorf("UpdateOrderItem of another tenant's item: %v", err)
+	}
+	if err := store.DeleteOrderItem(b, "1001", itemA.OrderItemID); !errors.Is(err, constants.ErrOrderItemNotFound) {
+		t.Errorf("DeleteOrderItem of another tenant's item: %v", err)
+	}
+
+	// writes of tenant b to the ids both tenants use, each refreshing rollup dates tenant a sold on,
+	// leave tenant a's data alone
+	writes := []struct {
+		name  string
+		write func(store repository.Store) error
+	}{
+		{"UpdateProduct", func(s repository.Store) error {
+			return s.UpdateProduct(b, models.Product{ProductID: "P1", ProductName: "Phone X2", Category: "Phones", UnitPrice: 950})
+		}},
+		{"UpdateCustomer", func(s repository.Store) error {
+			return s.UpdateCustomer(b, models.Customer{CustomerID: "C1", CustomerName: "Cat Baker-Smith"})
+		}},
+		{"UpdateOrder", func(s repository.Store) error {
+			return s.UpdateOrder(b, models.Order{OrderID: "1001", CustomerID: "C3", DateOfSale: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), PaymentMethod: "PayPal", Region: "Europe"})
+		}},
+		{"CreateOrder", func(s repository.Store) error {
+			return s.CreateOrder(b, models.Order{OrderID: "1002", CustomerID: "C1", DateOfSale: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), PaymentMethod: "PayPal", Region: "Asia"},
+				[]models.OrderItem{{ProductID: "P5", QuantitySold: 2}})
+		}},
+		{"DeleteOrder", func(s repository.Store) error { return s.DeleteOrder(b, "1007") }},
+	}
+	for _, write := range writes {
+		if err := write.write(store); err != nil {
+			t.Fatalf("tenant b %s: %v", write.name, err)
+		}
+		if err := write.write(controls["b"]); err != nil {
+			t.Fatalf("tenant b %s on its own data: %v", write.name, err)
+		}
+	}
+	for _, id := range []string{"a", "b"} {
+		assertSameResults(t, id, store, controls[id])
+	}
+}
+
+// TestStatementsRequireTenant expects reads and writes without a tenant to fail instead of
+// reaching every tenant's data.
+func TestStatementsRequireTenant(t *testing.T) {
+	store := newSQLStore(t)
+	refreshTenant(t, store, "a")
+
+	ctx := context.Background()
+	if _, _, err := store.ListProducts(ctx, models.PageRequest{Limit: 10}); !errors.Is(err, tenant.ErrNoTenant) {
+		t.Errorf("ListProducts without a tenant: %v", err)
+	}
+	if _, err := store.GetTopProductsOverall(ctx, 10, models.DateRange{}, models.SalesFilter{}); !errors.Is(err, tenant.ErrNoTenant) {
+		t.Errorf("GetTopProductsOverall without a tenant: %v", err)
+	}
+	if err := store.DeleteOrder(ctx, "1001"); !errors.Is(err, tenant.ErrNoTenant) {
+		t.Errorf("DeleteOrder without a tenant: %v", err)
+	}
+}
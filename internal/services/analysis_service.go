@@ -1,20 +1,134 @@
 package services
 
 import (
+	"context"
+	"sales/internal/constants"
 	"sales/internal/models"
 	"sales/internal/repository"
-
-	"gorm.io/gorm"
+	"sort"
 )
 
-func GetTopProductsOverall(db *gorm.DB, n int, startDate string, endDate string) ([]models.Product, error) {
-	return repository.GetTopProductsOverall(db, n, startDate, endDate)
+// AnalyticsService computes the analytics of the API from the aggregates of the analytics
+// repository. Results are cached per tenant until its data changes.
+type AnalyticsService struct {
+	analytics repository.AnalyticsRepository
+	cache     *ResultCache
+}
+
+// NewAnalyticsService returns an analytics service over the given repository.
+func NewAnalyticsService(analytics repository.AnalyticsRepository, cache *ResultCache) *AnalyticsService {
+	return &AnalyticsService{analytics: analytics, cache: cache}
+}
+
+func (s *AnalyticsService) GetTopProductsOverall(ctx context.Context, n int, dateRange models.DateRange, filter models.SalesFilter) ([]models.Product, error) {
+	return cached(ctx, s.cache, "GetTopProductsOverall", func() ([]models.Product, error) {
+		return s.analytics.GetTopProductsOverall(ctx, n, dateRange, filter)
+	}, n, dateRange, filter)
+}
+
+func (s *AnalyticsService) GetTopProductsByCategory(ctx context.Context, n int, dateRange models.DateRange, filter models.SalesFilter) (map[string][]models.Product, error) {
+	return cached(ctx, s.cache, "GetTopProductsByCategory", func() (map[string][]models.Product, error) {
+		return s.analytics.GetTopProductsByCategory(ctx, n, dateRange, filter)
+	}, n, dateRange, filter)
+}
+
+func (s *AnalyticsService) GetTopProductsByRegion(ctx context.Context, n int, dateRange models.DateRange, filter models.SalesFilter) (map[string][]models.Product, error) {
+	return cached(ctx, s.cache, "GetTopProductsByRegion", func() (map[string][]models.Product, error) {
+		return s.analytics.GetTopProductsByRegion(ctx, n, dateRange, filter)
+	}, n, dateRange, filter)
+}
+
+func (s *AnalyticsService) GetSalesByPeriod(ctx context.Context, interval string, productID string, dateRange models.DateRange, filter models.SalesFilter) ([]models.SalesBucket, error) {
+	return cached(ctx, s.cache, "GetSalesByPeriod", func() ([]models.SalesBucket, error) {
+		return s.analytics.GetSalesByPeriod(ctx, interval, productID, dateRange, filter)
+	}, interval, productID, dateRange, filter)
+}
+
+func (s *AnalyticsService) GetProductSalesTotals(ctx context.Context, productIDs []string, dateRange models.DateRange, filter models.SalesFilter) ([]models.ProductSalesTotal, error) {
+	return cached(ctx, s.cache, "GetProductSalesTotals", func() ([]models.ProductSalesTotal, error) {
+		return s.analytics.GetProductSalesTotals(ctx, productIDs, dateRange, filter)
+	}, productIDs, dateRange, filter)
 }
 
-func GetTopProductsByCategory(db *gorm.DB, n int, startDate string, endDate string) (map[string][]models.Product, error) {
-	return repository.GetTopProductsByCategory(db, n, startDate, endDate)
+// GetTrendingProducts ranks products by growth of quantity sold in the date range against the
+// window of equal length immediately before it. Products below minVolume in the window being ranked
+// on are ignored so that tiny SKUs don't dominate either list.
+func (s *AnalyticsService) GetTrendingProducts(ctx context.Context, n int, minVolume int, dateRange models.DateRange, filter models.SalesFilter) (models.TrendingProducts, error) {
+	return cached(ctx, s.cache, "GetTrendingProducts", func() (models.TrendingProducts, error) {
+		return s.getTrendingProducts(ctx, n, minVolume, dateRange, filter)
+	}, n, minVolume, dateRange, filter)
 }
 
-func GetTopProductsByRegion(db *gorm.DB, n int, startDate string, endDate string) (map[string][]models.Product, error) {
-	return repository.GetTopProductsByRegion(db, n, startDate, endDate)
+func (s *AnalyticsService) getTrendingProducts(ctx context.Context, n int, minVolume int, dateRange models.DateRange, filter models.SalesFilter) (models.TrendingProducts, error) {
+	windowDays := int(dateRange.To.Sub(dateRange.From).Hours()/24 + 0.5)
+	baseline := models.DateRange{
+		From:     dateRange.From.AddDate(0, 0, -windowDays),
+		To:       dateRange.From,
+		Location: dateRange.Location,
+	}
+
+	// windows are reported as inclusive dates, like start_date and end_date
+	trending := models.TrendingProducts{
+		BaselineStart: baseline.From.Format(constants.DateFormat),
+		BaselineEnd:   baseline.To.AddDate(0, 0, -1).Format(constants.DateFormat),
+		RecentStart:   dateRange.From.Format(constants.DateFormat),
+		RecentEnd:     dateRange.To.AddDate(0, 0, -1).Format(constants.DateFormat),
+		MinVolume:     minVolume,
+	}
+
+	results, err := s.analytics.GetProductSalesComparison(ctx, baseline, dateRange, filter)
+	if err != nil {
+		return models.TrendingProducts{}, err
+	}
+
+	var risers, decliners []models.ProductTrend
+	for _, res := range results {
+		trend := models.ProductTrend{
+			Product: models.Product{
+				ProductID:   res.ProductID,
+				ProductName: res.ProductName,
+				Category:    res.Category,
+				UnitPrice:   res.UnitPrice,
+			},
+			RecentQuantity:   res.RecentQuantity,
+			BaselineQuantity: res.BaselineQuantity,
+		}
+		if res.BaselineQuantity > 0 {
+			growth := float64(res.RecentQuantity-res.BaselineQuantity) / float64(res.BaselineQuantity)
+			trend.GrowthRate = &growth
+		}
+
+		switch {
+		case res.RecentQuantity > res.BaselineQuantity && res.RecentQuantity >= minVolume:
+			risers = append(risers, trend)
+		case res.RecentQuantity < res.BaselineQuantity && res.BaselineQuantity >= minVolume:
+			decliners = append(decliners, trend)
+		}
+	}
+
+	// new products (no baseline) rank above every growth rate, ties are broken by volume
+	sort.SliceStable(risers, func(i, j int) bool {
+		a, b := risers[i], risers[j]
+		if (a.GrowthRate == nil) != (b.GrowthRate == nil) {
+			return a.GrowthRate == nil
+		}
+		if a.GrowthRate != nil && *a.GrowthRate != *b.GrowthRate {
+			return *a.GrowthRate > *b.GrowthRate
+		}
+		return a.RecentQuantity > b.RecentQuantity
+	})
+	sort.SliceStable(decliners, func(i, j int) bool {
+		a, b := decliners[i], decliners[j]
+		if *a.GrowthRate != *b.GrowthRate {
+			return *a.GrowthRate < *b.GrowthRate
+		}
+		return a.BaselineQuantity > b.BaselineQuantity
+	})
+
+	trending.Trending = make([]models.ProductTrend, 0, n)
+	trending.Trending = append(trending.Trending, risers[:min(n, len(risers))]...)
+	trending.Decliners = make([]models.ProductTrend, 0, n)
+	trending.Decliners = append(trending.Decliners, decliners[:min(n, len(decliners))]...)
+
+	return trending, nil
 }
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"fmt"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"testing"
+)
+
+func TestGetTopProducts(t *testing.T) {
+	forEachStore(t, func(t *testing.T, svc *Services, _ repository.Store) {
+		all := dateRange(t, "2023-01-01", "2024-12-31")
+
+		top, err := svc.Analytics.GetTopProductsOverall(testContext, 3, all, models.SalesFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// P123, P456 and P789 sold 3 each, P234 only 1
+		if got := fmt.Sprint(productIDs(top)); got != "[P123 P456 P789]" {
+			t.Errorf("top products %s, want [P123 P456 P789]", got)
+		}
+
+		byCategory, err := svc.Analytics.GetTopProductsByCategory(testContext, 1, all, models.SalesFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for category, want := range map[string]string{"Clothing": "P789", "Electronics": "P456", "Shoes": "P123"} {
+			if got := productIDs(byCategory[category]); len(got) != 1 || got[0] != want {
+				t.Errorf("top product of %s %v, want %s", category, got, want)
+			}
+		}
+
+		europe, err := svc.Analytics.GetTopProductsByRegion(testContext, 5, all, models.SalesFilter{Regions: []string{"Europe"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(europe) != 1 || fmt.Sprint(productIDs(europe["Europe"])) != "[P456]" {
+			t.Errorf("top products in Europe %v, want only P456", europe)
+		}
+	})
+}
+
+func TestGetTrendingProducts(t *testing.T) {
+	forEachStore(t, func(t *testing.T, svc *Services, _ repository.Store) {
+
+		trending, err := svc.Analytics.GetTrendingProducts(testContext, 5, 1, dateRange(t, "2024-03-01", "2024-05-31"), models.SalesFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if trending.BaselineStart != "2023-11-30" || trending.BaselineEnd != "2024-02-29" {
+			t.Errorf("baseline %s to %s, want the 92 days before the range", trending.BaselineStart, trending.BaselineEnd)
+		}
+
+		var risers, decliners []string
+		for _, trend := range trending.Trending {
+			risers = append(risers, trend.ProductID)
+		}
+		for _, trend := range trending.Decliners {
+			decliners = append(decliners, trend.ProductID)
+		}
+		// P234 is new and ranks first; P456 doubled; P789 stopped selling; P123 halved
+		if fmt.Sprint(risers) != "[P234 P456]" || fmt.Sprint(decliners) != "[P789 P123]" {
+			t.Errorf("risers %v and decliners %v, want [P234 P456] and [P789 P123]", risers, decliners)
+		}
+		if growth := trending.Trending[1].GrowthRate; growth == nil || *growth != 1 {
+			t.Errorf("P456 growth %v, want 1", growth)
+		}
+		if trending.Trending[0].GrowthRate != nil {
+			t.Error("a new product has a growth rate")
+		}
+	})
+}
+
+// TestResultsAreCached expects analytics to be answered from the cache until the tenant's results
+// are invalidated.
+func TestResultsAreCached(t *testing.T) {
+	forEachStore(t, func(t *testing.T, svc *Services, store repository.Store) {
+		all := dateRange(t, "2023-01-01", "2024-12-31")
+
+		before, err := svc.Analytics.GetTopProductsOverall(testContext, 1, all, models.SalesFilter{Categories: []string{"Shoes", "Clothing"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// a write behind the services' back isn't seen, and reordered filter values share the entry
+		if err := store.DeleteOrder(testContext, "1001"); err != nil {
+			t.Fatal(err)
+		}
+		cached, err := svc.Analytics.GetTopProductsOverall(testContext, 1, all, models.SalesFilter{Categories: []string{"Clothing", "Shoes"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(productIDs(cached)) != fmt.Sprint(productIDs(before)) {
+			t.Errorf("cached top products %v, want %v", productIDs(cached), productIDs(before))
+		}
+
+		svc.Cache.Invalidate(testContext)
+		after, err := svc.Analytics.GetTopProductsOverall(testContext, 1, all, models.SalesFilter{Categories: []string{"Clothing", "Shoes"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(productIDs(after)) != "[P789]" {
+			t.Errorf("top product after invalidation %v, want P789", productIDs(after))
+		}
+	})
+}
+
+func TestTagChangesWithTheData(t *testing.T) {
+	forEachStore(t, func(t *testing.T, svc *Services, _ repository.Store) {
+
+		tag, ok := svc.Cache.Tag(testContext, "GET /v1/top-products/overall?n=5")
+		if !ok {
+			t.Fatal("no tag")
+		}
+		if other, _ := svc.Cache.Tag(testContext, "GET /v1/top-products/overall?n=6"); other == tag {
+			t.Error("different requests share a tag")
+		}
+		if err := svc.Entities.DeleteOrder(testContext, "1001"); err != nil {
+			t.Fatal(err)
+		}
+		if after, _ := svc.Cache.Tag(testContext, "GET /v1/top-products/overall?n=5"); after == tag {
+			t.Error("the tag survived a write")
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"sales/internal/models"
+	"sales/internal/tenant"
+	"sales/pkg/cache"
+	"slices"
+	"strconv"
+)
+
+// ResultCache holds analytics results per tenant. Each tenant is a cache namespace, so a write to
+// its data invalidates its results only.
+type ResultCache struct {
+	store cache.Store
+}
+
+// NewResultCache caches results in store, in process or shared with other instances through Redis.
+func NewResultCache(store cache.Store) *ResultCache {
+	return &ResultCache{store: store}
+}
+
+// Invalidate drops the cached results of the tenant in ctx. Failures are logged: results then
+// stay stale until they expire.
+func (c *ResultCache) Invalidate(ctx context.Context) {
+	t, ok := tenant.FromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := c.store.Invalidate(ctx, t.ID); err != nil {
+		log.Printf("Cache invalidation failed for tenant %s: %v", t.ID, err)
+	}
+}
+
+// Tag returns an entity tag for request, a canonical description of an analytics request, that
+// changes whenever the data of the tenant in ctx does. ok is false when no tag can be given.
+func (c *ResultCache) Tag(ctx context.Context, request string) (tag string, ok bool) {
+	t, ok := tenant.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	generation, err := c.store.Generation(ctx, t.ID)
+	if err != nil {
+		log.Printf("Cache generation lookup failed for tenant %s: %v", t.ID, err)
+		return "", false
+	}
+	return `"` + digest(t.ID, strconv.FormatInt(generation, 10), request) + `"`, true
+}
+
+// cached returns the result of load for the named query and its arguments, from c when the
+// tenant's data is unchanged since it was stored. The cache is bypassed when it fails.
+func cached[T any](ctx context.Context, c *ResultCache, name string, load func() (T, error), args ...any) (T, error) {
+	t, ok := tenant.FromContext(ctx)
+	if !ok {
+		return load()
+	}
+
+	key, err := c.key(ctx, t.ID, name, args)
+	if err != nil {
+		log.Printf("Cache key failed for %s: %v", name, err)
+		return load()
+	}
+	if data, hit, err := c.store.Get(ctx, key); err != nil {
+		log.Printf("Cache read failed for %s: %v", name, err)
+	} else if hit {
+		var result T
+		if err := json.Unmarshal(data, &result); err == nil {
+			return result, nil
+		}
+	}
+
+	result, err := load()
+	if err != nil {
+		return result, err
+	}
+	if data, err := json.Marshal(result); err != nil {
+		log.Printf("Cache encoding failed for %s: %v", name, err)
+	} else if err := c.store.Set(ctx, key, data); err != nil {
+		log.Printf("Cache write failed for %s: %v", name, err)
+	}
+	return result, nil
+}
+
+// key identifies a query within the current generation of the tenant's data.
+func (c *ResultCache) key(ctx context.Context, tenantID string, name string, args []any) (string, error) {
+	generation, err := c.store.Generation(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+	normalized := make([]any, len(args))
+	for i, arg := range args {
+		normalized[i] = normalizeArg(arg)
+	}
+	encoded, err := json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+	return "result:" + tenantID + ":" + strconv.FormatInt(generation, 10) + ":" + name + ":" + digest(string(encoded)), nil
+}
+
+// normalizeArg makes equivalent arguments encode identically: date ranges by their instants and
+// time zone, filters and id lists regardless of order.
+func normalizeArg(arg any) any {
+	switch v := arg.(type) {
+	case models.DateRange:
+		location := ""
+		if v.Location != nil {
+			location = v.Location.String()
+		}
+		return []string{v.String(), location}
+	case models.SalesFilter:
+		v.Categories = sortedCopy(v.Categories)
+		v.Regions = sortedCopy(v.Regions)
+		v.PaymentMethods = sortedCopy(v.PaymentMethods)
+		v.CustomerIDs = sortedCopy(v.CustomerIDs)
+		return v
+	case []string:
+		return sortedCopy(v)
+	default:
+		return arg
+	}
+}
+
+func sortedCopy(values []string) []string {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// digest hashes parts, separated so that different splits of the same text differ.
+func digest(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// invalidateOnSuccess drops the cached results of the tenant in ctx once a write succeeded.
+func (c *ResultCache) invalidateOnSuccess(ctx context.Context, err error) error {
+	if err == nil {
+		c.Invalidate(ctx)
+	}
+	return err
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"sales/internal/models"
+)
+
+// GetNewVsReturningCustomers splits revenue per period between first-time and repeat customers.
+// RepeatPurchaseRate is the share of the period's active customers that had ordered in an earlier period.
+func (s *AnalyticsService) GetNewVsReturningCustomers(ctx context.Context, interval string, dateRange models.DateRange, filter models.SalesFilter) ([]models.CustomerRetentionPeriod, error) {
+	return cached(ctx, s.cache, "GetNewVsReturningCustomers", func() ([]models.CustomerRetentionPeriod, error) {
+		return s.getNewVsReturningCustomers(ctx, interval, dateRange, filter)
+	}, interval, dateRange, filter)
+}
+
+func (s *AnalyticsService) getNewVsReturningCustomers(ctx context.Context, interval string, dateRange models.DateRange, filter models.SalesFilter) ([]models.CustomerRetentionPeriod, error) {
+	rows, err := s.analytics.GetCustomerSegmentsByPeriod(ctx, interval, dateRange, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// rows are ordered by period, so consecutive rows belong to the same period
+	periods := make([]models.CustomerRetentionPeriod, 0, len(rows))
+	for _, row := range rows {
+		if len(periods) == 0 || periods[len(periods)-1].Period != row.Period {
+			periods = append(periods, models.CustomerRetentionPeriod{Period: row.Period})
+		}
+		period := &periods[len(periods)-1]
+
+		if row.Segment == "new" {
+			period.NewCustomers = row.Customers
+			period.NewOrders = row.Orders
+			period.NewRevenue = row.Revenue
+		} else {
+			period.ReturningCustomers = row.Customers
+			period.ReturningOrders = row.Orders
+			period.ReturningRevenue = row.Revenue
+		}
+	}
+
+	for i := range periods {
+		if active := periods[i].NewCustomers + periods[i].ReturningCustomers; active > 0 {
+			periods[i].RepeatPurchaseRate = float64(periods[i].ReturningCustomers) / float64(active)
+		}
+	}
+
+	return periods, nil
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"testing"
+)
+
+func TestGetNewVsReturningCustomers(t *testing.T) {
+	forEachStore(t, func(t *testing.T, svc *Services, _ repository.Store) {
+
+		periods, err := svc.Analytics.GetNewVsReturningCustomers(testContext, constants.IntervalMonth, dateRange(t, "2023-12-01", "2024-05-31"), models.SalesFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// C456 first ordered in December, C789 in January and C101 in March; each came back later
+		want := []struct {
+			period              string
+			newCustomers, later int
+		}{
+			{"2023-12-01", 1, 0},
+			{"2024-01-01", 1, 0},
+			{"2024-02-01", 0, 1},
+			{"2024-03-01", 1, 0},
+			{"2024-04-01", 0, 1},
+			{"2024-05-01", 0, 1},
+		}
+		if len(periods) != len(want) {
+			t.Fatalf("periods %+v, want %d", periods, len(want))
+		}
+		for i, period := range periods {
+			w := want[i]
+			if period.Period != w.period || period.NewCustomers != w.newCustomers || period.ReturningCustomers != w.later {
+				t.Errorf("period %d = %+v, want %+v", i, period, w)
+			}
+			if rate := float64(w.later) / float64(w.newCustomers+w.later); period.RepeatPurchaseRate != rate {
+				t.Errorf("%s repeat purchase rate %v, want %v", period.Period, period.RepeatPurchaseRate, rate)
+			}
+		}
+		if revenue := periods[0].NewRevenue; revenue < 323.99 || revenue > 324.01 {
+			t.Errorf("December new customer revenue %v, want 2 × 180 less 10%%", revenue)
+		}
+	})
+}
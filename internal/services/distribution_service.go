@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"sales/internal/models"
+	"sales/pkg/stats"
+	"sort"
+)
+
+// GetDistributionStatistics summarizes the order value and quantity-per-line distributions per group.
+func (s *AnalyticsService) GetDistributionStatistics(ctx context.Context, groupBy string, buckets int, dateRange models.DateRange, filter models.SalesFilter) (models.DistributionResult, error) {
+	return cached(ctx, s.cache, "GetDistributionStatistics", func() (models.DistributionResult, error) {
+		return s.getDistributionStatistics(ctx, groupBy, buckets, dateRange, filter)
+	}, groupBy, buckets, dateRange, filter)
+}
+
+func (s *AnalyticsService) getDistributionStatistics(ctx context.Context, groupBy string, buckets int, dateRange models.DateRange, filter models.SalesFilter) (models.DistributionResult, error) {
+	orderValues, err := s.analytics.GetOrderValues(ctx, groupBy, dateRange, filter)
+	if err != nil {
+		return models.DistributionResult{}, err
+	}
+
+	lineQuantities, err := s.analytics.GetLineQuantities(ctx, groupBy, dateRange, filter)
+	if err != nil {
+		return models.DistributionResult{}, err
+	}
+
+	valuesByGroup := groupValues(orderValues)
+	quantitiesByGroup := groupValues(lineQuantities)
+
+	keys := make([]string, 0, len(valuesByGroup))
+	for key := range valuesByGroup {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := models.DistributionResult{GroupBy: groupBy, Groups: make([]models.DistributionGroup, 0, len(keys))}
+	for _, key := range keys {
+		result.Groups = append(result.Groups, models.DistributionGroup{
+			Key:             key,
+			OrderValue:      stats.Summarize(valuesByGroup[key], buckets),
+			QuantityPerLine: stats.Summarize(quantitiesByGroup[key], buckets),
+		})
+	}
+
+	return result, nil
+}
+
+func groupValues(values []models.GroupedValue) map[string][]float64 {
+	grouped := make(map[string][]float64)
+	for _, v := range values {
+		grouped[v.GroupKey] = append(grouped[v.GroupKey], v.Value)
+	}
+	return grouped
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"testing"
+)
+
+func TestGetDistributionStatistics(t *testing.T) {
+	forEachStore(t, func(t *testing.T, svc *Services, _ repository.Store) {
+
+		result, err := svc.Analytics.GetDistributionStatistics(testContext, constants.DimensionPaymentMethod, 4, dateRange(t, "2023-01-01", "2024-12-31"), models.SalesFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Groups) != 3 || result.Groups[0].Key != "Credit Card" || result.Groups[1].Key != "Debit Card" || result.Groups[2].Key != "PayPal" {
+			t.Fatalf("groups %+v, want one per payment method in order", result.Groups)
+		}
+
+		paypal := result.Groups[2]
+		if paypal.OrderValue.Count != 2 || paypal.OrderValue.Max != 1299 || paypal.OrderValue.Min < 297.49 || paypal.OrderValue.Min > 297.50 {
+			t.Errorf("PayPal order values %+v, want 297.49 and 1299", paypal.OrderValue)
+		}
+		if len(paypal.OrderValue.Histogram) != 4 {
+			t.Errorf("%d histogram buckets, want 4", len(paypal.OrderValue.Histogram))
+		}
+		if quantities := result.Groups[1].QuantityPerLine; quantities.Count != 2 || quantities.Min != 2 || quantities.Max != 3 {
+			t.Errorf("debit card quantities per line %+v, want 2 and 3", quantities)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"sales/internal/models"
+	"sales/internal/repository"
+)
+
+// EntityService manages products, customers and orders. Writes drop the cached analytics results
+// of the tenant whose data they change.
+type EntityService struct {
+	products  repository.ProductRepository
+	customers repository.CustomerRepository
+	orders    repository.OrderRepository
+	cache     *ResultCache
+}
+
+// NewEntityService returns an entity service over the given repositories.
+func NewEntityService(products repository.ProductRepository, customers repository.CustomerRepository, orders repository.OrderRepository, cache *ResultCache) *EntityService {
+	return &EntityService{products: products, customers: customers, orders: orders, cache: cache}
+}
+
+func (s *EntityService) ListProducts(ctx context.Context, page models.PageRequest) ([]models.Product, int64, error) {
+	return s.products.ListProducts(ctx, page)
+}
+
+func (s *EntityService) ListCustomers(ctx context.Context, page models.PageRequest) ([]models.Customer, int64, error) {
+	return s.customers.ListCustomers(ctx, page)
+}
+
+func (s *EntityService) ListOrders(ctx context.Context, page models.PageRequest) ([]models.Order, int64, error) {
+	return s.orders.ListOrders(ctx, page)
+}
+
+func (s *EntityService) GetProduct(ctx context.Context, productID string) (models.Product, error) {
+	return s.products.GetProduct(ctx, productID)
+}
+
+func (s *EntityService) CreateProduct(ctx context.Context, product models.Product) error {
+	return s.cache.invalidateOnSuccess(ctx, s.products.CreateProduct(ctx, product))
+}
+
+func (s *EntityService) UpdateProduct(ctx context.Context, product models.Product) error {
+	return s.cache.invalidateOnSuccess(ctx, s.products.UpdateProduct(ctx, product))
+}
+
+func (s *EntityService) DeleteProduct(ctx context.Context, productID string) error {
+	return s.cache.invalidateOnSuccess(ctx, s.products.DeleteProduct(ctx, productID))
+}
+
+func (s *EntityService) GetCustomer(ctx context.Context, customerID string) (models.Customer, error) {
+	return s.customers.GetCustomer(ctx, customerID)
+}
+
+func (s *EntityService) CreateCustomer(ctx context.Context, customer models.Customer) error {
+	return s.cache.invalidateOnSuccess(ctx, s.customers.CreateCustomer(ctx, customer))
+}
+
+func (s *EntityService) UpdateCustomer(ctx context.Context, customer models.Customer) error {
+	return s.cache.invalidateOnSuccess(ctx, s.customers.UpdateCustomer(ctx, customer))
+}
+
+func (s *EntityService) DeleteCustomer(ctx context.Context, customerID string) error {
+	return s.cache.invalidateOnSuccess(ctx, s.customers.DeleteCustomer(ctx, customerID))
+}
+
+// CreateOrder creates an order with its items and returns it as stored.
+func (s *EntityService) CreateOrder(ctx context.Context, order models.Order, items []models.OrderItem) (models.OrderDetails, error) {
+	if err := s.cache.invalidateOnSuccess(ctx, s.orders.CreateOrder(ctx, order, items)); err != nil {
+		return models.OrderDetails{}, err
+	}
+	return s.orders.GetOrder(ctx, order.OrderID)
+}
+
+func (s *EntityService) GetOrder(ctx context.Context, orderID string) (models.OrderDetails, error) {
+	return s.orders.GetOrder(ctx, orderID)
+}
+
+// UpdateOrder replaces an order and returns it as stored.
+func (s *EntityService) UpdateOrder(ctx context.Context, order models.Order) (models.OrderDetails, error) {
+	if err := s.cache.invalidateOnSuccess(ctx, s.orders.UpdateOrder(ctx, order)); err != nil {
+		return models.OrderDetails{}, err
+	}
+	return s.orders.GetOrder(ctx, order.OrderID)
+}
+
+func (s *EntityService) DeleteOrder(ctx context.Context, orderID string) error {
+	return s.cache.invalidateOnSuccess(ctx, s.orders.DeleteOrder(ctx, orderID))
+}
+
+func (s *EntityService) ListOrderItems(ctx context.Context, orderID string) ([]models.OrderItem, error) {
+	return s.orders.ListOrderItems(ctx, orderID)
+}
+
+// CreateOrderItem adds an item to an order and returns it as stored.
+func (s *EntityService) CreateOrderItem(ctx context.Context, item models.OrderItem) (models.OrderItem, error) {
+	if err := s.cache.invalidateOnSuccess(ctx, s.orders.CreateOrderItem(ctx, &item)); err != nil {
+		return models.OrderItem{}, err
+	}
+	return s.orders.GetOrderItem(ctx, item.OrderID, item.OrderItemID)
+}
+
+// UpdateOrderItem replaces an item of an order and returns it as stored.
+func (s *EntityService) UpdateOrderItem(ctx context.Context, item models.OrderItem) (models.OrderItem, error) {
+	if err := s.cache.invalidateOnSuccess(ctx, s.orders.UpdateOrderItem(ctx, item)); err != nil {
+		return models.OrderItem{}, err
+	}
+	return s.orders.GetOrderItem(ctx, item.OrderID, item.OrderItemID)
+}
+
+func (s *EntityService) DeleteOrderItem(ctx context.Context, orderID string, orderItemID uint) error {
+	return s.cache.invalidateOnSuccess(ctx, s.orders.DeleteOrderItem(ctx, orderID, orderItemID))
+}
+
+func (s *EntityService) GetProductsByIDs(ctx context.Context, productIDs []string) ([]models.Product, error) {
+	return s.products.GetProductsByIDs(ctx, productIDs)
+}
+
+func (s *EntityService) GetCustomersByIDs(ctx context.Context, customerIDs []string) ([]models.Customer, error) {
+	return s.customers.GetCustomersByIDs(ctx, customerIDs)
+}
+
+func (s *EntityService) GetOrdersByIDs(ctx context.Context, orderIDs []string) ([]models.Order, error) {
+	return s.orders.GetOrdersByIDs(ctx, orderIDs)
+}
+
+func (s *EntityService) ListOrdersByCustomers(ctx context.Context, customerIDs []string) ([]models.Order, error) {
+	return s.orders.ListOrdersByCustomers(ctx, customerIDs)
+}
+
+func (s *EntityService) ListOrderItemsByOrders(ctx context.Context, orderIDs []string) ([]models.OrderItem, error) {
+	return s.orders.ListOrderItemsByOrders(ctx, orderIDs)
+}
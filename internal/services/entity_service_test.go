@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"testing"
+	"time"
+)
+
+func TestProductLifecycle(t *testing.T) {
+	forEachStore(t, func(t *testing.T, svc *Services, _ repository.Store) {
+		product := models.Product{ProductID: "P900", ProductName: "Trail Socks", Category: "Clothing", UnitPrice: 9.5}
+
+		if err := svc.Entities.CreateProduct(testContext, product); err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.Entities.CreateProduct(testContext, product); !errors.Is(err, constants.ErrProductExists) {
+			t.Errorf("creating a product twice: %v", err)
+		}
+		product.UnitPrice = 11
+		if err := svc.Entities.UpdateProduct(testContext, product); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := svc.Entities.GetProduct(testContext, "P900"); err != nil || got.UnitPrice != 11 {
+			t.Errorf("updated product %+v, %v", got, err)
+		}
+		if err := svc.Entities.DeleteProduct(testContext, "P900"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := svc.Entities.GetProduct(testContext, "P900"); !errors.Is(err, constants.ErrProductNotFound) {
+			t.Errorf("getting a deleted product: %v", err)
+		}
+		if err := svc.Entities.DeleteProduct(testContext, "P123"); !errors.Is(err, constants.ErrProductInUse) {
+			t.Errorf("deleting a sold product: %v", err)
+		}
+	})
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	forEachStore(t, func(t *testing.T, svc *Services, _ repository.Store) {
+		order := models.Order{OrderID: "2001", CustomerID: "C456", DateOfSale: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), PaymentMethod: "PayPal", Region: "Europe"}
+
+		if _, err := svc.Entities.CreateOrder(testContext, models.Order{OrderID: "2002", CustomerID: "C000"}, nil); !errors.Is(err, constants.ErrUnknownCustomer) {
+			t.Errorf("creating an order of an unknown customer: %v", err)
+		}
+		if _, err := svc.Entities.CreateOrder(testContext, order, []models.OrderItem{{ProductID: "P000", QuantitySold: 1}}); !errors.Is(err, constants.ErrUnknownProduct) {
+			t.Errorf("creating an order of an unknown product: %v", err)
+		}
+
+		details, err := svc.Entities.CreateOrder(testContext, order, []models.OrderItem{{OrderID: "2001", ProductID: "P456", QuantitySold: 2}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if details.Customer.CustomerName != "John Smith" || len(details.Items) != 1 || details.Items[0].Product.ProductName != "iPhone 15 Pro" {
+			t.Errorf("created order details %+v", details)
+		}
+
+		item, err := svc.Entities.CreateOrderItem(testContext, models.OrderItem{OrderID: "2001", ProductID: "P789", QuantitySold: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		item.QuantitySold = 4
+		if updated, err := svc.Entities.UpdateOrderItem(testContext, item); err != nil || updated.QuantitySold != 4 {
+			t.Errorf("updated item %+v, %v", updated, err)
+		}
+		if items, err := svc.Entities.ListOrderItems(testContext, "2001"); err != nil || len(items) != 2 {
+			t.Errorf("items %+v, %v", items, err)
+		}
+		if err := svc.Entities.DeleteOrderItem(testContext, "2001", item.OrderItemID); err != nil {
+			t.Fatal(err)
+		}
+
+		order.Region = "Asia"
+		if updated, err := svc.Entities.UpdateOrder(testContext, order); err != nil || updated.Order.Region != "Asia" {
+			t.Errorf("updated order %+v, %v", updated, err)
+		}
+		if err := svc.Entities.DeleteOrder(testContext, "2001"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := svc.Entities.GetOrder(testContext, "2001"); !errors.Is(err, constants.ErrOrderNotFound) {
+			t.Errorf("getting a deleted order: %v", err)
+		}
+	})
+}
+
+// TestWritesInvalidateResults expects an entity write to drop the tenant's cached analytics.
+func TestWritesInvalidateResults(t *testing.T) {
+	forEachStore(t, func(t *testing.T, svc *Services, _ repository.Store) {
+		all := dateRange(t, "2023-01-01", "2024-12-31")
+
+		if _, err := svc.Analytics.GetTopProductsOverall(testContext, 1, all, models.SalesFilter{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.Entities.UpdateProduct(testContext, models.Product{ProductID: "P123", ProductName: "UltraBoost 2", Category: "Shoes", UnitPrice: 190}); err != nil {
+			t.Fatal(err)
+		}
+		top, err := svc.Analytics.GetTopProductsOverall(testContext, 1, all, models.SalesFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(top) != 1 || top[0].ProductName != "UltraBoost 2" {
+			t.Errorf("top product after renaming %+v, want the new name", top)
+		}
+	})
+}
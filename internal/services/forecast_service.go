@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/pkg/forecast"
+	"time"
+)
+
+// GetSalesForecast projects weekly quantity sold for a product and/or the filtered sales over the next horizon weeks.
+// When method is empty every supported method is run so the caller can compare back-test errors.
+// Either side of the date range may be open to use all available history.
+func (s *AnalyticsService) GetSalesForecast(ctx context.Context, productID string, horizon int, method string, dateRange models.DateRange, filter models.SalesFilter) (models.ForecastResult, error) {
+	return cached(ctx, s.cache, "GetSalesForecast", func() (models.ForecastResult, error) {
+		return s.getSalesForecast(ctx, productID, horizon, method, dateRange, filter)
+	}, productID, horizon, method, dateRange, filter)
+}
+
+func (s *AnalyticsService) getSalesForecast(ctx context.Context, productID string, horizon int, method string, dateRange models.DateRange, filter models.SalesFilter) (models.ForecastResult, error) {
+	buckets, err := s.analytics.GetSalesByPeriod(ctx, constants.IntervalWeek, productID, dateRange, filter)
+	if err != nil {
+		return models.ForecastResult{}, err
+	}
+
+	history, err := fillWeeklyGaps(buckets)
+	if err != nil {
+		return models.ForecastResult{}, err
+	}
+
+	series := make([]float64, len(history))
+	for i, bucket := range history {
+		series[i] = float64(bucket.QuantitySold)
+	}
+
+	methods := forecast.Methods
+	if method != "" {
+		methods = []string{method}
+	}
+
+	result := models.ForecastResult{
+		ProductID:  productID,
+		Categories: filter.Categories,
+		Interval:   constants.ForecastInterval,
+		Horizon:    horizon,
+		History:    history,
+		Methods:    make([]models.MethodForecast, 0, len(methods)),
+	}
+
+	cfg := forecast.DefaultConfig()
+	for _, m := range methods {
+		result.Methods = append(result.Methods, runForecastMethod(m, history, series, horizon, cfg))
+	}
+
+	return result, nil
+}
+
+// runForecastMethod produces the forecast and back-test metrics for a single method.
+// Fitting failures are reported on the method instead of failing the whole request.
+func runForecastMethod(method string, history []models.SalesBucket, series []float64, horizon int, cfg forecast.Config) models.MethodForecast {
+	methodForecast := models.MethodForecast{Method: method}
+
+	points, err := forecast.Forecast(method, series, horizon, cfg)
+	if err != nil {
+		methodForecast.Error = err.Error()
+		return methodForecast
+	}
+
+	lastPeriod, _ := time.Parse(constants.DateFormat, history[len(history)-1].Period)
+	methodForecast.Forecast = make([]models.ForecastPoint, 0, len(points))
+	for _, point := range points {
+		methodForecast.Forecast = append(methodForecast.Forecast, models.ForecastPoint{
+			PeriodStart: lastPeriod.AddDate(0, 0, 7*point.Step).Format(constants.DateFormat),
+			Quantity:    point.Value,
+			Lower:       point.Lower,
+			Upper:       point.Upper,
+		})
+	}
+
+	// back-testing needs more history than forecasting, so it is best effort
+	if metrics, err := forecast.Backtest(method, series, horizon, cfg); err == nil {
+		methodForecast.Backtest = &models.BacktestMetrics{MAE: metrics.MAE, MAPE: metrics.MAPE}
+	}
+
+	return methodForecast
+}
+
+// fillWeeklyGaps inserts zero buckets for weeks without sales between the first and last bucket.
+func fillWeeklyGaps(buckets []models.SalesBucket) ([]models.SalesBucket, error) {
+	if len(buckets) == 0 {
+		return []models.SalesBucket{}, nil
+	}
+
+	filled := make([]models.SalesBucket, 0, len(buckets))
+	next, err := time.Parse(constants.DateFormat, buckets[0].Period)
+	if err != nil {
+		return nil, err
+	}
+	for _, bucket := range buckets {
+		period, err := time.Parse(constants.DateFormat, bucket.Period)
+		if err != nil {
+			return nil, err
+		}
+		for next.Before(period) {
+			filled = append(filled, models.SalesBucket{Period: next.Format(constants.DateFormat)})
+			next = next.AddDate(0, 0, 7)
+		}
+		filled = append(filled, bucket)
+		next = period.AddDate(0, 0, 7)
+	}
+
+	return filled, nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"sales/pkg/forecast"
+	"testing"
+)
+
+func TestGetSalesForecast(t *testing.T) {
+	forEachStore(t, func(t *testing.T, svc *Services, _ repository.Store) {
+
+		result, err := svc.Analytics.GetSalesForecast(testContext, "P456", 4, "", dateRange(t, "2024-01-01", "2024-12-31"), models.SalesFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// weekly history from the week of 2024-01-03 to the week of 2024-05-18, gaps filled with zeros
+		history := result.History
+		if len(history) != 20 || history[0].Period != "2024-01-01" || history[len(history)-1].Period != "2024-05-13" {
+			t.Fatalf("history of %d weeks from %s to %s", len(history), history[0].Period, history[len(history)-1].Period)
+		}
+		total := 0
+		for _, bucket := range history {
+			total += bucket.QuantitySold
+		}
+		if total != 3 {
+			t.Errorf("history sold %d, want 3", total)
+		}
+
+		if len(result.Methods) != len(forecast.Methods) {
+			t.Fatalf("%d methods, want every method", len(result.Methods))
+		}
+		for _, method := range result.Methods {
+			if method.Error != "" {
+				continue
+			}
+			if len(method.Forecast) != 4 || method.Forecast[0].PeriodStart != "2024-05-20" {
+				t.Errorf("%s forecast %+v, want 4 weeks from 2024-05-20", method.Method, method.Forecast)
+			}
+		}
+
+		single, err := svc.Analytics.GetSalesForecast(testContext, "P456", 2, forecast.MovingAverage, dateRange(t, "2024-01-01", "2024-12-31"), models.SalesFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(single.Methods) != 1 || single.Methods[0].Method != forecast.MovingAverage {
+			t.Errorf("methods %+v, want only %s", single.Methods, forecast.MovingAverage)
+		}
+	})
+}
+
+func TestFillWeeklyGaps(t *testing.T) {
+	filled, err := fillWeeklyGaps([]models.SalesBucket{{Period: "2024-01-01", QuantitySold: 2}, {Period: "2024-01-22", QuantitySold: 5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(filled); got != "[{2024-01-01 2 0} {2024-01-08 0 0} {2024-01-15 0 0} {2024-01-22 5 0}]" {
+		t.Errorf("filled %s", got)
+	}
+	if empty, err := fillWeeklyGaps(nil); err != nil || len(empty) != 0 {
+		t.Errorf("filling no buckets gave %v, %v", empty, err)
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sort"
+)
+
+// GetPivotTable builds a rows × columns matrix of a metric with row, column and grand totals.
+// With a percentage mode every value, totals included, is expressed as a percentage of its row or column total.
+func (s *AnalyticsService) GetPivotTable(ctx context.Context, rowDimension string, columnDimension string, metric string, percent string, dateRange models.DateRange, filter models.SalesFilter) (models.PivotTable, error) {
+	return cached(ctx, s.cache, "GetPivotTable", func() (models.PivotTable, error) {
+		return s.getPivotTable(ctx, rowDimension, columnDimension, metric, percent, dateRange, filter)
+	}, rowDimension, columnDimension, metric, percent, dateRange, filter)
+}
+
+func (s *AnalyticsService) getPivotTable(ctx context.Context, rowDimension string, columnDimension string, metric string, percent string, dateRange models.DateRange, filter models.SalesFilter) (models.PivotTable, error) {
+	cells, err := s.analytics.GetPivotCells(ctx, rowDimension, columnDimension, metric, dateRange, filter)
+	if err != nil {
+		return models.PivotTable{}, err
+	}
+
+	values := make(map[[2]string]float64)
+	rowTotals := make(map[string]float64)
+	columnTotals := make(map[string]float64)
+	rowLabels := make(map[string]string)
+	columnLabels := make(map[string]string)
+	var grandTotal float64
+	for _, cell := range cells {
+		if cell.RowLabel != "" {
+			rowLabels[cell.RowKey] = cell.RowLabel
+		}
+		if cell.ColumnLabel != "" {
+			columnLabels[cell.ColumnKey] = cell.ColumnLabel
+		}
+		switch {
+		case cell.RowTotal && cell.ColumnTotal:
+			grandTotal = cell.Value
+		case cell.RowTotal:
+			columnTotals[cell.ColumnKey] = cell.Value
+		case cell.ColumnTotal:
+			rowTotals[cell.RowKey] = cell.Value
+		default:
+			values[[2]string{cell.RowKey, cell.ColumnKey}] = cell.Value
+		}
+	}
+
+	table := models.PivotTable{
+		RowDimension:    rowDimension,
+		ColumnDimension: columnDimension,
+		Metric:          metric,
+		Percentage:      percent,
+		Columns:         sortedKeys(columnTotals),
+		GrandTotal:      grandTotal,
+	}
+
+	table.ColumnTotals = make([]float64, len(table.Columns))
+	for i, column := range table.Columns {
+		table.ColumnTotals[i] = columnTotals[column]
+	}
+	if len(columnLabels) > 0 {
+		table.ColumnLabels = make([]string, len(table.Columns))
+		for i, column := range table.Columns {
+			table.ColumnLabels[i] = columnLabels[column]
+		}
+	}
+
+	rowKeys := sortedKeys(rowTotals)
+	table.Rows = make([]models.PivotRow, 0, len(rowKeys))
+	for _, rowKey := range rowKeys {
+		row := models.PivotRow{Key: rowKey, Label: rowLabels[rowKey], Values: make([]float64, len(table.Columns)), Total: rowTotals[rowKey]}
+		for i, column := range table.Columns {
+			row.Values[i] = values[[2]string{rowKey, column}]
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	switch percent {
+	case constants.PercentOfRow:
+		for i := range table.Rows {
+			row := &table.Rows[i]
+			for j := range row.Values {
+				row.Values[j] = percentage(row.Values[j], row.Total)
+			}
+			row.Total = percentage(row.Total, row.Total)
+		}
+		for j := range table.ColumnTotals {
+			table.ColumnTotals[j] = percentage(table.ColumnTotals[j], grandTotal)
+		}
+		table.GrandTotal = percentage(grandTotal, grandTotal)
+	case constants.PercentOfColumn:
+		for i := range table.Rows {
+			row := &table.Rows[i]
+			for j := range row.Values {
+				row.Values[j] = percentage(row.Values[j], columnTotals[table.Columns[j]])
+			}
+			row.Total = percentage(row.Total, grandTotal)
+		}
+		for j := range table.ColumnTotals {
+			table.ColumnTotals[j] = percentage(table.ColumnTotals[j], table.ColumnTotals[j])
+		}
+		table.GrandTotal = percentage(grandTotal, grandTotal)
+	}
+
+	return table, nil
+}
+
+func percentage(value float64, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * value / total
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"fmt"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"testing"
+)
+
+func TestGetPivotTable(t *testing.T) {
+	forEachStore(t, func(t *testing.T, svc *Services, _ repository.Store) {
+		all := dateRange(t, "2023-01-01", "2024-12-31")
+
+		table, err := svc.Analytics.GetPivotTable(testContext, constants.DimensionRegion, constants.DimensionCategory, constants.MetricQuantity, "", all, models.SalesFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fmt.Sprint(table.Columns, table.ColumnTotals, table.GrandTotal); got != "[Clothing Electronics Shoes] [3 4 3] 10" {
+			t.Errorf("columns, totals and grand total %s", got)
+		}
+		want := map[string]string{
+			"Asia":          "[3 2 0] 5",
+			"Europe":        "[0 1 0] 1",
+			"North America": "[0 1 2] 3",
+			"South America": "[0 0 1] 1",
+		}
+		if len(table.Rows) != len(want) {
+			t.Fatalf("%d rows, want %d", len(table.Rows), len(want))
+		}
+		for _, row := range table.Rows {
+			if got := fmt.Sprint(row.Values, " ", row.Total); got != want[row.Key] {
+				t.Errorf("row %s = %s, want %s", row.Key, got, want[row.Key])
+			}
+		}
+
+		percentages, err := svc.Analytics.GetPivotTable(testContext, constants.DimensionRegion, constants.DimensionCategory, constants.MetricQuantity, constants.PercentOfRow, all, models.SalesFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fmt.Sprint(percentages.Rows[0].Values, percentages.Rows[0].Total, percentages.ColumnTotals, percentages.GrandTotal); got != "[60 40 0] 100 [30 40 30] 100" {
+			t.Errorf("row percentages of Asia and column totals %s", got)
+		}
+	})
+}
+
+func TestGetPivotTableLabelsProducts(t *testing.T) {
+	forEachStore(t, func(t *testing.T, svc *Services, _ repository.Store) {
+
+		table, err := svc.Analytics.GetPivotTable(testContext, constants.DimensionRegion, constants.DimensionProduct, constants.MetricOrders, "", dateRange(t, "2023-01-01", "2024-12-31"), models.SalesFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fmt.Sprint(table.Columns); got != "[P123 P234 P456 P789]" {
+			t.Errorf("product columns %s, want their ids", got)
+		}
+		if got := fmt.Sprint(table.ColumnLabels); got != "[UltraBoost Running Shoes Sony WH-1000XM5 Headphones iPhone 15 Pro Levi's 501 Jeans]" {
+			t.Errorf("product column labels %s, want their names", got)
+		}
+		for _, row := range table.Rows {
+			if row.Label != "" {
+				t.Errorf("region row %s has label %q", row.Key, row.Label)
+			}
+		}
+	})
+}
@@ -1,16 +1,27 @@
 package services
 
 import (
+	"context"
 	"encoding/csv"
-	"gorm.io/gorm"
 	"log"
 	"os"
-	"sales/internal/constants"
 	"sales/internal/models"
+	"sales/internal/repository"
 	"sales/internal/utils"
 	"strings"
 )
 
+// RefreshService imports the sales CSV files of tenants.
+type RefreshService struct {
+	orders repository.OrderRepository
+	cache  *ResultCache
+}
+
+// NewRefreshService returns a refresh service importing into the given repository.
+func NewRefreshService(orders repository.OrderRepository, cache *ResultCache) *RefreshService {
+	return &RefreshService{orders: orders, cache: cache}
+}
+
 // loadCSVData reads the CSV file and returns the data as a slice of string slices.
 func loadCSVData(filePath string) ([][]string, error) {
 	file, err := os.Open(filePath)
@@ -127,10 +138,8 @@ func validateAndTransformData(records [][]string) ([]models.Product, []models.Cu
 	return products, customers, orders, orderItems, nil
 }
 
-// RefreshDatabase refreshes the database with data from the CSV file. todo implement batching
-func RefreshDatabase(db *gorm.DB) error {
-	filePath := constants.CSVFilePath
-
+// RefreshDatabase refreshes the data of the tenant in ctx from its CSV file. todo implement batching
+func (s *RefreshService) RefreshDatabase(ctx context.Context, filePath string) error {
 	// Load CSV data
 	records, err := loadCSVData(filePath)
 	if err != nil {
@@ -143,76 +152,6 @@ func RefreshDatabase(db *gorm.DB) error {
 		return err
 	}
 
-	// Begin transaction
-	tx := db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	if err := tx.Error; err != nil {
-		return err
-	}
-
-	// Create or update customers
-	for _, customer := range customers {
-		if err := createOrUpdateCustomer(tx, customer); err != nil {
-			tx.Rollback()
-			return err
-		}
-	}
-
-	// Create or update products
-	for _, product := range products {
-		if err := createOrUpdateProduct(tx, product); err != nil {
-			tx.Rollback()
-			return err
-		}
-	}
-
-	// Create orders and order items
-	for _, order := range orders {
-		if err := createOrder(tx, order); err != nil {
-			tx.Rollback()
-			return err
-		}
-
-		// Find associated order item
-		for _, orderItem := range orderItems {
-			if orderItem.OrderID == order.OrderID {
-				if err := createOrderItem(tx, orderItem); err != nil {
-					tx.Rollback()
-					return err
-				}
-			}
-		}
-	}
-
-	// Commit transaction
-	return tx.Commit().Error
-}
-
-// createOrUpdateCustomer creates or updates a customer in the database.
-func createOrUpdateCustomer(db *gorm.DB, customer models.Customer) error {
-	result := db.FirstOrCreate(&customer, models.Customer{CustomerID: customer.CustomerID})
-	return result.Error
-}
-
-// createOrUpdateProduct creates or updates a product in the database.
-func createOrUpdateProduct(db *gorm.DB, product models.Product) error {
-	result := db.FirstOrCreate(&product, models.Product{ProductID: product.ProductID})
-	return result.Error
-}
-
-// createOrder creates an order in the database.
-func createOrder(db *gorm.DB, order models.Order) error {
-	result := db.Create(&order)
-	return result.Error
-}
-
-// createOrderItem creates an order item in the database.
-func createOrderItem(db *gorm.DB, orderItem models.OrderItem) error {
-	result := db.Create(&orderItem)
-	return result.Error
+	// Store it, then drop the results computed from the old data
+	return s.cache.invalidateOnSuccess(ctx, s.orders.ImportSales(ctx, customers, products, orders, orderItems))
 }
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/pkg/cache"
+	"testing"
+)
+
+func TestValidateAndTransformDataSkipsInvalidRecords(t *testing.T) {
+	header := []string{"Order ID", "Product ID", "Customer ID", "Product Name", "Category", "Region", "Date of Sale", "Quantity Sold", "Unit Price", "Discount", "Shipping Cost", "Payment Method", "Customer Name", "Customer Email", "Customer Address"}
+	valid := []string{" 1 ", " P1 ", " C1 ", "Shoe", "Shoes", "Europe", "2024-01-05", "2", "10.50", "0.1", "3", "PayPal", "Ann", "ann@example.com", "1 A St"}
+	withField := func(field int, value string) []string {
+		record := append([]string(nil), valid...)
+		record[field] = value
+		return record
+	}
+
+	products, customers, orders, items, err := validateAndTransformData([][]string{
+		header,
+		valid,
+		valid[:14],                 // too few fields
+		withField(8, "ten"),        // unit price
+		withField(9, "1.5"),        // discount above 1
+		withField(10, "-1"),        // shipping cost
+		withField(7, "two"),        // quantity
+		withField(6, "05/01/2024"), // date
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(products) != 1 || len(customers) != 1 || len(orders) != 1 || len(items) != 1 {
+		t.Fatalf("kept %d products, %d customers, %d orders and %d items, want the valid record only", len(products), len(customers), len(orders), len(items))
+	}
+	if orders[0].OrderID != "1" || items[0].ProductID != "P1" || products[0].UnitPrice != 10.5 || items[0].Discount != 0.1 {
+		t.Errorf("transformed %+v, %+v, %+v", orders[0], items[0], products[0])
+	}
+}
+
+func TestRefreshDatabase(t *testing.T) {
+	for _, testStore := range testStores {
+		t.Run(testStore.name, func(t *testing.T) {
+			store := testStore.open(t)
+			svc := New(store, NewResultCache(cache.NewLRU(constants.CacheEntries, constants.CacheTTL)))
+
+			if err := svc.Refresh.RefreshDatabase(testContext, filepath.Join(t.TempDir(), "missing.csv")); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("refreshing from a missing file: %v", err)
+			}
+			tag, _ := svc.Cache.Tag(testContext, "request")
+
+			if err := svc.Refresh.RefreshDatabase(testContext, testCSVFile); err != nil {
+				t.Fatal(err)
+			}
+			orders, total, err := store.ListOrders(testContext, models.PageRequest{Limit: 10})
+			if err != nil || total != 6 || len(orders) != 6 {
+				t.Errorf("imported %d orders of %d: %v", len(orders), total, err)
+			}
+			if after, _ := svc.Cache.Tag(testContext, "request"); after == tag {
+				t.Error("a refresh kept the cached results")
+			}
+		})
+	}
+}
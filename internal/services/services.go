@@ -0,0 +1,21 @@
+package services
+
+import "sales/internal/repository"
+
+// Services are the services of the application over one store, sharing its result cache.
+type Services struct {
+	Entities  *EntityService
+	Analytics *AnalyticsService
+	Refresh   *RefreshService
+	Cache     *ResultCache
+}
+
+// New returns the services over store, caching analytics results in resultCache.
+func New(store repository.Store, resultCache *ResultCache) *Services {
+	return &Services{
+		Entities:  NewEntityService(store, store, store, resultCache),
+		Analytics: NewAnalyticsService(store, resultCache),
+		Refresh:   NewRefreshService(store, resultCache),
+		Cache:     resultCache,
+	}
+}
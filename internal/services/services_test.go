@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"sales/internal/constants"
+	"sales/internal/database"
+	"sales/internal/models"
+	"sales/internal/repository"
+	"sales/internal/repository/memory"
+	"sales/internal/tenant"
+	"sales/pkg/cache"
+	"testing"
+	"time"
+)
+
+// testCSVFile is the sample data the test services are refreshed from.
+const testCSVFile = "../../data/sales_data.csv"
+
+// testContext acts for the default tenant.
+var testContext = tenant.NewContext(context.Background(), tenant.Tenant{ID: constants.DefaultTenant})
+
+// testStores open the stores the services are tested over: the in-memory store and the SQL store
+// on SQLite, so both answer the same questions the same way.
+var testStores = []struct {
+	name string
+	open func(t *testing.T) repository.Store
+}{
+	{"memory", func(*testing.T) repository.Store { return memory.NewStore() }},
+	{"sqlite", newSQLiteStore},
+}
+
+// newSQLiteStore opens a migrated, tenant-scoped SQLite database in a temporary directory.
+func newSQLiteStore(t *testing.T) repository.Store {
+	t.Helper()
+	db, err := database.NewDatabase(database.Config{Driver: constants.DriverSQLite, DSN: filepath.Join(t.TempDir(), "sales.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		t.Cleanup(func() { _ = sqlDB.Close() })
+	}
+	return repository.NewSQLStore(db)
+}
+
+// forEachStore runs test as a subtest per test store, with services over the store holding
+// testCSVFile.
+func forEachStore(t *testing.T, test func(t *testing.T, svc *Services, store repository.Store)) {
+	for _, testStore := range testStores {
+		t.Run(testStore.name, func(t *testing.T) {
+			store := testStore.open(t)
+			svc := New(store, NewResultCache(cache.NewLRU(constants.CacheEntries, constants.CacheTTL)))
+			if err := svc.Refresh.RefreshDatabase(testContext, testCSVFile); err != nil {
+				t.Fatal(err)
+			}
+			test(t, svc, store)
+		})
+	}
+}
+
+// dateRange covers the inclusive UTC dates from start to end.
+func dateRange(t *testing.T, start, end string) models.DateRange {
+	t.Helper()
+	from, err := time.Parse(constants.DateFormat, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := time.Parse(constants.DateFormat, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.DateRange{From: from, To: to.AddDate(0, 0, 1), Location: time.UTC}
+}
+
+// productIDs lists the ids of products in order.
+func productIDs(products []models.Product) []string {
+	ids := make([]string, len(products))
+	for i, product := range products {
+		ids[i] = product.ProductID
+	}
+	return ids
+}
@@ -0,0 +1,71 @@
+package tenant
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoTenant fails statements on tenant data whose context carries no tenant, so a missed
+// context can never read or write across tenants.
+var ErrNoTenant = errors.New("no tenant in statement context")
+
+// field is the model field holding the owning tenant.
+const field = "TenantID"
+
+// Register scopes every statement on a model with a TenantID field to the tenant of the
+// statement's context, set with db.WithContext(NewContext(ctx, t)): reads, updates and deletes get
+// a tenant_id condition and inserts get their TenantID set. Tables joined in by hand must be
+// joined on tenant_id as well.
+func Register(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Query().Before("gorm:query").Register("tenant:scope", scope),
+		callbacks.Row().Before("gorm:row").Register("tenant:scope", scope),
+		callbacks.Update().Before("gorm:update").Register("tenant:scope", scopeUpdate),
+		callbacks.Delete().Before("gorm:delete").Register("tenant:scope", scope),
+		callbacks.Create().Before("gorm:create").Register("tenant:assign", assign),
+	)
+}
+
+// scope adds the tenant condition on the statement's table.
+func scope(db *gorm.DB) {
+	if id, ok := statementTenant(db); ok {
+		addCondition(db, id)
+	}
+}
+
+// scopeUpdate adds the tenant condition and keeps the tenant of rows whose every column is written.
+func scopeUpdate(db *gorm.DB) {
+	if id, ok := statementTenant(db); ok {
+		addCondition(db, id)
+		db.Statement.SetColumn(field, id, true)
+	}
+}
+
+func addCondition(db *gorm.DB, id string) {
+	column := clause.Column{Table: db.Statement.Table, Name: db.Statement.Schema.LookUpField(field).DBName}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{clause.Eq{Column: column, Value: id}}})
+}
+
+// assign sets the tenant of inserted rows.
+func assign(db *gorm.DB) {
+	if id, ok := statementTenant(db); ok {
+		db.Statement.SetColumn(field, id, true)
+	}
+}
+
+// statementTenant returns the tenant a statement on tenant data runs for, failing the statement
+// when its context carries none. Raw SQL and tables without tenants are left alone.
+func statementTenant(db *gorm.DB) (string, bool) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Schema.LookUpField(field) == nil || db.Statement.SQL.Len() > 0 {
+		return "", false
+	}
+	t, ok := FromContext(db.Statement.Context)
+	if !ok {
+		_ = db.AddError(ErrNoTenant)
+		return "", false
+	}
+	return t.ID, true
+}
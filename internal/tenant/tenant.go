@@ -0,0 +1,117 @@
+// Package tenant isolates the data of the business units sharing one instance. Every product,
+// customer, order and order item belongs to a tenant, and once Register has been called every
+// gorm statement on them is scoped to the tenant carried by its context.
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sales/internal/constants"
+
+	cron "github.com/robfig/cron/v3"
+)
+
+// Tenant is a business unit as configured in the tenants file: the CSV its data is refreshed from
+// and the cron schedule of the refresh.
+type Tenant struct {
+	ID       string `json:"id"`
+	CSVFile  string `json:"csv_file"`
+	Schedule string `json:"schedule"`
+}
+
+// Registry holds the configured tenants.
+type Registry struct {
+	tenants []Tenant
+}
+
+// NewRegistry checks tenants have unique ids and valid schedules; an empty CSV file or schedule
+// falls back to the one of defaults.
+func NewRegistry(tenants []Tenant, defaults Tenant) (*Registry, error) {
+	seen := make(map[string]bool, len(tenants))
+	for i := range tenants {
+		t := &tenants[i]
+		if t.ID == "" {
+			return nil, fmt.Errorf("tenant %d: id is required", i)
+		}
+		if seen[t.ID] {
+			return nil, fmt.Errorf("tenant %q is configured twice", t.ID)
+		}
+		seen[t.ID] = true
+
+		if t.CSVFile == "" {
+			t.CSVFile = defaults.CSVFile
+		}
+		if t.Schedule == "" {
+			t.Schedule = defaults.Schedule
+		}
+		if _, err := cron.ParseStandard(t.Schedule); err != nil {
+			return nil, fmt.Errorf("tenant %q: invalid schedule %q: %w", t.ID, t.Schedule, err)
+		}
+	}
+	return &Registry{tenants: tenants}, nil
+}
+
+// Load builds the registry from the tenants file at path, whose tenants default to the CSV file
+// and schedule of defaults. Without a file the instance serves only the default tenant.
+func Load(path string, defaults Tenant) (*Registry, error) {
+	if path == "" {
+		return NewRegistry([]Tenant{{ID: constants.DefaultTenant}}, defaults)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tenants []Tenant
+	if err := json.Unmarshal(data, &tenants); err != nil {
+		return nil, fmt.Errorf("invalid tenants file %s: %w", path, err)
+	}
+	if len(tenants) == 0 {
+		return nil, fmt.Errorf("tenants file %s lists no tenants", path)
+	}
+	return NewRegistry(tenants, defaults)
+}
+
+// Tenants returns the configured tenants.
+func (r *Registry) Tenants() []Tenant {
+	return r.tenants
+}
+
+// Resolve picks the tenant of a request from the tenant its credentials are bound to and the one
+// it asks for. Bound credentials may only ask for their own tenant; unbound ones may ask for any and
+// must ask for one unless a single tenant is configured.
+func (r *Registry) Resolve(bound, requested string) (Tenant, error) {
+	id := requested
+	switch {
+	case bound != "" && requested != "" && requested != bound:
+		return Tenant{}, constants.ErrTenantDenied
+	case bound != "":
+		id = bound
+	case id == "" && len(r.tenants) > 1:
+		return Tenant{}, constants.ErrTenantMissing
+	case id == "":
+		return r.tenants[0], nil
+	}
+
+	for _, t := range r.tenants {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return Tenant{}, fmt.Errorf("%w %q", constants.ErrUnknownTenant, id)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying t.
+func NewContext(ctx context.Context, t Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the tenant carried by ctx.
+func FromContext(ctx context.Context) (Tenant, bool) {
+	t, ok := ctx.Value(contextKey{}).(Tenant)
+	return t, ok
+}
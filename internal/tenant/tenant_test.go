@@ -0,0 +1,45 @@
+package tenant
+
+import (
+	"errors"
+	"sales/internal/constants"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	defaults := Tenant{CSVFile: "sales.csv", Schedule: constants.DefaultCronSchedule}
+	single, err := NewRegistry([]Tenant{{ID: "a"}}, defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	several, err := NewRegistry([]Tenant{{ID: "a"}, {ID: "b"}}, defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name      string
+		registry  *Registry
+		bound     string
+		requested string
+		want      string
+		err       error
+	}{
+		{"unbound credentials of a single tenant", single, "", "", "a", nil},
+		{"unbound credentials selecting a tenant", several, "", "b", "b", nil},
+		{"unbound credentials selecting no tenant among several", several, "", "", "", constants.ErrTenantMissing},
+		{"unbound credentials selecting an unknown tenant", several, "", "c", "", constants.ErrUnknownTenant},
+		{"bound credentials", several, "b", "", "b", nil},
+		{"bound credentials selecting their tenant", several, "b", "b", "b", nil},
+		{"bound credentials selecting another tenant", several, "b", "a", "", constants.ErrTenantDenied},
+		{"credentials bound to an unknown tenant", several, "c", "", "", constants.ErrUnknownTenant},
+	} {
+		got, err := test.registry.Resolve(test.bound, test.requested)
+		if !errors.Is(err, test.err) || got.ID != test.want {
+			t.Errorf("%s: got %q, %v, want %q, %v", test.name, got.ID, err, test.want, test.err)
+		}
+		if err == nil && got.CSVFile != defaults.CSVFile {
+			t.Errorf("%s: CSV file %q, want the default %q", test.name, got.CSVFile, defaults.CSVFile)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sales/internal/models"
+	"strings"
+)
+
+// inputValidator collects field violations of a request body.
+type inputValidator struct {
+	prefix     string
+	violations models.ValidationErrors
+}
+
+func (v *inputValidator) add(field string, err error) {
+	message := err.Error()
+	var customErr *models.CustomError
+	if errors.As(err, &customErr) {
+		message = customErr.Message
+	}
+	v.violations = append(v.violations, models.FieldError{Field: v.prefix + field, Message: message})
+}
+
+// required trims a mandatory text field, recording a violation when it is empty.
+func (v *inputValidator) required(field, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		v.add(field, logError("value cannot be empty"))
+	}
+	return value
+}
+
+func (v *inputValidator) err() error {
+	if len(v.violations) > 0 {
+		return v.violations
+	}
+	return nil
+}
+
+// ValidateProductInput validates a product body with the CSV import rules and converts it to a product.
+func ValidateProductInput(input models.ProductInput) (models.Product, error) {
+	var v inputValidator
+	product := models.Product{
+		ProductID:   v.required("ProductID", input.ProductID),
+		ProductName: v.required("ProductName", input.ProductName),
+		Category:    v.required("Category", input.Category),
+	}
+
+	unitPrice, err := ParsePrice(input.UnitPrice.String())
+	if err != nil {
+		v.add("UnitPrice", err)
+	}
+	product.UnitPrice = unitPrice
+
+	return product, v.err()
+}
+
+// ValidateCustomerInput validates a customer body and converts it to a customer.
+func ValidateCustomerInput(input models.CustomerInput) (models.Customer, error) {
+	var v inputValidator
+	customer := models.Customer{
+		CustomerID:      v.required("CustomerID", input.CustomerID),
+		CustomerName:    v.required("CustomerName", input.CustomerName),
+		CustomerEmail:   v.required("CustomerEmail", input.CustomerEmail),
+		CustomerAddress: strings.TrimSpace(input.CustomerAddress),
+	}
+	if customer.CustomerEmail != "" && !strings.Contains(customer.CustomerEmail, "@") {
+		v.add("CustomerEmail", logError("invalid email format"))
+	}
+
+	return customer, v.err()
+}
+
+// ValidateOrderInput validates an order body and its items with the CSV import rules and converts them.
+func ValidateOrderInput(input models.OrderInput) (models.Order, []models.OrderItem, error) {
+	var v inputValidator
+	order := models.Order{
+		OrderID:       v.required("OrderID", input.OrderID),
+		CustomerID:    v.required("CustomerID", input.CustomerID),
+		PaymentMethod: v.required("PaymentMethod", input.PaymentMethod),
+		Region:        v.required("Region", input.Region),
+	}
+
+	dateOfSale, err := ParseDate(input.DateOfSale)
+	if err != nil {
+		v.add("DateOfSale", err)
+	}
+	order.DateOfSale = dateOfSale
+
+	shippingCost, err := ParsePrice(input.ShippingCost.String())
+	if err != nil {
+		v.add("ShippingCost", err)
+	}
+	order.ShippingCost = shippingCost
+
+	items := make([]models.OrderItem, 0, len(input.Items))
+	for i, itemInput := range input.Items {
+		itemValidator := inputValidator{prefix: fmt.Sprintf("Items[%d].", i)}
+		item := validateOrderItem(&itemValidator, itemInput)
+		item.OrderID = order.OrderID
+		items = append(items, item)
+		v.violations = append(v.violations, itemValidator.violations...)
+	}
+
+	return order, items, v.err()
+}
+
+// ValidateOrderItemInput validates an order item body with the CSV import rules and converts it.
+func ValidateOrderItemInput(input models.OrderItemInput) (models.OrderItem, error) {
+	var v inputValidator
+	item := validateOrderItem(&v, input)
+	return item, v.err()
+}
+
+func validateOrderItem(v *inputValidator, input models.OrderItemInput) models.OrderItem {
+	item := models.OrderItem{ProductID: v.required("ProductID", input.ProductID)}
+
+	quantitySold, err := ParseInt(input.QuantitySold.String())
+	if err != nil {
+		v.add("QuantitySold", err)
+	}
+	item.QuantitySold = quantitySold
+
+	discount, err := ParseDiscount(input.Discount.String())
+	if err != nil {
+		v.add("Discount", err)
+	}
+	item.Discount = discount
+
+	return item
+}
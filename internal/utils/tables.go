@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"sales/internal/models"
+	"sales/pkg/export"
+	"sales/pkg/stats"
+	"sort"
+)
+
+var productColumns = []string{"ProductID", "ProductName", "Category", "UnitPrice"}
+
+func productCells(product models.Product) []any {
+	return []any{product.ProductID, product.ProductName, product.Category, product.UnitPrice}
+}
+
+// ProductsTable flattens a ranked product list into one row per product.
+func ProductsTable(products []models.Product) export.Table {
+	return export.Table{
+		Columns: append([]string{"Rank"}, productColumns...),
+		Rows: func(yield func([]any) bool) {
+			for i, product := range products {
+				if !yield(append([]any{i + 1}, productCells(product)...)) {
+					return
+				}
+			}
+		},
+	}
+}
+
+// GroupedProductsTable flattens ranked products per group (category or region) into one row per
+// group and product, with the group named by groupColumn. A product column repeating the group is dropped.
+func GroupedProductsTable(groupColumn string, groups map[string][]models.Product) export.Table {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	columns := []string{groupColumn, "Rank"}
+	duplicate := -1
+	for i, column := range productColumns {
+		if column == groupColumn {
+			duplicate = i
+			continue
+		}
+		columns = append(columns, column)
+	}
+
+	return export.Table{
+		Columns: columns,
+		Rows: func(yield func([]any) bool) {
+			for _, key := range keys {
+				for i, product := range groups[key] {
+					cells := productCells(product)
+					if duplicate >= 0 {
+						cells = append(cells[:duplicate], cells[duplicate+1:]...)
+					}
+					if !yield(append([]any{key, i + 1}, cells...)) {
+						return
+					}
+				}
+			}
+		},
+	}
+}
+
+// TrendingProductsTable flattens the trending products and decliners into one row per product.
+func TrendingProductsTable(trending models.TrendingProducts) export.Table {
+	columns := append([]string{"Direction", "Rank"}, productColumns...)
+	columns = append(columns, "RecentQuantity", "BaselineQuantity", "GrowthRate")
+
+	return export.Table{
+		Columns: columns,
+		Rows: func(yield func([]any) bool) {
+			for _, list := range []struct {
+				direction string
+				products  []models.ProductTrend
+			}{{"trending", trending.Trending}, {"declining", trending.Decliners}} {
+				for i, trend := range list.products {
+					row := append([]any{list.direction, i + 1}, productCells(trend.Product)...)
+					if !yield(append(row, trend.RecentQuantity, trend.BaselineQuantity, trend.GrowthRate)) {
+						return
+					}
+				}
+			}
+		},
+	}
+}
+
+// ForecastTable flattens the sales history and every method's forecast into one row per series and week.
+// History rows belong to the "history" series; forecast rows to their method.
+func ForecastTable(result models.ForecastResult) export.Table {
+	return export.Table{
+		Columns: []string{"Series", "PeriodStart", "Quantity", "Revenue", "Lower", "Upper"},
+		Rows: func(yield func([]any) bool) {
+			for _, bucket := range result.History {
+				if !yield([]any{"history", bucket.Period, bucket.QuantitySold, bucket.Revenue, nil, nil}) {
+					return
+				}
+			}
+			for _, method := range result.Methods {
+				for _, point := range method.Forecast {
+					if !yield([]any{method.Method, point.PeriodStart, point.Quantity, nil, point.Lower, point.Upper}) {
+						return
+					}
+				}
+			}
+		},
+	}
+}
+
+// CustomerRetentionTable flattens the new vs returning split into one row per period.
+func CustomerRetentionTable(periods []models.CustomerRetentionPeriod) export.Table {
+	return export.Table{
+		Columns: []string{"Period", "NewCustomers", "ReturningCustomers", "NewOrders", "ReturningOrders",
+			"NewRevenue", "ReturningRevenue", "RepeatPurchaseRate"},
+		Rows: func(yield func([]any) bool) {
+			for _, p := range periods {
+				if !yield([]any{p.Period, p.NewCustomers, p.ReturningCustomers, p.NewOrders, p.ReturningOrders,
+					p.NewRevenue, p.ReturningRevenue, p.RepeatPurchaseRate}) {
+					return
+				}
+			}
+		},
+	}
+}
+
+// PivotTableRows lays a pivot table out as a spreadsheet with a totals column and a totals row
+func PivotTableRows(table models.PivotTable) export.Table {
+	columns := []string{table.RowDimension + " \\ " + table.ColumnDimension}
+	for i, column := range table.Columns {
+		if i < len(table.ColumnLabels) {
+			column = pivotHeading(column, table.ColumnLabels[i])
+		}
+		columns = append(columns, column)
+	}
+
+	return export.Table{
+		Columns: append(columns, "Total"),
+		Rows: func(yield func([]any) bool) {
+			for _, row := range table.Rows {
+				record := make([]any, 0, len(row.Values)+2)
+				record = append(record, pivotHeading(row.Key, row.Label))
+				for _, value := range row.Values {
+					record = append(record, value)
+				}
+				if !yield(append(record, row.Total)) {
+					return
+				}
+			}
+
+			totals := make([]any, 0, len(table.ColumnTotals)+2)
+			totals = append(totals, "Total")
+			for _, value := range table.ColumnTotals {
+				totals = append(totals, value)
+			}
+			yield(append(totals, table.GrandTotal))
+		},
+	}
+}
+
+// pivotHeading names a pivot key, with the product name first when the key is a product id.
+func pivotHeading(key string, label string) string {
+	if label == "" {
+		return key
+	}
+	return label + " (" + key + ")"
+}
+
+// DistributionTable flattens the distribution summaries into one row per group and measure.
+// Histograms are only part of the JSON response.
+func DistributionTable(result models.DistributionResult) export.Table {
+	return export.Table{
+		Columns: []string{"Group", "Measure", "Count", "Min", "Max", "Mean", "P50", "P75", "P90", "P99"},
+		Rows: func(yield func([]any) bool) {
+			for _, group := range result.Groups {
+				for _, measure := range []struct {
+					name    string
+					summary stats.Summary
+				}{{"order_value", group.OrderValue}, {"quantity_per_line", group.QuantityPerLine}} {
+					s := measure.summary
+					if !yield([]any{group.Key, measure.name, s.Count, s.Min, s.Max, s.Mean, s.P50, s.P75, s.P90, s.P99}) {
+						return
+					}
+				}
+			}
+		},
+	}
+}
@@ -75,17 +75,19 @@ func ParseDiscount(discountStr string) (float64, error) {
 	return discount, nil
 }
 
-// ParseDate parses and validates a date string.
+// ParseDate parses and validates a date or timestamp string, returning it in UTC.
+// Timestamps without an offset and plain dates are taken to be UTC.
 func ParseDate(dateStr string) (time.Time, error) {
 	dateStr = strings.TrimSpace(dateStr)
 	if dateStr == "" {
 		return time.Time{}, logError("date cannot be empty")
 	}
-	date, err := time.Parse(constants.DateFormat, dateStr)
-	if err != nil {
-		return time.Time{}, logError("invalid date format")
+	for _, layout := range constants.TimestampFormats {
+		if date, err := time.Parse(layout, dateStr); err == nil {
+			return date.UTC(), nil
+		}
 	}
-	return date, nil
+	return time.Time{}, logError("invalid date format")
 }
 
 // ValidateDateFormat checks if a date string is in YYYY-MM-DD format.
@@ -97,23 +99,41 @@ func ValidateDateFormat(dateStr string) error {
 	return nil
 }
 
-// ValidateParamsAndGetLimit validates params received in request
-func ValidateParamsAndGetLimit(nStr, startDate, endDate string) (int, error) {
-	// limit validation
-	n, err := strconv.Atoi(nStr)
-	if err != nil || n <= 0 {
-		return 0, constants.ErrInvalidLimit
+// ParseTimezone loads the IANA timezone, defaulting to UTC when empty
+func ParseTimezone(tz string) (*time.Location, error) {
+	if tz == "" {
+		return time.UTC, nil
 	}
-
-	// start date validation
-	if err := ValidateDateFormat(startDate); err != nil {
-		return 0, constants.ErrInvalidStartDate
+	location, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, constants.ErrInvalidTimezone
 	}
+	return location, nil
+}
 
-	// end date validation
-	if err := ValidateDateFormat(endDate); err != nil {
-		return 0, constants.ErrInvalidEndDate
+// ParseDateRange converts inclusive start and end dates into a half-open range of instants covering
+// whole days in the timezone. An empty date leaves that side of the range open.
+func ParseDateRange(startDate, endDate, tz string) (models.DateRange, error) {
+	location, err := ParseTimezone(tz)
+	if err != nil {
+		return models.DateRange{}, err
 	}
 
-	return n, nil
+	dateRange := models.DateRange{Location: location}
+	if startDate != "" {
+		start, err := time.ParseInLocation(constants.DateFormat, startDate, location)
+		if err != nil {
+			return models.DateRange{}, constants.ErrInvalidStartDate
+		}
+		dateRange.From = start
+	}
+	if endDate != "" {
+		end, err := time.ParseInLocation(constants.DateFormat, endDate, location)
+		if err != nil {
+			return models.DateRange{}, constants.ErrInvalidEndDate
+		}
+		// the end date is inclusive, so the range stops at midnight of the following day
+		dateRange.To = end.AddDate(0, 0, 1)
+	}
+	return dateRange, nil
 }
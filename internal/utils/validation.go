@@ -0,0 +1,333 @@
+package utils
+
+import (
+	"net/url"
+	"sales/internal/constants"
+	"sales/internal/models"
+	"sales/pkg/forecast"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryRule validates a single query parameter; Validate returns the parameter's sentinel error.
+// Multi parameters may be repeated or comma separated and every value is validated. Default is the
+// value of an absent parameter.
+type QueryRule struct {
+	Required bool
+	Multi    bool
+	Default  string
+	Validate func(value string) error
+}
+
+// QueryRules describes every query parameter an endpoint accepts along with cross-field checks.
+type QueryRules struct {
+	Params map[string]QueryRule
+	// MaxRangeDays caps the inclusive start_date..end_date span; 0 leaves it unbounded
+	MaxRangeDays int
+	// Check reports the violations spanning several parameters of a query that passed every other rule
+	Check func(query Query) models.ValidationErrors
+}
+
+// Query is a query string that passed ValidateQuery, read with the defaults of its rules.
+// DateRange and Filter hold the parsed date range and sales filter parameters.
+type Query struct {
+	values    url.Values
+	rules     QueryRules
+	DateRange models.DateRange
+	Filter    models.SalesFilter
+}
+
+// Get returns the value of a parameter, or its default when absent.
+func (q Query) Get(name string) string {
+	if value := q.values.Get(name); value != "" {
+		return value
+	}
+	return q.rules.Params[name].Default
+}
+
+// Int returns the value of an integer parameter, or 0 when absent without a default.
+func (q Query) Int(name string) int {
+	i, _ := strconv.Atoi(q.Get(name))
+	return i
+}
+
+// ValidateQuery checks every rule, unknown parameters, the date range ordering and length and the
+// cross-field checks, returning all violations at once as models.ValidationErrors. A valid query is
+// returned parsed.
+func ValidateQuery(query url.Values, rules QueryRules) (Query, error) {
+	var violations models.ValidationErrors
+
+	// unknown parameters, reported in a stable order
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, ok := rules.Params[key]; !ok {
+			violations = append(violations, models.FieldError{Field: key, Message: constants.ErrUnknownParameter.Error()})
+		}
+	}
+
+	// per parameter rules, reported in a stable order
+	names := make([]string, 0, len(rules.Params))
+	for name := range rules.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rule := rules.Params[name]
+		values, present := query[name]
+		switch {
+		case !present || (len(values) == 1 && values[0] == ""):
+			if rule.Required {
+				violations = append(violations, models.FieldError{Field: name, Message: constants.ErrMissingParameter.Error()})
+			}
+		case len(values) > 1 && !rule.Multi:
+			violations = append(violations, models.FieldError{Field: name, Message: constants.ErrRepeatedParameter.Error()})
+		case rule.Validate != nil:
+			if !rule.Multi {
+				values = values[:1]
+			} else {
+				values = SplitMultiValues(values)
+			}
+			for _, value := range values {
+				if err := rule.Validate(value); err != nil {
+					violations = append(violations, models.FieldError{Field: name, Message: err.Error()})
+					break
+				}
+			}
+		}
+	}
+
+	// cross-field date range checks, only once both dates parse
+	start, startErr := time.Parse(constants.DateFormat, query.Get(constants.StartDate))
+	end, endErr := time.Parse(constants.DateFormat, query.Get(constants.EndDate))
+	if startErr == nil && endErr == nil {
+		if start.After(end) {
+			violations = append(violations, models.FieldError{Field: constants.EndDate, Message: constants.ErrDateRangeOrder.Error()})
+		} else if rules.MaxRangeDays > 0 && int(end.Sub(start).Hours()/24)+1 > rules.MaxRangeDays {
+			violations = append(violations, models.FieldError{Field: constants.EndDate, Message: constants.ErrDateRangeTooLong.Error()})
+		}
+	}
+
+	// cross-field price range check, only once both prices parse
+	minPrice, minErr := ParsePrice(query.Get(constants.MinPrice))
+	maxPrice, maxErr := ParsePrice(query.Get(constants.MaxPrice))
+	if minErr == nil && maxErr == nil && minPrice > maxPrice {
+		violations = append(violations, models.FieldError{Field: constants.MaxPrice, Message: constants.ErrPriceRangeOrder.Error()})
+	}
+	if len(violations) > 0 {
+		return Query{}, violations
+	}
+
+	parsed := Query{values: query, rules: rules, Filter: models.SalesFilter{
+		Categories:     SplitMultiValues(query[constants.Category]),
+		Regions:        SplitMultiValues(query[constants.Region]),
+		PaymentMethods: SplitMultiValues(query[constants.PaymentMethod]),
+		CustomerIDs:    SplitMultiValues(query[constants.CustomerID]),
+	}}
+	if minErr == nil {
+		parsed.Filter.MinPrice = &minPrice
+	}
+	if maxErr == nil {
+		parsed.Filter.MaxPrice = &maxPrice
+	}
+	dateRange, err := ParseDateRange(query.Get(constants.StartDate), query.Get(constants.EndDate), query.Get(constants.Timezone))
+	if err != nil {
+		return Query{}, err
+	}
+	parsed.DateRange = dateRange
+
+	if rules.Check != nil {
+		if violations := rules.Check(parsed); len(violations) > 0 {
+			return Query{}, violations
+		}
+	}
+	return parsed, nil
+}
+
+// SplitMultiValues flattens repeated and comma separated values, dropping empty ones.
+func SplitMultiValues(values []string) []string {
+	var split []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				split = append(split, part)
+			}
+		}
+	}
+	return split
+}
+
+// ValidateSalesFilter applies the filter parameter rules to a filter given outside a query string,
+// such as a GraphQL or gRPC argument.
+func ValidateSalesFilter(filter models.SalesFilter) (models.SalesFilter, error) {
+	filter.Categories = SplitMultiValues(filter.Categories)
+	filter.Regions = SplitMultiValues(filter.Regions)
+	filter.PaymentMethods = SplitMultiValues(filter.PaymentMethods)
+	filter.CustomerIDs = SplitMultiValues(filter.CustomerIDs)
+
+	if filter.MinPrice != nil && *filter.MinPrice < 0 {
+		return models.SalesFilter{}, constants.ErrInvalidMinPrice
+	}
+	if filter.MaxPrice != nil && *filter.MaxPrice < 0 {
+		return models.SalesFilter{}, constants.ErrInvalidMaxPrice
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return models.SalesFilter{}, constants.ErrPriceRangeOrder
+	}
+
+	return filter, nil
+}
+
+// dateRangeRules validates a required date range given outside a query string.
+var dateRangeRules = QueryRules{
+	Params: map[string]QueryRule{
+		constants.StartDate: {Required: true, Validate: DateRule(constants.ErrInvalidStartDate)},
+		constants.EndDate:   {Required: true, Validate: DateRule(constants.ErrInvalidEndDate)},
+		constants.Timezone:  {Validate: func(v string) error { _, err := ParseTimezone(v); return err }},
+	},
+	MaxRangeDays: constants.MaxDateRangeDays,
+}
+
+// ValidateDateRange applies the start_date, end_date and tz parameter rules to a date range given
+// outside a query string, such as a GraphQL or gRPC argument, and parses it.
+func ValidateDateRange(startDate, endDate, tz string) (models.DateRange, error) {
+	query := url.Values{constants.StartDate: {startDate}, constants.EndDate: {endDate}}
+	if tz != "" {
+		query.Set(constants.Timezone, tz)
+	}
+	parsed, err := ValidateQuery(query, dateRangeRules)
+	if err != nil {
+		return models.DateRange{}, err
+	}
+	return parsed.DateRange, nil
+}
+
+// enum parameter rules shared by the REST query strings and the GraphQL and gRPC arguments
+var (
+	IntervalRule     = QueryRule{Default: constants.IntervalMonth, Validate: EnumRule(constants.ErrInvalidInterval, constants.Intervals...)}
+	PivotRowsRule    = QueryRule{Required: true, Validate: EnumRule(constants.ErrInvalidRows, constants.PivotDimensions...)}
+	PivotColumnsRule = QueryRule{Required: true, Validate: EnumRule(constants.ErrInvalidColumns, constants.PivotDimensions...)}
+	MetricRule       = QueryRule{Default: constants.MetricQuantity, Validate: EnumRule(constants.ErrInvalidMetric, constants.Metrics...)}
+	PercentRule      = QueryRule{Validate: EnumRule(constants.ErrInvalidPercent, constants.PercentModes...)}
+	MethodRule       = QueryRule{Validate: EnumRule(constants.ErrInvalidMethod, forecast.Methods...)}
+	GroupByRule      = QueryRule{Validate: EnumRule(constants.ErrInvalidGroupBy, constants.DistributionDimensions...)}
+	DimensionRule    = QueryRule{Required: true, Validate: EnumRule(constants.ErrInvalidDimension, constants.GroupDimensions...)}
+)
+
+// CheckPivotDimensions reports a pivot table whose rows and columns are the same dimension.
+func CheckPivotDimensions(query Query) models.ValidationErrors {
+	if query.Get(constants.Rows) == query.Get(constants.Columns) {
+		return models.ValidationErrors{{Field: constants.Columns, Message: constants.ErrSameDimensions.Error()}}
+	}
+	return nil
+}
+
+// validateArgs applies rules to arguments given outside a query string, skipping the empty ones.
+func validateArgs(args map[string]string, rules QueryRules) (Query, error) {
+	query := url.Values{}
+	for name, value := range args {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	return ValidateQuery(query, rules)
+}
+
+// intervalRules validates a time bucketing interval given outside a query string.
+var intervalRules = QueryRules{Params: map[string]QueryRule{constants.Interval: IntervalRule}}
+
+// ValidateInterval applies the interval parameter rule, defaulting to month when empty.
+func ValidateInterval(interval string) (string, error) {
+	parsed, err := validateArgs(map[string]string{constants.Interval: interval}, intervalRules)
+	if err != nil {
+		return "", err
+	}
+	return parsed.Get(constants.Interval), nil
+}
+
+// pivotParamRules validates the pivot dimensions, metric and percentage mode given outside a query string.
+var pivotParamRules = QueryRules{
+	Params: map[string]QueryRule{
+		constants.Rows:    PivotRowsRule,
+		constants.Columns: PivotColumnsRule,
+		constants.Metric:  MetricRule,
+		constants.Percent: PercentRule,
+	},
+	Check: CheckPivotDimensions,
+}
+
+// ValidatePivotParams applies the rows, columns, metric and percent parameter rules, reporting every
+// violation at once, and returns the metric, which defaults to quantity.
+func ValidatePivotParams(rows, columns, metric, percent string) (string, error) {
+	args := map[string]string{constants.Rows: rows, constants.Columns: columns, constants.Metric: metric, constants.Percent: percent}
+	parsed, err := validateArgs(args, pivotParamRules)
+	if err != nil {
+		return "", err
+	}
+	return parsed.Get(constants.Metric), nil
+}
+
+// dimensionRules validates the dimension top products are grouped by given outside a query string.
+var dimensionRules = QueryRules{Params: map[string]QueryRule{constants.GRPCDimension: DimensionRule}}
+
+// ValidateDimension applies the dimension argument rule.
+func ValidateDimension(dimension string) error {
+	_, err := validateArgs(map[string]string{constants.GRPCDimension: dimension}, dimensionRules)
+	return err
+}
+
+// PriceRule validates a non-negative price, returning errInvalid otherwise
+func PriceRule(errInvalid error) func(string) error {
+	return func(value string) error {
+		if _, err := ParsePrice(value); err != nil {
+			return errInvalid
+		}
+		return nil
+	}
+}
+
+// ParseLimit parses the 'n' parameter, which must be between 1 and constants.MaxLimit
+func ParseLimit(nStr string) (int, error) {
+	n, err := strconv.Atoi(nStr)
+	if err != nil || n <= 0 || n > constants.MaxLimit {
+		return 0, constants.ErrInvalidLimit
+	}
+	return n, nil
+}
+
+// DateRule validates a YYYY-MM-DD date, returning errInvalid otherwise
+func DateRule(errInvalid error) func(string) error {
+	return func(value string) error {
+		if err := ValidateDateFormat(value); err != nil {
+			return errInvalid
+		}
+		return nil
+	}
+}
+
+// EnumRule validates that a value is one of the allowed values, returning errInvalid otherwise
+func EnumRule(errInvalid error, allowed ...string) func(string) error {
+	return func(value string) error {
+		if !slices.Contains(allowed, value) {
+			return errInvalid
+		}
+		return nil
+	}
+}
+
+// IntRangeRule validates an integer within [min, max], returning errInvalid otherwise
+func IntRangeRule(errInvalid error, min, max int) func(string) error {
+	return func(value string) error {
+		i, err := strconv.Atoi(value)
+		if err != nil || i < min || i > max {
+			return errInvalid
+		}
+		return nil
+	}
+}
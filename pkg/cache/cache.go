@@ -0,0 +1,114 @@
+// Package cache stores encoded values under string keys, in process with an LRU or shared through
+// a Redis-compatible server. Keys are grouped in namespaces carrying a generation: invalidating a
+// namespace bumps its generation, so keys built from the old one are never read again and age out.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Store is a cache backend.
+type Store interface {
+	// Get returns the value stored under key, if present and not expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key.
+	Set(ctx context.Context, key string, value []byte) error
+	// Generation returns the current generation of namespace.
+	Generation(ctx context.Context, namespace string) (int64, error)
+	// Invalidate moves namespace to a new generation.
+	Invalidate(ctx context.Context, namespace string) error
+}
+
+// LRU is an in-process Store holding at most a fixed number of entries, evicting the least
+// recently used one first. It is safe for concurrent use.
+type LRU struct {
+	capacity int
+	ttl      time.Duration
+
+	mu          sync.Mutex
+	entries     map[string]*list.Element
+	order       *list.List
+	generations map[string]int64
+	// firstGeneration seeds namespaces so generations differ across restarts
+	firstGeneration int64
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRU returns an LRU holding up to capacity entries for at most ttl each.
+func NewLRU(capacity int, ttl time.Duration) *LRU {
+	return &LRU{
+		capacity:        capacity,
+		ttl:             ttl,
+		entries:         map[string]*list.Element{},
+		order:           list.New(),
+		generations:     map[string]int64{},
+		firstGeneration: time.Now().UnixNano(),
+	}
+}
+
+func (c *LRU) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		c.remove(element)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+func (c *LRU) Set(_ context.Context, key string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &lruEntry{key: key, value: value, expires: time.Now().Add(c.ttl)}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *LRU) Generation(_ context.Context, namespace string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation(namespace), nil
+}
+
+func (c *LRU) Invalidate(_ context.Context, namespace string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generations[namespace] = c.generation(namespace) + 1
+	return nil
+}
+
+func (c *LRU) generation(namespace string) int64 {
+	if generation, ok := c.generations[namespace]; ok {
+		return generation
+	}
+	return c.firstGeneration
+}
+
+func (c *LRU) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry).key)
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a Store on a Redis-compatible server, shared by every instance using it. Entries expire
+// after the TTL; generations are kept until deleted.
+type Redis struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedis connects to the server at url, e.g. redis://localhost:6379/0, and prefixes every key
+// it writes with prefix.
+func NewRedis(url string, prefix string, ttl time.Duration) (*Redis, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return &Redis{client: redis.NewClient(options), prefix: prefix, ttl: ttl}, nil
+}
+
+// Ping checks the server is reachable.
+func (c *Redis) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c *Redis) Set(ctx context.Context, key string, value []byte) error {
+	return c.client.Set(ctx, c.prefix+key, value, c.ttl).Err()
+}
+
+func (c *Redis) Generation(ctx context.Context, namespace string) (int64, error) {
+	generation, err := c.client.Get(ctx, c.generationKey(namespace)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return generation, err
+}
+
+func (c *Redis) Invalidate(ctx context.Context, namespace string) error {
+	return c.client.Incr(ctx, c.generationKey(namespace)).Err()
+}
+
+func (c *Redis) generationKey(namespace string) string {
+	return c.prefix + "generation:" + namespace
+}
@@ -1,27 +1,29 @@
 package cronjob
 
 import (
+	"context"
 	cron "github.com/robfig/cron/v3"
 	"log"
-	"sales/internal/constants"
 	"sales/internal/services"
-
-	"gorm.io/gorm"
+	"sales/internal/tenant"
 )
 
-// SetupCronJob sets up the cron job for refreshing the database.
-func SetupCronJob(db *gorm.DB) {
+// SetupCronJob sets up a cron job per tenant refreshing its data from its CSV on its schedule.
+func SetupCronJob(refresh *services.RefreshService, tenants []tenant.Tenant) {
 	c := cron.New()
-	_, err := c.AddFunc(constants.CronTime, func() {
-		err := services.RefreshDatabase(db)
+	for _, t := range tenants {
+		tenantCtx := tenant.NewContext(context.Background(), t)
+		_, err := c.AddFunc(t.Schedule, func() {
+			err := refresh.RefreshDatabase(tenantCtx, t.CSVFile)
+			if err != nil {
+				log.Printf("Error refreshing tenant %s: %v", t.ID, err)
+			} else {
+				log.Printf("Tenant %s refreshed successfully via cron.", t.ID)
+			}
+		})
 		if err != nil {
-			log.Println("Error refreshing database:", err)
-		} else {
-			log.Println("Database refreshed successfully via cron.")
+			log.Printf("Error adding cron function for tenant %s: %v", t.ID, err)
 		}
-	})
-	if err != nil {
-		log.Println("Error adding cron function:", err)
 	}
 
 	c.Start()
@@ -0,0 +1,276 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// Table is a tidy table whose rows are flattened lazily from a result already held in memory, so
+// writers can send them in chunks without building the whole file first.
+// Cells are strings, integers, floats, *float64 (nil is an empty cell) or nil.
+type Table struct {
+	Columns []string
+	Rows    iter.Seq[[]any]
+}
+
+// Writer writes a table header followed by its rows; Close flushes any buffered output.
+type Writer interface {
+	WriteHeader(columns []string) error
+	WriteRow(row []any) error
+	Close() error
+}
+
+// Write passes a table through a writer, calling flush every flushEvery rows when it is set.
+func Write(w Writer, table Table, flushEvery int, flush func()) error {
+	if err := w.WriteHeader(table.Columns); err != nil {
+		return err
+	}
+	written := 0
+	for row := range table.Rows {
+		if err := w.WriteRow(row); err != nil {
+			return err
+		}
+		written++
+		if flush != nil && flushEvery > 0 && written%flushEvery == 0 {
+			flush()
+		}
+	}
+	return w.Close()
+}
+
+// FormatCell renders a cell as text; floats use the shortest exact representation.
+func FormatCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case *float64:
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formulaPrefixes are the leading characters that make a spreadsheet evaluate a text cell as a formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// EscapeFormula prefixes text starting like a formula with a single quote so spreadsheets show it as text.
+func EscapeFormula(text string) string {
+	if text != "" && strings.ContainsRune(formulaPrefixes, rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+// isNumber reports whether a cell holds a number, which is written as is: a negative number is no formula.
+func isNumber(value any) bool {
+	switch value.(type) {
+	case int, int64, float64, *float64:
+		return true
+	default:
+		return false
+	}
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+// NewCSVWriter writes RFC 4180 CSV with a header row, escaping text cells that start like a formula.
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{writer: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteHeader(columns []string) error {
+	return c.writer.Write(columns)
+}
+
+func (c *csvWriter) WriteRow(row []any) error {
+	record := make([]string, len(row))
+	for i, value := range row {
+		record[i] = FormatCell(value)
+		if !isNumber(value) {
+			record[i] = EscapeFormula(record[i])
+		}
+	}
+	if err := c.writer.Write(record); err != nil {
+		return err
+	}
+	// hand the row to the underlying writer so callers can flush it to the client
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type ndjsonWriter struct {
+	writer  io.Writer
+	columns []string
+}
+
+// NewNDJSONWriter writes one JSON object per row keyed by column, in column order.
+func NewNDJSONWriter(w io.Writer) Writer {
+	return &ndjsonWriter{writer: w}
+}
+
+func (n *ndjsonWriter) WriteHeader(columns []string) error {
+	n.columns = columns
+	return nil
+}
+
+func (n *ndjsonWriter) WriteRow(row []any) error {
+	line := []byte{'{'}
+	for i, value := range row {
+		if i > 0 {
+			line = append(line, ',')
+		}
+		key, err := json.Marshal(n.columns[i])
+		if err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		line = append(append(append(line, key...), ':'), encoded...)
+	}
+	line = append(line, '}', '\n')
+	_, err := n.writer.Write(line)
+	return err
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
+
+// static parts of a single sheet workbook
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	row     int
+}
+
+// NewXLSXWriter writes a single sheet workbook, compressing rows into the sheet as they come.
+// Text cells are written inline so no shared string table has to be buffered, and those starting like
+// a formula are escaped.
+func NewXLSXWriter(w io.Writer, sheetName string) (Writer, error) {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		if err := writeZipPart(archive, part.name, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	var name strings.Builder
+	_ = xml.EscapeText(&name, []byte(sheetName))
+	workbook := xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	if err := writeZipPart(archive, "xl/workbook.xml", workbook); err != nil {
+		return nil, err
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	x := &xlsxWriter{archive: archive, sheet: bufio.NewWriter(sheet)}
+	_, err = x.sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return x, err
+}
+
+func writeZipPart(archive *zip.Writer, name, content string) error {
+	part, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(part, content)
+	return err
+}
+
+func (x *xlsxWriter) WriteHeader(columns []string) error {
+	row := make([]any, len(columns))
+	for i, column := range columns {
+		row[i] = column
+	}
+	return x.WriteRow(row)
+}
+
+func (x *xlsxWriter) WriteRow(row []any) error {
+	x.row++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.row)
+	for i, value := range row {
+		ref := columnName(i) + strconv.Itoa(x.row)
+		switch {
+		case value == nil:
+			continue
+		case isNumber(value):
+			if text := FormatCell(value); text != "" {
+				fmt.Fprintf(x.sheet, `<c r="%s"><v>%s</v></c>`, ref, text)
+			}
+		default:
+			fmt.Fprintf(x.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			if err := xml.EscapeText(x.sheet, []byte(EscapeFormula(FormatCell(value)))); err != nil {
+				return err
+			}
+			_, _ = x.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := x.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := x.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.archive.Close()
+}
+
+// columnName converts a zero based column index to its spreadsheet letters (0 is A, 26 is AA).
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
@@ -0,0 +1,153 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// rows yields the given rows as a lazily produced table body.
+func rows(values ...[]any) func(func([]any) bool) {
+	return func(yield func([]any) bool) {
+		for _, row := range values {
+			if !yield(row) {
+				return
+			}
+		}
+	}
+}
+
+var formulaTable = Table{
+	Columns: []string{"name", "value"},
+	Rows: rows(
+		[]any{"=SUM(A1:A9)", -3},
+		[]any{"+1", -1.5},
+		[]any{"-2", nil},
+		[]any{"@cmd", int64(4)},
+		[]any{"\tindent", 0.25},
+		[]any{"\rreturn", (*float64)(nil)},
+		[]any{"plain", 7},
+	),
+}
+
+func TestCSVEscapesFormulas(t *testing.T) {
+	var out bytes.Buffer
+	if err := Write(NewCSVWriter(&out), formulaTable, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"name", "value"},
+		{"'=SUM(A1:A9)", "-3"},
+		{"'+1", "-1.5"},
+		{"'-2", ""},
+		{"'@cmd", "4"},
+		{"'\tindent", "0.25"},
+		{"'\rreturn", ""},
+		{"plain", "7"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("%d records, want %d: %q", len(records), len(want), records)
+	}
+	for i := range want {
+		if !slices.Equal(records[i], want[i]) {
+			t.Errorf("record %d: %q, want %q", i, records[i], want[i])
+		}
+	}
+}
+
+// xlsxSheet is the part of a worksheet the tests read back.
+type xlsxSheet struct {
+	Rows []struct {
+		R     string `xml:"r,attr"`
+		Cells []struct {
+			R      string `xml:"r,attr"`
+			T      string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestXLSX(t *testing.T) {
+	var out bytes.Buffer
+	w, err := NewXLSXWriter(&out, `Q1 <"sales"> & more`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := Table{
+		Columns: []string{"name", "value"},
+		Rows:    rows([]any{`<b>"Fish" & Chips</b>`, -3}, []any{"=HYPERLINK(\"x\")", nil}),
+	}
+	if err := Write(w, table, 1, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	for _, file := range archive.File {
+		r, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[file.Name] = string(content)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels", "xl/workbook.xml", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("the workbook has no %s part", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="Q1 &lt;&#34;sales&#34;&gt; &amp; more"`) {
+		t.Errorf("sheet name not escaped: %s", parts["xl/workbook.xml"])
+	}
+
+	raw := parts["xl/worksheets/sheet1.xml"]
+	if !strings.Contains(raw, `&lt;b&gt;&#34;Fish&#34; &amp; Chips&lt;/b&gt;`) {
+		t.Errorf("text cell not escaped: %s", raw)
+	}
+	var sheet xlsxSheet
+	if err := xml.Unmarshal([]byte(raw), &sheet); err != nil {
+		t.Fatalf("the sheet is not well formed: %v\n%s", err, raw)
+	}
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("%d rows, want a header and 2 rows: %s", len(sheet.Rows), raw)
+	}
+
+	header, first, second := sheet.Rows[0], sheet.Rows[1], sheet.Rows[2]
+	if header.R != "1" || len(header.Cells) != 2 || header.Cells[1].R != "B1" || header.Cells[1].Inline != "value" {
+		t.Errorf("header %+v", header)
+	}
+	if cell := first.Cells[0]; cell.R != "A2" || cell.T != "inlineStr" || cell.Inline != `<b>"Fish" & Chips</b>` {
+		t.Errorf("text cell %+v", cell)
+	}
+	if cell := first.Cells[1]; cell.R != "B2" || cell.T != "" || cell.Value != "-3" {
+		t.Errorf("number cell %+v, want a plain -3", cell)
+	}
+	if len(second.Cells) != 1 || second.Cells[0].R != "A3" || second.Cells[0].Inline != `'=HYPERLINK("x")` {
+		t.Errorf("formula row %+v, want an escaped formula and no empty cell", second)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != want {
+			t.Errorf("column %d: %s, want %s", i, got, want)
+		}
+	}
+}
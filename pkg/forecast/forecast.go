@@ -0,0 +1,260 @@
+package forecast
+
+import (
+	"errors"
+	"math"
+)
+
+// supported forecasting methods
+const (
+	SeasonalNaive = "seasonal_naive"
+	MovingAverage = "moving_average"
+	HoltWinters   = "holt_winters"
+)
+
+// Methods lists every supported forecasting method in the order they are reported.
+var Methods = []string{SeasonalNaive, MovingAverage, HoltWinters}
+
+// z-score used for the 95% prediction interval
+const zScore95 = 1.96
+
+var (
+	ErrUnknownMethod       = errors.New("unknown forecasting method")
+	ErrInsufficientHistory = errors.New("not enough history to fit forecasting method")
+)
+
+// Config holds the tuning parameters shared by all methods.
+type Config struct {
+	SeasonLength int     // number of periods in one season
+	Window       int     // moving average window
+	Alpha        float64 // Holt-Winters level smoothing
+	Beta         float64 // Holt-Winters trend smoothing
+	Gamma        float64 // Holt-Winters seasonal smoothing
+}
+
+// DefaultConfig returns sensible defaults for weekly sales data.
+func DefaultConfig() Config {
+	return Config{
+		SeasonLength: 4,
+		Window:       4,
+		Alpha:        0.3,
+		Beta:         0.1,
+		Gamma:        0.2,
+	}
+}
+
+// Point is a single forecasted value together with its 95% prediction interval.
+type Point struct {
+	Step  int
+	Value float64
+	Lower float64
+	Upper float64
+}
+
+// Metrics holds back-test error metrics. MAPE is nil when every actual value is zero.
+type Metrics struct {
+	MAE  float64
+	MAPE *float64
+}
+
+// fitFunc returns one-step in-sample fitted values (NaN where no fit exists) and h-step forecasts.
+type fitFunc func(series []float64, horizon int, cfg Config) ([]float64, []float64, error)
+
+// Forecast runs the named method over the series and returns horizon points with prediction intervals.
+func Forecast(method string, series []float64, horizon int, cfg Config) ([]Point, error) {
+	fit, err := lookup(method)
+	if err != nil {
+		return nil, err
+	}
+
+	fitted, predictions, err := fit(series, horizon, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sigma := residualStdDev(series, fitted)
+	points := make([]Point, 0, horizon)
+	for i, value := range predictions {
+		step := i + 1
+		spread := zScore95 * sigma * math.Sqrt(float64(step))
+		points = append(points, Point{
+			Step:  step,
+			Value: nonNegative(value),
+			Lower: nonNegative(value - spread),
+			Upper: nonNegative(value + spread),
+		})
+	}
+	return points, nil
+}
+
+// Backtest holds out the last horizon values, fits the method on the rest and scores the forecast.
+func Backtest(method string, series []float64, horizon int, cfg Config) (Metrics, error) {
+	fit, err := lookup(method)
+	if err != nil {
+		return Metrics{}, err
+	}
+	if horizon <= 0 || len(series) <= horizon {
+		return Metrics{}, ErrInsufficientHistory
+	}
+
+	train := series[:len(series)-horizon]
+	actual := series[len(series)-horizon:]
+	_, predictions, err := fit(train, horizon, cfg)
+	if err != nil {
+		return Metrics{}, err
+	}
+
+	var absErr, pctErr float64
+	pctCount := 0
+	for i, a := range actual {
+		diff := math.Abs(a - nonNegative(predictions[i]))
+		absErr += diff
+		if a != 0 {
+			pctErr += diff / math.Abs(a)
+			pctCount++
+		}
+	}
+
+	metrics := Metrics{MAE: absErr / float64(len(actual))}
+	if pctCount > 0 {
+		mape := 100 * pctErr / float64(pctCount)
+		metrics.MAPE = &mape
+	}
+	return metrics, nil
+}
+
+// lookup resolves a method name to its implementation.
+func lookup(method string) (fitFunc, error) {
+	switch method {
+	case SeasonalNaive:
+		return seasonalNaive, nil
+	case MovingAverage:
+		return movingAverage, nil
+	case HoltWinters:
+		return holtWinters, nil
+	default:
+		return nil, ErrUnknownMethod
+	}
+}
+
+// seasonalNaive repeats the value observed one season earlier.
+func seasonalNaive(series []float64, horizon int, cfg Config) ([]float64, []float64, error) {
+	m := cfg.SeasonLength
+	if m <= 0 || len(series) < m {
+		return nil, nil, ErrInsufficientHistory
+	}
+
+	fitted := make([]float64, len(series))
+	for i := range series {
+		if i < m {
+			fitted[i] = math.NaN()
+			continue
+		}
+		fitted[i] = series[i-m]
+	}
+
+	predictions := make([]float64, horizon)
+	for h := 0; h < horizon; h++ {
+		predictions[h] = series[len(series)-m+h%m]
+	}
+	return fitted, predictions, nil
+}
+
+// movingAverage forecasts a flat line at the mean of the last window values.
+func movingAverage(series []float64, horizon int, cfg Config) ([]float64, []float64, error) {
+	w := cfg.Window
+	if w <= 0 || len(series) < w {
+		return nil, nil, ErrInsufficientHistory
+	}
+
+	fitted := make([]float64, len(series))
+	for i := range series {
+		if i < w {
+			fitted[i] = math.NaN()
+			continue
+		}
+		fitted[i] = mean(series[i-w : i])
+	}
+
+	last := mean(series[len(series)-w:])
+	predictions := make([]float64, horizon)
+	for h := range predictions {
+		predictions[h] = last
+	}
+	return fitted, predictions, nil
+}
+
+// holtWinters applies additive triple exponential smoothing.
+func holtWinters(series []float64, horizon int, cfg Config) ([]float64, []float64, error) {
+	m := cfg.SeasonLength
+	if m <= 0 || len(series) < 2*m {
+		return nil, nil, ErrInsufficientHistory
+	}
+
+	// initial level, trend and seasonal components from the first two seasons
+	level := mean(series[:m])
+	trend := (mean(series[m:2*m]) - level) / float64(m)
+	seasonal := make([]float64, m)
+	for i := 0; i < m; i++ {
+		seasonal[i] = series[i] - level
+	}
+
+	fitted := make([]float64, len(series))
+	for i := range series {
+		if i < m {
+			fitted[i] = math.NaN()
+			continue
+		}
+		s := seasonal[i%m]
+		fitted[i] = level + trend + s
+
+		prevLevel := level
+		level = cfg.Alpha*(series[i]-s) + (1-cfg.Alpha)*(level+trend)
+		trend = cfg.Beta*(level-prevLevel) + (1-cfg.Beta)*trend
+		seasonal[i%m] = cfg.Gamma*(series[i]-level) + (1-cfg.Gamma)*s
+	}
+
+	predictions := make([]float64, horizon)
+	for h := 0; h < horizon; h++ {
+		predictions[h] = level + float64(h+1)*trend + seasonal[(len(series)+h)%m]
+	}
+	return fitted, predictions, nil
+}
+
+// residualStdDev returns the standard deviation of one-step in-sample errors.
+func residualStdDev(series, fitted []float64) float64 {
+	var sum, sumSq float64
+	count := 0
+	for i, f := range fitted {
+		if math.IsNaN(f) {
+			continue
+		}
+		r := series[i] - f
+		sum += r
+		sumSq += r * r
+		count++
+	}
+	if count < 2 {
+		return 0
+	}
+	avg := sum / float64(count)
+	return math.Sqrt((sumSq - float64(count)*avg*avg) / float64(count-1))
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func nonNegative(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,162 @@
+package forecast
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+const tolerance = 1e-9
+
+// series returns n values of f over the period index.
+func series(n int, f func(i int) float64) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = f(i)
+	}
+	return values
+}
+
+func values(points []Point) []float64 {
+	out := make([]float64, len(points))
+	for i, p := range points {
+		out[i] = p.Value
+	}
+	return out
+}
+
+func equal(got, want []float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func TestForecastConstantSeries(t *testing.T) {
+	constant := series(12, func(int) float64 { return 10 })
+	for _, method := range Methods {
+		points, err := Forecast(method, constant, 3, DefaultConfig())
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		for _, p := range points {
+			if math.Abs(p.Value-10) > tolerance || p.Lower != p.Value || p.Upper != p.Value {
+				t.Errorf("%s: step %d is %+v, want 10 with no spread", method, p.Step, p)
+			}
+		}
+	}
+}
+
+func TestForecastLinearTrend(t *testing.T) {
+	trend := series(12, func(i int) float64 { return 10 + 2*float64(i) })
+
+	for _, test := range []struct {
+		method string
+		want   []float64
+	}{
+		// the last season is repeated, so each step is one season behind the trend
+		{SeasonalNaive, []float64{26, 28, 30, 32}},
+		{MovingAverage, []float64{29, 29, 29, 29}},
+	} {
+		points, err := Forecast(test.method, trend, 4, DefaultConfig())
+		if err != nil {
+			t.Fatalf("%s: %v", test.method, err)
+		}
+		if got := values(points); !equal(got, test.want) {
+			t.Errorf("%s: %v, want %v", test.method, got, test.want)
+		}
+	}
+
+	points, err := Forecast(HoltWinters, trend, 4, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range points {
+		actual := 10 + 2*float64(len(trend)+i)
+		if i > 0 && p.Value <= points[i-1].Value {
+			t.Errorf("holt_winters: step %d is %v, want it above step %d", p.Step, p.Value, i)
+		}
+		if actual < p.Lower || actual > p.Upper {
+			t.Errorf("holt_winters: step %d interval [%v, %v] misses the trend %v", p.Step, p.Lower, p.Upper, actual)
+		}
+	}
+}
+
+func TestForecastWeeklySeasonality(t *testing.T) {
+	pattern := []float64{10, 20, 30, 40}
+	seasonal := series(12, func(i int) float64 { return pattern[i%4] })
+
+	for _, test := range []struct {
+		method string
+		want   []float64
+	}{
+		{SeasonalNaive, []float64{10, 20, 30, 40, 10, 20}},
+		{HoltWinters, []float64{10, 20, 30, 40, 10, 20}},
+		{MovingAverage, []float64{25, 25, 25, 25, 25, 25}},
+	} {
+		points, err := Forecast(test.method, seasonal, 6, DefaultConfig())
+		if err != nil {
+			t.Fatalf("%s: %v", test.method, err)
+		}
+		if got := values(points); !equal(got, test.want) {
+			t.Errorf("%s: %v, want %v", test.method, got, test.want)
+		}
+	}
+}
+
+func TestForecastIntervalWidensWithHorizon(t *testing.T) {
+	// seasonal naive residuals are 0, 1, -1 and 1
+	points, err := Forecast(SeasonalNaive, []float64{10, 12, 9, 11, 10, 13, 8, 12}, 4, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, last := points[0].Upper-points[0].Value, points[3].Upper-points[3].Value
+	if first <= 0 || math.Abs(last-2*first) > tolerance {
+		t.Errorf("spread %v at step 1 and %v at step 4, want it to grow with the square root of the step", first, last)
+	}
+}
+
+func TestBacktest(t *testing.T) {
+	// the held-out season 0, 4, 2, 8 is forecast as 1, 2, 3, 4
+	metrics, err := Backtest(SeasonalNaive, []float64{1, 2, 3, 4, 0, 4, 2, 8}, 4, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metrics.MAE != 2 {
+		t.Errorf("MAE %v, want 2", metrics.MAE)
+	}
+	// the zero actual is left out of the percentage error
+	if metrics.MAPE == nil || math.Abs(*metrics.MAPE-50) > tolerance {
+		t.Errorf("MAPE %v, want 50", metrics.MAPE)
+	}
+
+	metrics, err = Backtest(SeasonalNaive, []float64{1, 2, 3, 4, 0, 0, 0, 0}, 4, DefaultConfig())
+	if err != nil || metrics.MAPE != nil || metrics.MAE != 2.5 {
+		t.Errorf("all-zero actuals: %+v, %v, want MAE 2.5 and no MAPE", metrics, err)
+	}
+}
+
+func TestInsufficientHistory(t *testing.T) {
+	short := []float64{1, 2, 3, 4, 5, 6, 7}
+	for _, test := range []struct {
+		name string
+		run  func() error
+		want error
+	}{
+		{"seasonal naive shorter than a season", func() error { _, err := Forecast(SeasonalNaive, short[:3], 1, DefaultConfig()); return err }, ErrInsufficientHistory},
+		{"moving average shorter than its window", func() error { _, err := Forecast(MovingAverage, short[:3], 1, DefaultConfig()); return err }, ErrInsufficientHistory},
+		{"holt-winters shorter than two seasons", func() error { _, err := Forecast(HoltWinters, short, 1, DefaultConfig()); return err }, ErrInsufficientHistory},
+		{"backtest holding out the whole series", func() error { _, err := Backtest(SeasonalNaive, short[:4], 4, DefaultConfig()); return err }, ErrInsufficientHistory},
+		{"backtest with too short a training set", func() error { _, err := Backtest(HoltWinters, short, 1, DefaultConfig()); return err }, ErrInsufficientHistory},
+		{"an unknown method", func() error { _, err := Forecast("arima", short, 1, DefaultConfig()); return err }, ErrUnknownMethod},
+	} {
+		if err := test.run(); !errors.Is(err, test.want) {
+			t.Errorf("%s: %v, want %v", test.name, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Package jwt verifies HS256 and RS256 JSON Web Tokens against the keys of a JSON Web Key Set.
+// Parsing, signatures and the registered claims are checked by github.com/golang-jwt/jwt.
+package jwt
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// supported signing algorithms
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+)
+
+// signingMethods are the algorithms tokens may be signed with; any other, none included, is rejected.
+var signingMethods = []string{HS256, RS256}
+
+var (
+	ErrMalformed   = errors.New("malformed token")
+	ErrAlgorithm   = errors.New("unsupported signing algorithm")
+	ErrUnknownKey  = errors.New("no key matches the token")
+	ErrSignature   = errors.New("invalid token signature")
+	ErrExpired     = errors.New("token is expired")
+	ErrNotYetValid = errors.New("token is not valid yet")
+	ErrIssuer      = errors.New("unexpected token issuer")
+	ErrAudience    = errors.New("unexpected token audience")
+	ErrNoExpiry    = errors.New("token has no expiry")
+)
+
+// Key is a verification key of a key set. Secret is set for HS256 keys and Public for RS256 keys.
+type Key struct {
+	ID     string
+	Alg    string
+	Secret []byte
+	Public *rsa.PublicKey
+}
+
+// KeySet holds the keys tokens are verified with.
+type KeySet struct {
+	Keys []Key
+}
+
+// jwk is the JSON form of a key: kty "oct" with k for HS256, kty "RSA" with n and e for RS256.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// LoadKeySet reads a JSON Web Key Set file. Keys meant for encryption are skipped.
+func LoadKeySet(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeySet(data)
+}
+
+// ParseKeySet parses a JSON Web Key Set.
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var document struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid key set: %w", err)
+	}
+
+	set := &KeySet{}
+	for i, raw := range document.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+		key, err := parseKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %d (%q): %w", i, raw.Kid, err)
+		}
+		set.Keys = append(set.Keys, key)
+	}
+	return set, nil
+}
+
+func parseKey(raw jwk) (Key, error) {
+	switch raw.Kty {
+	case "oct":
+		if raw.Alg != "" && raw.Alg != HS256 {
+			return Key{}, ErrAlgorithm
+		}
+		secret, err := base64.RawURLEncoding.DecodeString(raw.K)
+		if err != nil || len(secret) == 0 {
+			return Key{}, errors.New("invalid 'k'")
+		}
+		return Key{ID: raw.Kid, Alg: HS256, Secret: secret}, nil
+	case "RSA":
+		if raw.Alg != "" && raw.Alg != RS256 {
+			return Key{}, ErrAlgorithm
+		}
+		n, err := base64.RawURLEncoding.DecodeString(raw.N)
+		if err != nil || len(n) == 0 {
+			return Key{}, errors.New("invalid 'n'")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(raw.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return Key{}, errors.New("invalid 'e'")
+		}
+		public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		return Key{ID: raw.Kid, Alg: RS256, Public: public}, nil
+	default:
+		return Key{}, fmt.Errorf("unsupported key type %q", raw.Kty)
+	}
+}
+
+// Claims are the registered claims checked by Verify plus the raw payload for custom claims.
+type Claims struct {
+	Subject   string
+	Issuer    string
+	Audience  []string
+	ExpiresAt time.Time
+	NotBefore time.Time
+	Raw       map[string]json.RawMessage
+}
+
+// Verifier checks token signatures, lifetimes and optionally the issuer and audience.
+type Verifier struct {
+	Keys *KeySet
+	// Issuer and Audience are only checked when set
+	Issuer   string
+	Audience string
+	// Leeway tolerates clock skew on exp and nbf
+	Leeway time.Duration
+}
+
+// Verify checks a compact serialized token and returns its claims. Tokens must expire and their
+// alg must match the type of the key they are verified with, so an RSA public key is never used
+// as an HMAC secret. Errors are the errors of this package.
+func (v *Verifier) Verify(token string, now time.Time) (Claims, error) {
+	options := []gojwt.ParserOption{
+		gojwt.WithValidMethods(signingMethods),
+		gojwt.WithExpirationRequired(),
+		gojwt.WithLeeway(v.Leeway),
+		gojwt.WithTimeFunc(func() time.Time { return now }),
+	}
+	if v.Issuer != "" {
+		options = append(options, gojwt.WithIssuer(v.Issuer))
+	}
+	if v.Audience != "" {
+		options = append(options, gojwt.WithAudience(v.Audience))
+	}
+
+	claims := &tokenClaims{}
+	parsed, err := gojwt.NewParser(options...).ParseWithClaims(token, claims, v.keys)
+	if err != nil {
+		return Claims{}, v.translate(err, parsed, claims)
+	}
+
+	result := Claims{
+		Subject:  claims.Subject,
+		Issuer:   claims.Issuer,
+		Audience: claims.Audience,
+		Raw:      claims.raw,
+	}
+	if claims.ExpiresAt != nil {
+		result.ExpiresAt = claims.ExpiresAt.Time
+	}
+	if claims.NotBefore != nil {
+		result.NotBefore = claims.NotBefore.Time
+	}
+	return result, nil
+}
+
+// tokenClaims decodes the registered claims and keeps the raw payload for custom claims.
+type tokenClaims struct {
+	gojwt.RegisteredClaims
+	raw map[string]json.RawMessage
+}
+
+func (c *tokenClaims) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &c.RegisteredClaims); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &c.raw)
+}
+
+// keys returns the keys of the set a token may be verified with: those of its alg and, when the
+// token names one, its kid.
+func (v *Verifier) keys(token *gojwt.Token) (any, error) {
+	alg := token.Method.Alg()
+	kid, _ := token.Header["kid"].(string)
+
+	var set gojwt.VerificationKeySet
+	for _, key := range v.Keys.Keys {
+		if key.Alg != alg || (kid != "" && key.ID != kid) {
+			continue
+		}
+		switch alg {
+		case HS256:
+			set.Keys = append(set.Keys, key.Secret)
+		case RS256:
+			set.Keys = append(set.Keys, key.Public)
+		}
+	}
+	if len(set.Keys) == 0 {
+		return nil, ErrUnknownKey
+	}
+	return set, nil
+}
+
+// translate maps an error of the JWT library to the matching error of this package.
+func (v *Verifier) translate(err error, token *gojwt.Token, claims *tokenClaims) error {
+	missing := errors.Is(err, gojwt.ErrTokenRequiredClaimMissing)
+	switch {
+	case errors.Is(err, gojwt.ErrTokenMalformed):
+		return ErrMalformed
+	case token == nil || token.Method == nil || !slices.Contains(signingMethods, token.Method.Alg()):
+		return ErrAlgorithm
+	case errors.Is(err, ErrUnknownKey):
+		return ErrUnknownKey
+	case errors.Is(err, gojwt.ErrTokenSignatureInvalid):
+		return ErrSignature
+	case missing && claims.ExpiresAt == nil:
+		return ErrNoExpiry
+	case errors.Is(err, gojwt.ErrTokenExpired):
+		return ErrExpired
+	case errors.Is(err, gojwt.ErrTokenNotValidYet):
+		return ErrNotYetValid
+	case errors.Is(err, gojwt.ErrTokenInvalidIssuer), missing && claims.Issuer == "" && v.Issuer != "":
+		return ErrIssuer
+	case errors.Is(err, gojwt.ErrTokenInvalidAudience), missing && len(claims.Audience) == 0 && v.Audience != "":
+		return ErrAudience
+	default:
+		return fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+}
@@ -0,0 +1,193 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	now    = time.Unix(1_700_000_000, 0)
+	secret = []byte("a shared secret of at least 32 bytes")
+)
+
+// token encodes header and claims and signs them with sign, which may be nil for an empty signature.
+func token(t *testing.T, header, claims map[string]any, sign func(signed []byte) []byte) string {
+	t.Helper()
+	segment := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := segment(header) + "." + segment(claims)
+	var signature []byte
+	if sign != nil {
+		signature = sign([]byte(signed))
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func hs256(key []byte) func([]byte) []byte {
+	return func(signed []byte) []byte {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(signed)
+		return mac.Sum(nil)
+	}
+}
+
+func rs256(t *testing.T, key *rsa.PrivateKey) func([]byte) []byte {
+	return func(signed []byte) []byte {
+		digest := sha256.Sum256(signed)
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signature
+	}
+}
+
+// claims returns valid claims with the changes applied; a nil value removes the claim.
+func claims(changes map[string]any) map[string]any {
+	c := map[string]any{"sub": "alice", "iss": "issuer", "aud": "sales", "exp": now.Add(time.Hour).Unix()}
+	for name, value := range changes {
+		if value == nil {
+			delete(c, name)
+		} else {
+			c[name] = value
+		}
+	}
+	return c
+}
+
+func TestVerify(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	verifier := &Verifier{
+		Keys: &KeySet{Keys: []Key{
+			{ID: "hmac", Alg: HS256, Secret: secret},
+			{ID: "rsa", Alg: RS256, Public: &private.PublicKey},
+		}},
+		Issuer:   "issuer",
+		Audience: "sales",
+		Leeway:   30 * time.Second,
+	}
+	hsHeader := map[string]any{"alg": HS256, "typ": "JWT", "kid": "hmac"}
+	rsHeader := map[string]any{"alg": RS256, "typ": "JWT", "kid": "rsa"}
+	valid := token(t, hsHeader, claims(nil), hs256(secret))
+	parts := strings.Split(valid, ".")
+
+	for _, test := range []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"an HS256 token", valid, nil},
+		{"an RS256 token", token(t, rsHeader, claims(nil), rs256(t, private)), nil},
+		{"a token without kid", token(t, map[string]any{"alg": HS256}, claims(nil), hs256(secret)), nil},
+
+		{"alg none", token(t, map[string]any{"alg": "none"}, claims(nil), nil), ErrAlgorithm},
+		{"alg none with a kid", token(t, map[string]any{"alg": "none", "kid": "hmac"}, claims(nil), nil), ErrAlgorithm},
+		{"an unsupported alg", token(t, map[string]any{"alg": "HS512", "kid": "hmac"}, claims(nil), hs256(secret)), ErrAlgorithm},
+		{"HS256 signed with the RSA public key of its kid", token(t, map[string]any{"alg": HS256, "kid": "rsa"}, claims(nil), hs256(publicPEM)), ErrUnknownKey},
+		{"HS256 signed with the RSA public key", token(t, map[string]any{"alg": HS256}, claims(nil), hs256(publicPEM)), ErrSignature},
+		{"RS256 signed with the HMAC secret", token(t, map[string]any{"alg": RS256}, claims(nil), hs256(secret)), ErrSignature},
+
+		{"no exp", token(t, hsHeader, claims(map[string]any{"exp": nil}), hs256(secret)), ErrNoExpiry},
+		{"expired within the leeway", token(t, hsHeader, claims(map[string]any{"exp": now.Add(-29 * time.Second).Unix()}), hs256(secret)), nil},
+		{"expired by the leeway", token(t, hsHeader, claims(map[string]any{"exp": now.Add(-30 * time.Second).Unix()}), hs256(secret)), ErrExpired},
+		{"expired past the leeway", token(t, hsHeader, claims(map[string]any{"exp": now.Add(-time.Hour).Unix()}), hs256(secret)), ErrExpired},
+		{"nbf within the leeway", token(t, hsHeader, claims(map[string]any{"nbf": now.Add(29 * time.Second).Unix()}), hs256(secret)), nil},
+		{"nbf in the future", token(t, hsHeader, claims(map[string]any{"nbf": now.Add(31 * time.Second).Unix()}), hs256(secret)), ErrNotYetValid},
+		{"a malformed exp", token(t, hsHeader, claims(map[string]any{"exp": "tomorrow"}), hs256(secret)), ErrMalformed},
+
+		{"an unknown kid", token(t, map[string]any{"alg": HS256, "kid": "other"}, claims(nil), hs256(secret)), ErrUnknownKey},
+		{"a tampered payload", parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin","exp":9999999999}`)) + "." + parts[2], ErrSignature},
+		{"a tampered signature", parts[0] + "." + parts[1] + "." + base64.RawURLEncoding.EncodeToString(hs256([]byte("another secret"))([]byte(parts[0]+"."+parts[1]))), ErrSignature},
+		{"a truncated token", parts[0] + "." + parts[1], ErrMalformed},
+
+		{"aud as an array", token(t, hsHeader, claims(map[string]any{"aud": []string{"other", "sales"}}), hs256(secret)), nil},
+		{"another aud", token(t, hsHeader, claims(map[string]any{"aud": "other"}), hs256(secret)), ErrAudience},
+		{"another aud array", token(t, hsHeader, claims(map[string]any{"aud": []string{"other"}}), hs256(secret)), ErrAudience},
+		{"no aud", token(t, hsHeader, claims(map[string]any{"aud": nil}), hs256(secret)), ErrAudience},
+		{"another iss", token(t, hsHeader, claims(map[string]any{"iss": "other"}), hs256(secret)), ErrIssuer},
+		{"no iss", token(t, hsHeader, claims(map[string]any{"iss": nil}), hs256(secret)), ErrIssuer},
+	} {
+		if _, err := verifier.Verify(test.token, now); !errors.Is(err, test.err) {
+			t.Errorf("%s: %v, want %v", test.name, err, test.err)
+		}
+	}
+}
+
+func TestVerifyClaims(t *testing.T) {
+	verifier := &Verifier{Keys: &KeySet{Keys: []Key{{Alg: HS256, Secret: secret}}}}
+
+	for name, aud := range map[string]any{"a string": "sales", "an array": []string{"sales", "reports"}} {
+		signed := token(t, map[string]any{"alg": HS256}, claims(map[string]any{"aud": aud, "nbf": now.Unix(), "roles": []string{"viewer"}}), hs256(secret))
+		got, err := verifier.Verify(signed, now)
+		if err != nil {
+			t.Fatalf("aud as %s: %v", name, err)
+		}
+		if got.Subject != "alice" || got.Issuer != "issuer" || got.Audience[0] != "sales" || !got.ExpiresAt.Equal(now.Add(time.Hour)) || !got.NotBefore.Equal(now) {
+			t.Errorf("aud as %s: claims %+v", name, got)
+		}
+		if string(got.Raw["roles"]) != `["viewer"]` {
+			t.Errorf("aud as %s: raw roles %s", name, got.Raw["roles"])
+		}
+	}
+
+	// issuer and audience are only checked when the verifier names them
+	signed := token(t, map[string]any{"alg": HS256}, claims(map[string]any{"iss": nil, "aud": nil}), hs256(secret))
+	if _, err := verifier.Verify(signed, now); err != nil {
+		t.Errorf("no iss or aud without expectations: %v", err)
+	}
+}
+
+func TestParseKeySet(t *testing.T) {
+	set, err := ParseKeySet([]byte(`{"keys": [
+		{"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"},
+		{"kty": "RSA", "kid": "rsa", "alg": "RS256", "n": "AQAB", "e": "AQAB"},
+		{"kty": "RSA", "kid": "encryption", "use": "enc", "n": "AQAB", "e": "AQAB"}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, key := range set.Keys {
+		ids = append(ids, key.ID+":"+key.Alg)
+	}
+	if !slices.Equal(ids, []string{"hmac:HS256", "rsa:RS256"}) {
+		t.Errorf("keys %v, want the signing keys only", ids)
+	}
+
+	for name, document := range map[string]string{
+		"an oct key for RS256": `{"keys": [{"kty": "oct", "alg": "RS256", "k": "c2VjcmV0"}]}`,
+		"an empty secret":      `{"keys": [{"kty": "oct", "k": ""}]}`,
+		"an unknown key type":  `{"keys": [{"kty": "EC", "crv": "P-256"}]}`,
+		"malformed JSON":       `{"keys": [`,
+	} {
+		if _, err := ParseKeySet([]byte(document)); err == nil {
+			t.Errorf("%s was accepted", name)
+		}
+	}
+}
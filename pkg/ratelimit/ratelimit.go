@@ -0,0 +1,115 @@
+// Package ratelimit limits request rates with a token bucket per client key.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a sustained rate and the burst allowed on top of it. A zero PerMinute means unlimited.
+type Limit struct {
+	PerMinute float64 `json:"per_minute"`
+	Burst     int     `json:"burst"`
+}
+
+// Unlimited reports whether the limit lets every request through.
+func (l Limit) Unlimited() bool {
+	return l.PerMinute == 0
+}
+
+// Window is how long an empty bucket takes to refill completely.
+func (l Limit) Window() time.Duration {
+	return l.refill(float64(l.Burst))
+}
+
+// refill is how long the bucket takes to gain tokens.
+func (l Limit) refill(tokens float64) time.Duration {
+	return time.Duration(tokens / l.PerMinute * float64(time.Minute))
+}
+
+// Result is the outcome of a request against its bucket.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is how long until a denied request would be allowed
+	RetryAfter time.Duration
+}
+
+// sweepInterval is how often buckets that refilled completely are dropped.
+const sweepInterval = time.Minute
+
+// Limiter holds one token bucket per client key. It is safe for concurrent use.
+type Limiter struct {
+	limit     Limit
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// New returns a limiter granting every key limit.
+func New(limit Limit) *Limiter {
+	return &Limiter{limit: limit, buckets: map[string]*bucket{}}
+}
+
+// Limit returns the limit granted to every key.
+func (l *Limiter) Limit() Limit {
+	return l.limit
+}
+
+// Allow takes a token from the bucket of key if one is left.
+func (l *Limiter) Allow(key string, now time.Time) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	capacity := float64(l.limit.Burst)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.tokensAt(b, now)
+	b.updated = now
+
+	result := Result{Limit: l.limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = l.limit.refill(1 - b.tokens)
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = l.limit.refill(capacity - b.tokens)
+	return result
+}
+
+// tokensAt refills b up to the burst for the time elapsed since its last use.
+func (l *Limiter) tokensAt(b *bucket, now time.Time) float64 {
+	elapsed := now.Sub(b.updated).Minutes()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(l.limit.Burst), b.tokens+elapsed*l.limit.PerMinute)
+}
+
+// sweep drops full buckets, which behave exactly like missing ones, so idle clients don't pile up.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if l.tokensAt(b, now) >= float64(l.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
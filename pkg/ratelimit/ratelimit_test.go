@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+// perSecond refills one token a second on top of a burst of three.
+var perSecond = Limit{PerMinute: 60, Burst: 3}
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// near reports whether got is within a millisecond of want, absorbing float rounding of the refill.
+func near(got, want time.Duration) bool {
+	return got > want-time.Millisecond && got < want+time.Millisecond
+}
+
+func TestAllowBurst(t *testing.T) {
+	limiter := New(perSecond)
+	for i, want := range []struct {
+		remaining int
+		reset     time.Duration
+	}{
+		{2, time.Second},
+		{1, 2 * time.Second},
+		{0, 3 * time.Second},
+	} {
+		result := limiter.Allow("client", epoch)
+		if !result.Allowed || result.Limit != 3 || result.Remaining != want.remaining || !near(result.Reset, want.reset) || result.RetryAfter != 0 {
+			t.Errorf("request %d: %+v, want allowed with %d remaining and a reset of %v", i+1, result, want.remaining, want.reset)
+		}
+	}
+
+	result := limiter.Allow("client", epoch)
+	if result.Allowed || result.Remaining != 0 || !near(result.RetryAfter, time.Second) || !near(result.Reset, 3*time.Second) {
+		t.Errorf("request past the burst: %+v, want denied, retried after 1s and reset after 3s", result)
+	}
+	if result := limiter.Allow("other", epoch); !result.Allowed || result.Remaining != 2 {
+		t.Errorf("another key: %+v, want its own full bucket", result)
+	}
+}
+
+func TestAllowRefill(t *testing.T) {
+	limiter := New(perSecond)
+	for range perSecond.Burst {
+		limiter.Allow("client", epoch)
+	}
+
+	result := limiter.Allow("client", epoch.Add(250*time.Millisecond))
+	if result.Allowed || !near(result.RetryAfter, 750*time.Millisecond) || !near(result.Reset, 2750*time.Millisecond) {
+		t.Errorf("after 250ms: %+v, want denied, retried after 750ms and reset after 2.75s", result)
+	}
+	// denied requests don't take tokens, so the refill carries on
+	result = limiter.Allow("client", epoch.Add(1100*time.Millisecond))
+	if !result.Allowed || result.Remaining != 0 {
+		t.Errorf("after 1.1s: %+v, want one request allowed", result)
+	}
+	// the bucket never holds more than the burst
+	result = limiter.Allow("client", epoch.Add(time.Hour))
+	if !result.Allowed || result.Remaining != 2 || !near(result.Reset, time.Second) {
+		t.Errorf("after an hour: %+v, want a full bucket", result)
+	}
+	// a clock going backwards refills nothing
+	result = limiter.Allow("client", epoch)
+	if !result.Allowed || result.Remaining != 1 {
+		t.Errorf("earlier clock: %+v, want the bucket as it was", result)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	limiter := New(perSecond)
+	limiter.Allow("idle", epoch)
+	limiter.Allow("busy", epoch)
+	for range perSecond.Burst {
+		limiter.Allow("busy", epoch.Add(sweepInterval-time.Second))
+	}
+
+	limiter.Allow("new", epoch.Add(sweepInterval))
+	if _, ok := limiter.buckets["idle"]; ok {
+		t.Error("the full bucket of an idle key was kept")
+	}
+	if _, ok := limiter.buckets["busy"]; !ok {
+		t.Error("a bucket still refilling was dropped")
+	}
+}
+
+func TestLimit(t *testing.T) {
+	if !(Limit{}).Unlimited() || perSecond.Unlimited() {
+		t.Error("only a zero per-minute rate is unlimited")
+	}
+	if window := perSecond.Window(); !near(window, 3*time.Second) {
+		t.Errorf("window %v, want 3s", window)
+	}
+}
@@ -0,0 +1,4 @@
+// Package salespb holds the protobuf messages and gRPC stubs generated from proto/sales/v1/sales.proto.
+package salespb
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=sales --go-grpc_out=../.. --go-grpc_opt=module=sales sales/v1/sales.proto
@@ -0,0 +1,1384 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        v5.28.3
+// source: sales/v1/sales.proto
+
+package salespb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GroupDimension int32
+
+const (
+	GroupDimension_GROUP_DIMENSION_UNSPECIFIED GroupDimension = 0
+	GroupDimension_GROUP_DIMENSION_CATEGORY    GroupDimension = 1
+	GroupDimension_GROUP_DIMENSION_REGION      GroupDimension = 2
+)
+
+// Enum value maps for GroupDimension.
+var (
+	GroupDimension_name = map[int32]string{
+		0: "GROUP_DIMENSION_UNSPECIFIED",
+		1: "GROUP_DIMENSION_CATEGORY",
+		2: "GROUP_DIMENSION_REGION",
+	}
+	GroupDimension_value = map[string]int32{
+		"GROUP_DIMENSION_UNSPECIFIED": 0,
+		"GROUP_DIMENSION_CATEGORY":    1,
+		"GROUP_DIMENSION_REGION":      2,
+	}
+)
+
+func (x GroupDimension) Enum() *GroupDimension {
+	p := new(GroupDimension)
+	*p = x
+	return p
+}
+
+func (x GroupDimension) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GroupDimension) Descriptor() protoreflect.EnumDescriptor {
+	return file_sales_v1_sales_proto_enumTypes[0].Descriptor()
+}
+
+func (GroupDimension) Type() protoreflect.EnumType {
+	return &file_sales_v1_sales_proto_enumTypes[0]
+}
+
+func (x GroupDimension) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GroupDimension.Descriptor instead.
+func (GroupDimension) EnumDescriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{0}
+}
+
+// Inclusive dates (YYYY-MM-DD) whose day boundaries follow the IANA timezone tz, UTC when empty.
+type DateRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartDate     string                 `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string                 `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Tz            string                 `protobuf:"bytes,3,opt,name=tz,proto3" json:"tz,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DateRange) Reset() {
+	*x = DateRange{}
+	mi := &file_sales_v1_sales_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DateRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DateRange) ProtoMessage() {}
+
+func (x *DateRange) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DateRange.ProtoReflect.Descriptor instead.
+func (*DateRange) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DateRange) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *DateRange) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *DateRange) GetTz() string {
+	if x != nil {
+		return x.Tz
+	}
+	return ""
+}
+
+// Optional sales filters; repeated values match any of them.
+type SalesFilter struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Categories     []string               `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	Regions        []string               `protobuf:"bytes,2,rep,name=regions,proto3" json:"regions,omitempty"`
+	PaymentMethods []string               `protobuf:"bytes,3,rep,name=payment_methods,json=paymentMethods,proto3" json:"payment_methods,omitempty"`
+	CustomerIds    []string               `protobuf:"bytes,4,rep,name=customer_ids,json=customerIds,proto3" json:"customer_ids,omitempty"`
+	MinPrice       *float64               `protobuf:"fixed64,5,opt,name=min_price,json=minPrice,proto3,oneof" json:"min_price,omitempty"`
+	MaxPrice       *float64               `protobuf:"fixed64,6,opt,name=max_price,json=maxPrice,proto3,oneof" json:"max_price,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SalesFilter) Reset() {
+	*x = SalesFilter{}
+	mi := &file_sales_v1_sales_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SalesFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SalesFilter) ProtoMessage() {}
+
+func (x *SalesFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SalesFilter.ProtoReflect.Descriptor instead.
+func (*SalesFilter) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SalesFilter) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *SalesFilter) GetRegions() []string {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+func (x *SalesFilter) GetPaymentMethods() []string {
+	if x != nil {
+		return x.PaymentMethods
+	}
+	return nil
+}
+
+func (x *SalesFilter) GetCustomerIds() []string {
+	if x != nil {
+		return x.CustomerIds
+	}
+	return nil
+}
+
+func (x *SalesFilter) GetMinPrice() float64 {
+	if x != nil && x.MinPrice != nil {
+		return *x.MinPrice
+	}
+	return 0
+}
+
+func (x *SalesFilter) GetMaxPrice() float64 {
+	if x != nil && x.MaxPrice != nil {
+		return *x.MaxPrice
+	}
+	return 0
+}
+
+type GetTopProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	N             int32                  `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
+	Range         *DateRange             `protobuf:"bytes,2,opt,name=range,proto3" json:"range,omitempty"`
+	Filter        *SalesFilter           `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopProductsRequest) Reset() {
+	*x = GetTopProductsRequest{}
+	mi := &file_sales_v1_sales_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopProductsRequest) ProtoMessage() {}
+
+func (x *GetTopProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopProductsRequest.ProtoReflect.Descriptor instead.
+func (*GetTopProductsRequest) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetTopProductsRequest) GetN() int32 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+func (x *GetTopProductsRequest) GetRange() *DateRange {
+	if x != nil {
+		return x.Range
+	}
+	return nil
+}
+
+func (x *GetTopProductsRequest) GetFilter() *SalesFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type GetTopProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopProductsResponse) Reset() {
+	*x = GetTopProductsResponse{}
+	mi := &file_sales_v1_sales_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopProductsResponse) ProtoMessage() {}
+
+func (x *GetTopProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopProductsResponse.ProtoReflect.Descriptor instead.
+func (*GetTopProductsResponse) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetTopProductsResponse) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+type StreamTopProductsByGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dimension     GroupDimension         `protobuf:"varint,1,opt,name=dimension,proto3,enum=sales.v1.GroupDimension" json:"dimension,omitempty"`
+	N             int32                  `protobuf:"varint,2,opt,name=n,proto3" json:"n,omitempty"`
+	Range         *DateRange             `protobuf:"bytes,3,opt,name=range,proto3" json:"range,omitempty"`
+	Filter        *SalesFilter           `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTopProductsByGroupRequest) Reset() {
+	*x = StreamTopProductsByGroupRequest{}
+	mi := &file_sales_v1_sales_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTopProductsByGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTopProductsByGroupRequest) ProtoMessage() {}
+
+func (x *StreamTopProductsByGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTopProductsByGroupRequest.ProtoReflect.Descriptor instead.
+func (*StreamTopProductsByGroupRequest) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StreamTopProductsByGroupRequest) GetDimension() GroupDimension {
+	if x != nil {
+		return x.Dimension
+	}
+	return GroupDimension_GROUP_DIMENSION_UNSPECIFIED
+}
+
+func (x *StreamTopProductsByGroupRequest) GetN() int32 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+func (x *StreamTopProductsByGroupRequest) GetRange() *DateRange {
+	if x != nil {
+		return x.Range
+	}
+	return nil
+}
+
+func (x *StreamTopProductsByGroupRequest) GetFilter() *SalesFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type ProductGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Products      []*Product             `protobuf:"bytes,2,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductGroup) Reset() {
+	*x = ProductGroup{}
+	mi := &file_sales_v1_sales_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductGroup) ProtoMessage() {}
+
+func (x *ProductGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductGroup.ProtoReflect.Descriptor instead.
+func (*ProductGroup) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ProductGroup) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ProductGroup) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+type RefreshDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshDataRequest) Reset() {
+	*x = RefreshDataRequest{}
+	mi := &file_sales_v1_sales_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshDataRequest) ProtoMessage() {}
+
+func (x *RefreshDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshDataRequest.ProtoReflect.Descriptor instead.
+func (*RefreshDataRequest) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{6}
+}
+
+type RefreshDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshedAt   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=refreshed_at,json=refreshedAt,proto3" json:"refreshed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshDataResponse) Reset() {
+	*x = RefreshDataResponse{}
+	mi := &file_sales_v1_sales_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshDataResponse) ProtoMessage() {}
+
+func (x *RefreshDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshDataResponse.ProtoReflect.Descriptor instead.
+func (*RefreshDataResponse) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RefreshDataResponse) GetRefreshedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RefreshedAt
+	}
+	return nil
+}
+
+type GetProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductRequest) Reset() {
+	*x = GetProductRequest{}
+	mi := &file_sales_v1_sales_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductRequest) ProtoMessage() {}
+
+func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductRequest.ProtoReflect.Descriptor instead.
+func (*GetProductRequest) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetProductRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type GetCustomerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCustomerRequest) Reset() {
+	*x = GetCustomerRequest{}
+	mi := &file_sales_v1_sales_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCustomerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCustomerRequest) ProtoMessage() {}
+
+func (x *GetCustomerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCustomerRequest.ProtoReflect.Descriptor instead.
+func (*GetCustomerRequest) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetCustomerRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+type GetOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderRequest) Reset() {
+	*x = GetOrderRequest{}
+	mi := &file_sales_v1_sales_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderRequest) ProtoMessage() {}
+
+func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+// Sort order of a stream; sort_by takes the sortable fields of the REST list endpoints.
+type StreamProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SortBy        string                 `protobuf:"bytes,1,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	Desc          bool                   `protobuf:"varint,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamProductsRequest) Reset() {
+	*x = StreamProductsRequest{}
+	mi := &file_sales_v1_sales_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamProductsRequest) ProtoMessage() {}
+
+func (x *StreamProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamProductsRequest.ProtoReflect.Descriptor instead.
+func (*StreamProductsRequest) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *StreamProductsRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *StreamProductsRequest) GetDesc() bool {
+	if x != nil {
+		return x.Desc
+	}
+	return false
+}
+
+type StreamCustomersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SortBy        string                 `protobuf:"bytes,1,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	Desc          bool                   `protobuf:"varint,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamCustomersRequest) Reset() {
+	*x = StreamCustomersRequest{}
+	mi := &file_sales_v1_sales_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamCustomersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamCustomersRequest) ProtoMessage() {}
+
+func (x *StreamCustomersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamCustomersRequest.ProtoReflect.Descriptor instead.
+func (*StreamCustomersRequest) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StreamCustomersRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *StreamCustomersRequest) GetDesc() bool {
+	if x != nil {
+		return x.Desc
+	}
+	return false
+}
+
+type StreamOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SortBy        string                 `protobuf:"bytes,1,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	Desc          bool                   `protobuf:"varint,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOrdersRequest) Reset() {
+	*x = StreamOrdersRequest{}
+	mi := &file_sales_v1_sales_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOrdersRequest) ProtoMessage() {}
+
+func (x *StreamOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOrdersRequest.ProtoReflect.Descriptor instead.
+func (*StreamOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StreamOrdersRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *StreamOrdersRequest) GetDesc() bool {
+	if x != nil {
+		return x.Desc
+	}
+	return false
+}
+
+type Product struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ProductName   string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	Category      string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	UnitPrice     float64                `protobuf:"fixed64,4,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_sales_v1_sales_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Product) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *Product) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *Product) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Product) GetUnitPrice() float64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+type Customer struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId      string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	CustomerName    string                 `protobuf:"bytes,2,opt,name=customer_name,json=customerName,proto3" json:"customer_name,omitempty"`
+	CustomerEmail   string                 `protobuf:"bytes,3,opt,name=customer_email,json=customerEmail,proto3" json:"customer_email,omitempty"`
+	CustomerAddress string                 `protobuf:"bytes,4,opt,name=customer_address,json=customerAddress,proto3" json:"customer_address,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Customer) Reset() {
+	*x = Customer{}
+	mi := &file_sales_v1_sales_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Customer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Customer) ProtoMessage() {}
+
+func (x *Customer) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Customer.ProtoReflect.Descriptor instead.
+func (*Customer) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Customer) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *Customer) GetCustomerName() string {
+	if x != nil {
+		return x.CustomerName
+	}
+	return ""
+}
+
+func (x *Customer) GetCustomerEmail() string {
+	if x != nil {
+		return x.CustomerEmail
+	}
+	return ""
+}
+
+func (x *Customer) GetCustomerAddress() string {
+	if x != nil {
+		return x.CustomerAddress
+	}
+	return ""
+}
+
+type Order struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	CustomerId    string                 `protobuf:"bytes,2,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	DateOfSale    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=date_of_sale,json=dateOfSale,proto3" json:"date_of_sale,omitempty"`
+	ShippingCost  float64                `protobuf:"fixed64,4,opt,name=shipping_cost,json=shippingCost,proto3" json:"shipping_cost,omitempty"`
+	PaymentMethod string                 `protobuf:"bytes,5,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	Region        string                 `protobuf:"bytes,6,opt,name=region,proto3" json:"region,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_sales_v1_sales_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Order) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Order) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *Order) GetDateOfSale() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateOfSale
+	}
+	return nil
+}
+
+func (x *Order) GetShippingCost() float64 {
+	if x != nil {
+		return x.ShippingCost
+	}
+	return 0
+}
+
+func (x *Order) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *Order) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+type OrderItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderItemId   uint64                 `protobuf:"varint,1,opt,name=order_item_id,json=orderItemId,proto3" json:"order_item_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	QuantitySold  int32                  `protobuf:"varint,4,opt,name=quantity_sold,json=quantitySold,proto3" json:"quantity_sold,omitempty"`
+	Discount      float64                `protobuf:"fixed64,5,opt,name=discount,proto3" json:"discount,omitempty"`
+	Product       *Product               `protobuf:"bytes,6,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderItem) Reset() {
+	*x = OrderItem{}
+	mi := &file_sales_v1_sales_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItem) ProtoMessage() {}
+
+func (x *OrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
+func (*OrderItem) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *OrderItem) GetOrderItemId() uint64 {
+	if x != nil {
+		return x.OrderItemId
+	}
+	return 0
+}
+
+func (x *OrderItem) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *OrderItem) GetQuantitySold() int32 {
+	if x != nil {
+		return x.QuantitySold
+	}
+	return 0
+}
+
+func (x *OrderItem) GetDiscount() float64 {
+	if x != nil {
+		return x.Discount
+	}
+	return 0
+}
+
+func (x *OrderItem) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type OrderDetails struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Customer      *Customer              `protobuf:"bytes,2,opt,name=customer,proto3" json:"customer,omitempty"`
+	Items         []*OrderItem           `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderDetails) Reset() {
+	*x = OrderDetails{}
+	mi := &file_sales_v1_sales_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderDetails) ProtoMessage() {}
+
+func (x *OrderDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_sales_v1_sales_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderDetails.ProtoReflect.Descriptor instead.
+func (*OrderDetails) Descriptor() ([]byte, []int) {
+	return file_sales_v1_sales_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *OrderDetails) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderDetails) GetCustomer() *Customer {
+	if x != nil {
+		return x.Customer
+	}
+	return nil
+}
+
+func (x *OrderDetails) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+var File_sales_v1_sales_proto protoreflect.FileDescriptor
+
+const file_sales_v1_sales_proto_rawDesc = "" +
+	"\n" +
+	"\x14sales/v1/sales.proto\x12\bsales.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"U\n" +
+	"\tDateRange\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x02 \x01(\tR\aendDate\x12\x0e\n" +
+	"\x02tz\x18\x03 \x01(\tR\x02tz\"\xf3\x01\n" +
+	"\vSalesFilter\x12\x1e\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\tR\n" +
+	"categories\x12\x18\n" +
+	"\aregions\x18\x02 \x03(\tR\aregions\x12'\n" +
+	"\x0fpayment_methods\x18\x03 \x03(\tR\x0epaymentMethods\x12!\n" +
+	"\fcustomer_ids\x18\x04 \x03(\tR\vcustomerIds\x12 \n" +
+	"\tmin_price\x18\x05 \x01(\x01H\x00R\bminPrice\x88\x01\x01\x12 \n" +
+	"\tmax_price\x18\x06 \x01(\x01H\x01R\bmaxPrice\x88\x01\x01B\f\n" +
+	"\n" +
+	"_min_priceB\f\n" +
+	"\n" +
+	"_max_price\"\x7f\n" +
+	"\x15GetTopProductsRequest\x12\f\n" +
+	"\x01n\x18\x01 \x01(\x05R\x01n\x12)\n" +
+	"\x05range\x18\x02 \x01(\v2\x13.sales.v1.DateRangeR\x05range\x12-\n" +
+	"\x06filter\x18\x03 \x01(\v2\x15.sales.v1.SalesFilterR\x06filter\"G\n" +
+	"\x16GetTopProductsResponse\x12-\n" +
+	"\bproducts\x18\x01 \x03(\v2\x11.sales.v1.ProductR\bproducts\"\xc1\x01\n" +
+	"\x1fStreamTopProductsByGroupRequest\x126\n" +
+	"\tdimension\x18\x01 \x01(\x0e2\x18.sales.v1.GroupDimensionR\tdimension\x12\f\n" +
+	"\x01n\x18\x02 \x01(\x05R\x01n\x12)\n" +
+	"\x05range\x18\x03 \x01(\v2\x13.sales.v1.DateRangeR\x05range\x12-\n" +
+	"\x06filter\x18\x04 \x01(\v2\x15.sales.v1.SalesFilterR\x06filter\"O\n" +
+	"\fProductGroup\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12-\n" +
+	"\bproducts\x18\x02 \x03(\v2\x11.sales.v1.ProductR\bproducts\"\x14\n" +
+	"\x12RefreshDataRequest\"T\n" +
+	"\x13RefreshDataResponse\x12=\n" +
+	"\frefreshed_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\vrefreshedAt\"2\n" +
+	"\x11GetProductRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"5\n" +
+	"\x12GetCustomerRequest\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\tR\n" +
+	"customerId\",\n" +
+	"\x0fGetOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"D\n" +
+	"\x15StreamProductsRequest\x12\x17\n" +
+	"\asort_by\x18\x01 \x01(\tR\x06sortBy\x12\x12\n" +
+	"\x04desc\x18\x02 \x01(\bR\x04desc\"E\n" +
+	"\x16StreamCustomersRequest\x12\x17\n" +
+	"\asort_by\x18\x01 \x01(\tR\x06sortBy\x12\x12\n" +
+	"\x04desc\x18\x02 \x01(\bR\x04desc\"B\n" +
+	"\x13StreamOrdersRequest\x12\x17\n" +
+	"\asort_by\x18\x01 \x01(\tR\x06sortBy\x12\x12\n" +
+	"\x04desc\x18\x02 \x01(\bR\x04desc\"\x86\x01\n" +
+	"\aProduct\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12!\n" +
+	"\fproduct_name\x18\x02 \x01(\tR\vproductName\x12\x1a\n" +
+	"\bcategory\x18\x03 \x01(\tR\bcategory\x12\x1d\n" +
+	"\n" +
+	"unit_price\x18\x04 \x01(\x01R\tunitPrice\"\xa2\x01\n" +
+	"\bCustomer\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\tR\n" +
+	"customerId\x12#\n" +
+	"\rcustomer_name\x18\x02 \x01(\tR\fcustomerName\x12%\n" +
+	"\x0ecustomer_email\x18\x03 \x01(\tR\rcustomerEmail\x12)\n" +
+	"\x10customer_address\x18\x04 \x01(\tR\x0fcustomerAddress\"\xe5\x01\n" +
+	"\x05Order\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x1f\n" +
+	"\vcustomer_id\x18\x02 \x01(\tR\n" +
+	"customerId\x12<\n" +
+	"\fdate_of_sale\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"dateOfSale\x12#\n" +
+	"\rshipping_cost\x18\x04 \x01(\x01R\fshippingCost\x12%\n" +
+	"\x0epayment_method\x18\x05 \x01(\tR\rpaymentMethod\x12\x16\n" +
+	"\x06region\x18\x06 \x01(\tR\x06region\"\xd7\x01\n" +
+	"\tOrderItem\x12\"\n" +
+	"\rorder_item_id\x18\x01 \x01(\x04R\vorderItemId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\tR\tproductId\x12#\n" +
+	"\rquantity_sold\x18\x04 \x01(\x05R\fquantitySold\x12\x1a\n" +
+	"\bdiscount\x18\x05 \x01(\x01R\bdiscount\x12+\n" +
+	"\aproduct\x18\x06 \x01(\v2\x11.sales.v1.ProductR\aproduct\"\x90\x01\n" +
+	"\fOrderDetails\x12%\n" +
+	"\x05order\x18\x01 \x01(\v2\x0f.sales.v1.OrderR\x05order\x12.\n" +
+	"\bcustomer\x18\x02 \x01(\v2\x12.sales.v1.CustomerR\bcustomer\x12)\n" +
+	"\x05items\x18\x03 \x03(\v2\x13.sales.v1.OrderItemR\x05items*k\n" +
+	"\x0eGroupDimension\x12\x1f\n" +
+	"\x1bGROUP_DIMENSION_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18GROUP_DIMENSION_CATEGORY\x10\x01\x12\x1a\n" +
+	"\x16GROUP_DIMENSION_REGION\x10\x022\xa3\x05\n" +
+	"\fSalesService\x12S\n" +
+	"\x0eGetTopProducts\x12\x1f.sales.v1.GetTopProductsRequest\x1a .sales.v1.GetTopProductsResponse\x12_\n" +
+	"\x18StreamTopProductsByGroup\x12).sales.v1.StreamTopProductsByGroupRequest\x1a\x16.sales.v1.ProductGroup0\x01\x12J\n" +
+	"\vRefreshData\x12\x1c.sales.v1.RefreshDataRequest\x1a\x1d.sales.v1.RefreshDataResponse\x12<\n" +
+	"\n" +
+	"GetProduct\x12\x1b.sales.v1.GetProductRequest\x1a\x11.sales.v1.Product\x12?\n" +
+	"\vGetCustomer\x12\x1c.sales.v1.GetCustomerRequest\x1a\x12.sales.v1.Customer\x12=\n" +
+	"\bGetOrder\x12\x19.sales.v1.GetOrderRequest\x1a\x16.sales.v1.OrderDetails\x12F\n" +
+	"\x0eStreamProducts\x12\x1f.sales.v1.StreamProductsRequest\x1a\x11.sales.v1.Product0\x01\x12I\n" +
+	"\x0fStreamCustomers\x12 .sales.v1.StreamCustomersRequest\x1a\x12.sales.v1.Customer0\x01\x12@\n" +
+	"\fStreamOrders\x12\x1d.sales.v1.StreamOrdersRequest\x1a\x0f.sales.v1.Order0\x01B\x13Z\x11sales/pkg/salespbb\x06proto3"
+
+var (
+	file_sales_v1_sales_proto_rawDescOnce sync.Once
+	file_sales_v1_sales_proto_rawDescData []byte
+)
+
+func file_sales_v1_sales_proto_rawDescGZIP() []byte {
+	file_sales_v1_sales_proto_rawDescOnce.Do(func() {
+		file_sales_v1_sales_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_sales_v1_sales_proto_rawDesc), len(file_sales_v1_sales_proto_rawDesc)))
+	})
+	return file_sales_v1_sales_proto_rawDescData
+}
+
+var file_sales_v1_sales_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_sales_v1_sales_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_sales_v1_sales_proto_goTypes = []any{
+	(GroupDimension)(0),                     // 0: sales.v1.GroupDimension
+	(*DateRange)(nil),                       // 1: sales.v1.DateRange
+	(*SalesFilter)(nil),                     // 2: sales.v1.SalesFilter
+	(*GetTopProductsRequest)(nil),           // 3: sales.v1.GetTopProductsRequest
+	(*GetTopProductsResponse)(nil),          // 4: sales.v1.GetTopProductsResponse
+	(*StreamTopProductsByGroupRequest)(nil), // 5: sales.v1.StreamTopProductsByGroupRequest
+	(*ProductGroup)(nil),                    // 6: sales.v1.ProductGroup
+	(*RefreshDataRequest)(nil),              // 7: sales.v1.RefreshDataRequest
+	(*RefreshDataResponse)(nil),             // 8: sales.v1.RefreshDataResponse
+	(*GetProductRequest)(nil),               // 9: sales.v1.GetProductRequest
+	(*GetCustomerRequest)(nil),              // 10: sales.v1.GetCustomerRequest
+	(*GetOrderRequest)(nil),                 // 11: sales.v1.GetOrderRequest
+	(*StreamProductsRequest)(nil),           // 12: sales.v1.StreamProductsRequest
+	(*StreamCustomersRequest)(nil),          // 13: sales.v1.StreamCustomersRequest
+	(*StreamOrdersRequest)(nil),             // 14: sales.v1.StreamOrdersRequest
+	(*Product)(nil),                         // 15: sales.v1.Product
+	(*Customer)(nil),                        // 16: sales.v1.Customer
+	(*Order)(nil),                           // 17: sales.v1.Order
+	(*OrderItem)(nil),                       // 18: sales.v1.OrderItem
+	(*OrderDetails)(nil),                    // 19: sales.v1.OrderDetails
+	(*timestamppb.Timestamp)(nil),           // 20: google.protobuf.Timestamp
+}
+var file_sales_v1_sales_proto_depIdxs = []int32{
+	1,  // 0: sales.v1.GetTopProductsRequest.range:type_name -> sales.v1.DateRange
+	2,  // 1: sales.v1.GetTopProductsRequest.filter:type_name -> sales.v1.SalesFilter
+	15, // 2: sales.v1.GetTopProductsResponse.products:type_name -> sales.v1.Product
+	0,  // 3: sales.v1.StreamTopProductsByGroupRequest.dimension:type_name -> sales.v1.GroupDimension
+	1,  // 4: sales.v1.StreamTopProductsByGroupRequest.range:type_name -> sales.v1.DateRange
+	2,  // 5: sales.v1.StreamTopProductsByGroupRequest.filter:type_name -> sales.v1.SalesFilter
+	15, // 6: sales.v1.ProductGroup.products:type_name -> sales.v1.Product
+	20, // 7: sales.v1.RefreshDataResponse.refreshed_at:type_name -> google.protobuf.Timestamp
+	20, // 8: sales.v1.Order.date_of_sale:type_name -> google.protobuf.Timestamp
+	15, // 9: sales.v1.OrderItem.product:type_name -> sales.v1.Product
+	17, // 10: sales.v1.OrderDetails.order:type_name -> sales.v1.Order
+	16, // 11: sales.v1.OrderDetails.customer:type_name -> sales.v1.Customer
+	18, // 12: sales.v1.OrderDetails.items:type_name -> sales.v1.OrderItem
+	3,  // 13: sales.v1.SalesService.GetTopProducts:input_type -> sales.v1.GetTopProductsRequest
+	5,  // 14: sales.v1.SalesService.StreamTopProductsByGroup:input_type -> sales.v1.StreamTopProductsByGroupRequest
+	7,  // 15: sales.v1.SalesService.RefreshData:input_type -> sales.v1.RefreshDataRequest
+	9,  // 16: sales.v1.SalesService.GetProduct:input_type -> sales.v1.GetProductRequest
+	10, // 17: sales.v1.SalesService.GetCustomer:input_type -> sales.v1.GetCustomerRequest
+	11, // 18: sales.v1.SalesService.GetOrder:input_type -> sales.v1.GetOrderRequest
+	12, // 19: sales.v1.SalesService.StreamProducts:input_type -> sales.v1.StreamProductsRequest
+	13, // 20: sales.v1.SalesService.StreamCustomers:input_type -> sales.v1.StreamCustomersRequest
+	14, // 21: sales.v1.SalesService.StreamOrders:input_type -> sales.v1.StreamOrdersRequest
+	4,  // 22: sales.v1.SalesService.GetTopProducts:output_type -> sales.v1.GetTopProductsResponse
+	6,  // 23: sales.v1.SalesService.StreamTopProductsByGroup:output_type -> sales.v1.ProductGroup
+	8,  // 24: sales.v1.SalesService.RefreshData:output_type -> sales.v1.RefreshDataResponse
+	15, // 25: sales.v1.SalesService.GetProduct:output_type -> sales.v1.Product
+	16, // 26: sales.v1.SalesService.GetCustomer:output_type -> sales.v1.Customer
+	19, // 27: sales.v1.SalesService.GetOrder:output_type -> sales.v1.OrderDetails
+	15, // 28: sales.v1.SalesService.StreamProducts:output_type -> sales.v1.Product
+	16, // 29: sales.v1.SalesService.StreamCustomers:output_type -> sales.v1.Customer
+	17, // 30: sales.v1.SalesService.StreamOrders:output_type -> sales.v1.Order
+	22, // [22:31] is the sub-list for method output_type
+	13, // [13:22] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_sales_v1_sales_proto_init() }
+func file_sales_v1_sales_proto_init() {
+	if File_sales_v1_sales_proto != nil {
+		return
+	}
+	file_sales_v1_sales_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sales_v1_sales_proto_rawDesc), len(file_sales_v1_sales_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_sales_v1_sales_proto_goTypes,
+		DependencyIndexes: file_sales_v1_sales_proto_depIdxs,
+		EnumInfos:         file_sales_v1_sales_proto_enumTypes,
+		MessageInfos:      file_sales_v1_sales_proto_msgTypes,
+	}.Build()
+	File_sales_v1_sales_proto = out.File
+	file_sales_v1_sales_proto_goTypes = nil
+	file_sales_v1_sales_proto_depIdxs = nil
+}
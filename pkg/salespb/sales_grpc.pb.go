@@ -0,0 +1,452 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.1
+// - protoc             v5.28.3
+// source: sales/v1/sales.proto
+
+package salespb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SalesService_GetTopProducts_FullMethodName           = "/sales.v1.SalesService/GetTopProducts"
+	SalesService_StreamTopProductsByGroup_FullMethodName = "/sales.v1.SalesService/StreamTopProductsByGroup"
+	SalesService_RefreshData_FullMethodName              = "/sales.v1.SalesService/RefreshData"
+	SalesService_GetProduct_FullMethodName               = "/sales.v1.SalesService/GetProduct"
+	SalesService_GetCustomer_FullMethodName              = "/sales.v1.SalesService/GetCustomer"
+	SalesService_GetOrder_FullMethodName                 = "/sales.v1.SalesService/GetOrder"
+	SalesService_StreamProducts_FullMethodName           = "/sales.v1.SalesService/StreamProducts"
+	SalesService_StreamCustomers_FullMethodName          = "/sales.v1.SalesService/StreamCustomers"
+	SalesService_StreamOrders_FullMethodName             = "/sales.v1.SalesService/StreamOrders"
+)
+
+// SalesServiceClient is the client API for SalesService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SalesService exposes the top-products queries, the CSV refresh and the entity lookups of the
+// REST API. Calls require one of the API keys in the x-api-key or authorization: Bearer metadata.
+type SalesServiceClient interface {
+	// Top n products by quantity sold.
+	GetTopProducts(ctx context.Context, in *GetTopProductsRequest, opts ...grpc.CallOption) (*GetTopProductsResponse, error)
+	// Top n products by quantity sold per category or region, one group per message.
+	StreamTopProductsByGroup(ctx context.Context, in *StreamTopProductsByGroupRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProductGroup], error)
+	// Reloads the database from the CSV file.
+	RefreshData(ctx context.Context, in *RefreshDataRequest, opts ...grpc.CallOption) (*RefreshDataResponse, error)
+	GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*Product, error)
+	GetCustomer(ctx context.Context, in *GetCustomerRequest, opts ...grpc.CallOption) (*Customer, error)
+	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*OrderDetails, error)
+	// Every product, customer or order, read and sent in pages so the result is never held in memory.
+	StreamProducts(ctx context.Context, in *StreamProductsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Product], error)
+	StreamCustomers(ctx context.Context, in *StreamCustomersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Customer], error)
+	StreamOrders(ctx context.Context, in *StreamOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Order], error)
+}
+
+type salesServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSalesServiceClient(cc grpc.ClientConnInterface) SalesServiceClient {
+	return &salesServiceClient{cc}
+}
+
+func (c *salesServiceClient) GetTopProducts(ctx context.Context, in *GetTopProductsRequest, opts ...grpc.CallOption) (*GetTopProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTopProductsResponse)
+	err := c.cc.Invoke(ctx, SalesService_GetTopProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *salesServiceClient) StreamTopProductsByGroup(ctx context.Context, in *StreamTopProductsByGroupRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProductGroup], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SalesService_ServiceDesc.Streams[0], SalesService_StreamTopProductsByGroup_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamTopProductsByGroupRequest, ProductGroup]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SalesService_StreamTopProductsByGroupClient = grpc.ServerStreamingClient[ProductGroup]
+
+func (c *salesServiceClient) RefreshData(ctx context.Context, in *RefreshDataRequest, opts ...grpc.CallOption) (*RefreshDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefreshDataResponse)
+	err := c.cc.Invoke(ctx, SalesService_RefreshData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *salesServiceClient) GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*Product, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Product)
+	err := c.cc.Invoke(ctx, SalesService_GetProduct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *salesServiceClient) GetCustomer(ctx context.Context, in *GetCustomerRequest, opts ...grpc.CallOption) (*Customer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Customer)
+	err := c.cc.Invoke(ctx, SalesService_GetCustomer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *salesServiceClient) GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*OrderDetails, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderDetails)
+	err := c.cc.Invoke(ctx, SalesService_GetOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *salesServiceClient) StreamProducts(ctx context.Context, in *StreamProductsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Product], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SalesService_ServiceDesc.Streams[1], SalesService_StreamProducts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamProductsRequest, Product]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SalesService_StreamProductsClient = grpc.ServerStreamingClient[Product]
+
+func (c *salesServiceClient) StreamCustomers(ctx context.Context, in *StreamCustomersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Customer], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SalesService_ServiceDesc.Streams[2], SalesService_StreamCustomers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamCustomersRequest, Customer]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SalesService_StreamCustomersClient = grpc.ServerStreamingClient[Customer]
+
+func (c *salesServiceClient) StreamOrders(ctx context.Context, in *StreamOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Order], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SalesService_ServiceDesc.Streams[3], SalesService_StreamOrders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamOrdersRequest, Order]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SalesService_StreamOrdersClient = grpc.ServerStreamingClient[Order]
+
+// SalesServiceServer is the server API for SalesService service.
+// All implementations must embed UnimplementedSalesServiceServer
+// for forward compatibility.
+//
+// SalesService exposes the top-products queries, the CSV refresh and the entity lookups of the
+// REST API. Calls require one of the API keys in the x-api-key or authorization: Bearer metadata.
+type SalesServiceServer interface {
+	// Top n products by quantity sold.
+	GetTopProducts(context.Context, *GetTopProductsRequest) (*GetTopProductsResponse, error)
+	// Top n products by quantity sold per category or region, one group per message.
+	StreamTopProductsByGroup(*StreamTopProductsByGroupRequest, grpc.ServerStreamingServer[ProductGroup]) error
+	// Reloads the database from the CSV file.
+	RefreshData(context.Context, *RefreshDataRequest) (*RefreshDataResponse, error)
+	GetProduct(context.Context, *GetProductRequest) (*Product, error)
+	GetCustomer(context.Context, *GetCustomerRequest) (*Customer, error)
+	GetOrder(context.Context, *GetOrderRequest) (*OrderDetails, error)
+	// Every product, customer or order, read and sent in pages so the result is never held in memory.
+	StreamProducts(*StreamProductsRequest, grpc.ServerStreamingServer[Product]) error
+	StreamCustomers(*StreamCustomersRequest, grpc.ServerStreamingServer[Customer]) error
+	StreamOrders(*StreamOrdersRequest, grpc.ServerStreamingServer[Order]) error
+	mustEmbedUnimplementedSalesServiceServer()
+}
+
+// UnimplementedSalesServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSalesServiceServer struct{}
+
+func (UnimplementedSalesServiceServer) GetTopProducts(context.Context, *GetTopProductsRequest) (*GetTopProductsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTopProducts not implemented")
+}
+func (UnimplementedSalesServiceServer) StreamTopProductsByGroup(*StreamTopProductsByGroupRequest, grpc.ServerStreamingServer[ProductGroup]) error {
+	return status.Error(codes.Unimplemented, "method StreamTopProductsByGroup not implemented")
+}
+func (UnimplementedSalesServiceServer) RefreshData(context.Context, *RefreshDataRequest) (*RefreshDataResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RefreshData not implemented")
+}
+func (UnimplementedSalesServiceServer) GetProduct(context.Context, *GetProductRequest) (*Product, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetProduct not implemented")
+}
+func (UnimplementedSalesServiceServer) GetCustomer(context.Context, *GetCustomerRequest) (*Customer, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCustomer not implemented")
+}
+func (UnimplementedSalesServiceServer) GetOrder(context.Context, *GetOrderRequest) (*OrderDetails, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedSalesServiceServer) StreamProducts(*StreamProductsRequest, grpc.ServerStreamingServer[Product]) error {
+	return status.Error(codes.Unimplemented, "method StreamProducts not implemented")
+}
+func (UnimplementedSalesServiceServer) StreamCustomers(*StreamCustomersRequest, grpc.ServerStreamingServer[Customer]) error {
+	return status.Error(codes.Unimplemented, "method StreamCustomers not implemented")
+}
+func (UnimplementedSalesServiceServer) StreamOrders(*StreamOrdersRequest, grpc.ServerStreamingServer[Order]) error {
+	return status.Error(codes.Unimplemented, "method StreamOrders not implemented")
+}
+func (UnimplementedSalesServiceServer) mustEmbedUnimplementedSalesServiceServer() {}
+func (UnimplementedSalesServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeSalesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SalesServiceServer will
+// result in compilation errors.
+type UnsafeSalesServiceServer interface {
+	mustEmbedUnimplementedSalesServiceServer()
+}
+
+func RegisterSalesServiceServer(s grpc.ServiceRegistrar, srv SalesServiceServer) {
+	// If the following call panics, it indicates UnimplementedSalesServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SalesService_ServiceDesc, srv)
+}
+
+func _SalesService_GetTopProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SalesServiceServer).GetTopProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SalesService_GetTopProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SalesServiceServer).GetTopProducts(ctx, req.(*GetTopProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SalesService_StreamTopProductsByGroup_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTopProductsByGroupRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SalesServiceServer).StreamTopProductsByGroup(m, &grpc.GenericServerStream[StreamTopProductsByGroupRequest, ProductGroup]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SalesService_StreamTopProductsByGroupServer = grpc.ServerStreamingServer[ProductGroup]
+
+func _SalesService_RefreshData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SalesServiceServer).RefreshData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SalesService_RefreshData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SalesServiceServer).RefreshData(ctx, req.(*RefreshDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SalesService_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SalesServiceServer).GetProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SalesService_GetProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SalesServiceServer).GetProduct(ctx, req.(*GetProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SalesService_GetCustomer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCustomerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SalesServiceServer).GetCustomer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SalesService_GetCustomer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SalesServiceServer).GetCustomer(ctx, req.(*GetCustomerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SalesService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SalesServiceServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SalesService_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SalesServiceServer).GetOrder(ctx, req.(*GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SalesService_StreamProducts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamProductsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SalesServiceServer).StreamProducts(m, &grpc.GenericServerStream[StreamProductsRequest, Product]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SalesService_StreamProductsServer = grpc.ServerStreamingServer[Product]
+
+func _SalesService_StreamCustomers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamCustomersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SalesServiceServer).StreamCustomers(m, &grpc.GenericServerStream[StreamCustomersRequest, Customer]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SalesService_StreamCustomersServer = grpc.ServerStreamingServer[Customer]
+
+func _SalesService_StreamOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamOrdersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SalesServiceServer).StreamOrders(m, &grpc.GenericServerStream[StreamOrdersRequest, Order]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SalesService_StreamOrdersServer = grpc.ServerStreamingServer[Order]
+
+// SalesService_ServiceDesc is the grpc.ServiceDesc for SalesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SalesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sales.v1.SalesService",
+	HandlerType: (*SalesServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTopProducts",
+			Handler:    _SalesService_GetTopProducts_Handler,
+		},
+		{
+			MethodName: "RefreshData",
+			Handler:    _SalesService_RefreshData_Handler,
+		},
+		{
+			MethodName: "GetProduct",
+			Handler:    _SalesService_GetProduct_Handler,
+		},
+		{
+			MethodName: "GetCustomer",
+			Handler:    _SalesService_GetCustomer_Handler,
+		},
+		{
+			MethodName: "GetOrder",
+			Handler:    _SalesService_GetOrder_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamTopProductsByGroup",
+			Handler:       _SalesService_StreamTopProductsByGroup_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamProducts",
+			Handler:       _SalesService_StreamProducts_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamCustomers",
+			Handler:       _SalesService_StreamCustomers_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamOrders",
+			Handler:       _SalesService_StreamOrders_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "sales/v1/sales.proto",
+}
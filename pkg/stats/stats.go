@@ -0,0 +1,95 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Bucket is one equal-width histogram bin covering [Lower, Upper); the last bin also includes Upper.
+type Bucket struct {
+	Lower float64
+	Upper float64
+	Count int
+}
+
+// Summary describes the distribution of a sample.
+type Summary struct {
+	Count     int
+	Min       float64
+	Max       float64
+	Mean      float64
+	P50       float64
+	P75       float64
+	P90       float64
+	P99       float64
+	Histogram []Bucket
+}
+
+// Summarize computes percentiles and an equal-width histogram with the given number of buckets.
+func Summarize(values []float64, buckets int) Summary {
+	if len(values) == 0 {
+		return Summary{Histogram: []Bucket{}}
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+
+	return Summary{
+		Count:     len(sorted),
+		Min:       sorted[0],
+		Max:       sorted[len(sorted)-1],
+		Mean:      sum / float64(len(sorted)),
+		P50:       Percentile(sorted, 50),
+		P75:       Percentile(sorted, 75),
+		P90:       Percentile(sorted, 90),
+		P99:       Percentile(sorted, 99),
+		Histogram: Histogram(sorted, buckets),
+	}
+}
+
+// Percentile returns the p-th percentile of an ascending sorted sample using linear interpolation.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+// Histogram splits an ascending sorted sample into equal-width buckets between its min and max.
+func Histogram(sorted []float64, buckets int) []Bucket {
+	if len(sorted) == 0 || buckets <= 0 {
+		return []Bucket{}
+	}
+
+	lo, hi := sorted[0], sorted[len(sorted)-1]
+	if lo == hi {
+		return []Bucket{{Lower: lo, Upper: hi, Count: len(sorted)}}
+	}
+
+	width := (hi - lo) / float64(buckets)
+	histogram := make([]Bucket, buckets)
+	for i := range histogram {
+		histogram[i] = Bucket{Lower: lo + float64(i)*width, Upper: lo + float64(i+1)*width}
+	}
+	histogram[buckets-1].Upper = hi
+
+	for _, v := range sorted {
+		i := int((v - lo) / width)
+		if i >= buckets {
+			i = buckets - 1
+		}
+		histogram[i].Count++
+	}
+	return histogram
+}
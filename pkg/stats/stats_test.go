@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+const tolerance = 1e-9
+
+func TestSummarize(t *testing.T) {
+	// unsorted on purpose: Summarize sorts a copy
+	values := []float64{7, 3, 10, 1, 5, 9, 2, 8, 4, 6}
+	summary := Summarize(values, 3)
+
+	for _, check := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"count", float64(summary.Count), 10},
+		{"min", summary.Min, 1},
+		{"max", summary.Max, 10},
+		{"mean", summary.Mean, 5.5},
+		{"p50", summary.P50, 5.5},
+		{"p75", summary.P75, 7.75},
+		{"p90", summary.P90, 9.1},
+		{"p99", summary.P99, 9.91},
+	} {
+		if math.Abs(check.got-check.want) > tolerance {
+			t.Errorf("%s: %v, want %v", check.name, check.got, check.want)
+		}
+	}
+	if values[0] != 7 {
+		t.Error("Summarize sorted the caller's slice")
+	}
+
+	want := []Bucket{{1, 4, 3}, {4, 7, 3}, {7, 10, 4}}
+	if len(summary.Histogram) != len(want) {
+		t.Fatalf("histogram %+v, want %+v", summary.Histogram, want)
+	}
+	for i, b := range summary.Histogram {
+		if math.Abs(b.Lower-want[i].Lower) > tolerance || math.Abs(b.Upper-want[i].Upper) > tolerance || b.Count != want[i].Count {
+			t.Errorf("bucket %d: %+v, want %+v", i, b, want[i])
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	for _, test := range []struct {
+		sorted []float64
+		p      float64
+		want   float64
+	}{
+		{nil, 50, 0},
+		{[]float64{4}, 99, 4},
+		{[]float64{1, 2}, 0, 1},
+		{[]float64{1, 2}, 100, 2},
+		{[]float64{1, 2}, 25, 1.25},
+		{[]float64{10, 20, 30}, 50, 20},
+	} {
+		if got := Percentile(test.sorted, test.p); math.Abs(got-test.want) > tolerance {
+			t.Errorf("p%v of %v: %v, want %v", test.p, test.sorted, got, test.want)
+		}
+	}
+}
+
+func TestHistogramEdgeCases(t *testing.T) {
+	if h := Summarize(nil, 5).Histogram; h == nil || len(h) != 0 {
+		t.Errorf("empty sample: %+v, want an empty histogram", h)
+	}
+	if h := Histogram([]float64{1, 2}, 0); len(h) != 0 {
+		t.Errorf("no buckets: %+v, want an empty histogram", h)
+	}
+	if h := Histogram([]float64{3, 3, 3}, 5); len(h) != 1 || h[0] != (Bucket{3, 3, 3}) {
+		t.Errorf("constant sample: %+v, want a single bucket holding every value", h)
+	}
+	// the maximum falls into the last bucket rather than past it
+	if h := Histogram([]float64{0, 1, 2, 3, 4}, 2); h[0].Count != 2 || h[1].Count != 3 {
+		t.Errorf("upper bound: %+v, want 2 and 3 values", h)
+	}
+}